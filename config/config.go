@@ -2,10 +2,20 @@ package config
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/opensourceways/app-cla-server/util"
 )
 
+const (
+	DBMongodb    = "mongodb"
+	DBPostgresql = "postgresql"
+	DBMemory     = "memory"
+
+	FileStorageOBS    = "obs"
+	FileStorageGridFS = "gridfs"
+)
+
 var AppConfig = &appConfig{}
 
 func InitAppConfig(path string) error {
@@ -20,25 +30,108 @@ func InitAppConfig(path string) error {
 }
 
 type appConfig struct {
-	PythonBin                string        `json:"python_bin" required:"true"`
-	CLAFieldsNumber          int           `json:"cla_fields_number" required:"true"`
-	MaxSizeOfCorpCLAPDF      int           `json:"max_size_of_corp_cla_pdf"`
-	MaxSizeOfOrgSignaturePDF int           `json:"max_size_of_org_signature_pdf"`
-	MinLengthOfPassword      int           `json:"min_length_of_password"`
-	MaxLengthOfPassword      int           `json:"max_length_of_password"`
-	VerificationCodeExpiry   int64         `json:"verification_code_expiry" required:"true"`
-	APITokenExpiry           int64         `json:"api_token_expiry" required:"true"`
-	APITokenKey              string        `json:"api_token_key" required:"true"`
-	SymmetricEncryptionKey   string        `json:"symmetric_encryption_key" required:"true"`
-	SymmetricEncryptionNonce string        `json:"symmetric_encryption_nonce" required:"true"`
-	PDFOrgSignatureDir       string        `json:"pdf_org_signature_dir" required:"true"`
-	PDFOutDir                string        `json:"pdf_out_dir" required:"true"`
-	CodePlatformConfigFile   string        `json:"code_platforms" required:"true"`
-	EmailPlatformConfigFile  string        `json:"email_platforms" required:"true"`
-	EmployeeManagersNumber   int           `json:"employee_managers_number" required:"true"`
-	CLAPlatformURL           string        `json:"cla_platform_url" required:"true"`
-	Mongodb                  MongodbConfig `json:"mongodb" required:"true"`
-	OBS                      OBS           `json:"obs" required:"true"`
+	PythonBin                string           `json:"python_bin"`
+	CLAFieldsNumber          int              `json:"cla_fields_number" required:"true"`
+	MaxSizeOfCorpCLAPDF      int              `json:"max_size_of_corp_cla_pdf"`
+	MaxSizeOfOrgSignaturePDF int              `json:"max_size_of_org_signature_pdf"`
+	MaxSizeOfCLAText         int              `json:"max_size_of_cla_text"`
+	MaxSizeOfCorpPDFTemplate int              `json:"max_size_of_corp_pdf_template"`
+	MaxSizeOfCorpPDFLogo     int              `json:"max_size_of_corp_pdf_logo"`
+	MaxSizeOfESignature      int              `json:"max_size_of_esignature"`
+	MinLengthOfPassword      int              `json:"min_length_of_password"`
+	MaxLengthOfPassword      int              `json:"max_length_of_password"`
+	VerificationCodeExpiry   int64            `json:"verification_code_expiry" required:"true"`
+	APITokenExpiry           int64            `json:"api_token_expiry" required:"true"`
+	APITokenKey              string           `json:"api_token_key" required:"true"`
+	SymmetricEncryptionKey   string           `json:"symmetric_encryption_key" required:"true"`
+	SymmetricEncryptionNonce string           `json:"symmetric_encryption_nonce" required:"true"`
+	PDFOrgSignatureDir       string           `json:"pdf_org_signature_dir" required:"true"`
+	PDFOutDir                string           `json:"pdf_out_dir" required:"true"`
+	PDFCorpLangDir           string           `json:"pdf_corp_lang_dir"`
+	CodePlatformConfigFile   string           `json:"code_platforms" required:"true"`
+	EmailPlatformConfigFile  string           `json:"email_platforms" required:"true"`
+	EmployeeManagersNumber   int              `json:"employee_managers_number" required:"true"`
+	DeletedSigningRetention  int64            `json:"deleted_signing_retention"`
+	CLAPlatformURL           string           `json:"cla_platform_url" required:"true"`
+	AgreementVerificationURL string           `json:"agreement_verification_url"`
+	DB                       string           `json:"db"`
+	FileStorage              string           `json:"file_storage"`
+	EncryptionKeys           []EncryptionKey  `json:"encryption_keys"`
+	EncryptionKeyID          string           `json:"encryption_key_id"`
+	Mongodb                  MongodbConfig    `json:"mongodb"`
+	Postgresql               PostgresqlConfig `json:"postgresql"`
+	OBS                      OBS              `json:"obs"`
+	CLADownload              CLADownload      `json:"cla_download"`
+	CLADrift                 CLADrift         `json:"cla_drift"`
+	PDFSigning               PDFSigning       `json:"pdf_signing"`
+}
+
+// PDFSigning configures the digital signature of the corporation signing pdf.
+// The signing is disabled if CertFile is empty.
+type PDFSigning struct {
+	// CertFile is the PEM file of the certificate of signer, which can be followed by its chain.
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+}
+
+func (cfg *PDFSigning) validate() error {
+	if cfg.CertFile == "" && cfg.KeyFile == "" {
+		return nil
+	}
+
+	if util.IsFileNotExist(cfg.CertFile) {
+		return fmt.Errorf("The file:%s is not exist", cfg.CertFile)
+	}
+
+	if util.IsFileNotExist(cfg.KeyFile) {
+		return fmt.Errorf("The file:%s is not exist", cfg.KeyFile)
+	}
+
+	return nil
+}
+
+// CLADrift configures the check on whether the content of cla url differs from the cla text.
+type CLADrift struct {
+	// Interval is in seconds. The check is disabled if it is not positive.
+	Interval int64 `json:"interval"`
+	// SaveDraft means the changed content is saved as the draft of new version.
+	SaveDraft bool `json:"save_draft"`
+}
+
+// CLADownload restricts the url from which the cla text is downloaded.
+type CLADownload struct {
+	// AllowedHosts are the hosts which the url can be of, including the ones
+	// redirected to. Any public host is allowed if it is empty.
+	AllowedHosts []string `json:"allowed_hosts"`
+	MaxRedirects int      `json:"max_redirects"`
+	Timeout      int      `json:"timeout"`
+}
+
+func (cfg *CLADownload) setDefault() {
+	if cfg.MaxRedirects <= 0 {
+		cfg.MaxRedirects = 3
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10
+	}
+}
+
+// EncryptionKey is a key to encrypt the data saved in db. The ciphertexts
+// encrypted by it are prefixed with its id, so that it can be rotated.
+type EncryptionKey struct {
+	ID  string `json:"id" required:"true"`
+	Key string `json:"key" required:"true"`
+}
+
+// DBEncryptionKeys returns all the keys to decrypt the data saved in db and the id of
+// the one to encrypt the new data. The symmetric_encryption_key is included with the empty id,
+// because it encrypted the data without key id before the keys could be rotated.
+func (cfg *appConfig) DBEncryptionKeys() ([]EncryptionKey, string) {
+	keys := make([]EncryptionKey, 0, len(cfg.EncryptionKeys)+1)
+	keys = append(keys, EncryptionKey{Key: cfg.SymmetricEncryptionKey})
+
+	return append(keys, cfg.EncryptionKeys...), cfg.EncryptionKeyID
 }
 
 type MongodbConfig struct {
@@ -50,6 +143,59 @@ type MongodbConfig struct {
 	VCCollection                string `json:"verification_code_collection" required:"true"`
 	CorpSigningCollection       string `json:"corp_signing_collection" required:"true"`
 	IndividualSigningCollection string `json:"individual_signing_collection" required:"true"`
+
+	// The collections below save each signing or manager as a single doc.
+	CorpSigningRecordCollection       string `json:"corp_signing_record_collection"`
+	CorpManagerCollection             string `json:"corp_manager_collection"`
+	IndividualSigningRecordCollection string `json:"individual_signing_record_collection"`
+
+	// EmailIndexKey is the key to generate the blind index of email,
+	// which is used to look up the email encrypted with a random nonce.
+	EmailIndexKey string `json:"email_index_key" required:"true"`
+
+	// SchemaVersionCollection saves the version of data layout.
+	SchemaVersionCollection string `json:"schema_version_collection"`
+
+	// SkipMigration means the migrations will not run at startup,
+	// and they should be run by tools/migrate-db.
+	SkipMigration bool `json:"skip_migration"`
+
+	// AuditLogCollection saves the audit logs which are only appended.
+	AuditLogCollection string `json:"audit_log_collection"`
+
+	// ResignCampaignCollection saves each signer of re-signing campaign as a single doc.
+	ResignCampaignCollection string `json:"resign_campaign_collection"`
+}
+
+func (cfg *MongodbConfig) setDefault() {
+	if cfg.CorpSigningRecordCollection == "" {
+		cfg.CorpSigningRecordCollection = "corp_signing_records"
+	}
+
+	if cfg.CorpManagerCollection == "" {
+		cfg.CorpManagerCollection = "corp_managers"
+	}
+
+	if cfg.IndividualSigningRecordCollection == "" {
+		cfg.IndividualSigningRecordCollection = "individual_signing_records"
+	}
+
+	if cfg.SchemaVersionCollection == "" {
+		cfg.SchemaVersionCollection = "schema_version"
+	}
+
+	if cfg.AuditLogCollection == "" {
+		cfg.AuditLogCollection = "audit_logs"
+	}
+
+	if cfg.ResignCampaignCollection == "" {
+		cfg.ResignCampaignCollection = "resign_campaigns"
+	}
+}
+
+type PostgresqlConfig struct {
+	Conn         string `json:"postgresql_conn" required:"true"`
+	MaxOpenConns int    `json:"max_open_conns"`
 }
 
 type OBS struct {
@@ -65,6 +211,18 @@ func (cfg *appConfig) setDefault() {
 	if cfg.MaxSizeOfOrgSignaturePDF <= 0 {
 		cfg.MaxSizeOfOrgSignaturePDF = (1 << 20)
 	}
+	if cfg.MaxSizeOfCLAText <= 0 {
+		cfg.MaxSizeOfCLAText = (1 << 20)
+	}
+	if cfg.MaxSizeOfCorpPDFTemplate <= 0 {
+		cfg.MaxSizeOfCorpPDFTemplate = (64 << 10)
+	}
+	if cfg.MaxSizeOfCorpPDFLogo <= 0 {
+		cfg.MaxSizeOfCorpPDFLogo = (512 << 10)
+	}
+	if cfg.MaxSizeOfESignature <= 0 {
+		cfg.MaxSizeOfESignature = (256 << 10)
+	}
 
 	if cfg.MinLengthOfPassword <= 0 {
 		cfg.MinLengthOfPassword = 6
@@ -73,10 +231,34 @@ func (cfg *appConfig) setDefault() {
 	if cfg.MaxLengthOfPassword <= 0 {
 		cfg.MaxLengthOfPassword = 16
 	}
+
+	if cfg.DeletedSigningRetention <= 0 {
+		// 30 days
+		cfg.DeletedSigningRetention = 30 * 24 * 3600
+	}
+
+	if cfg.PDFCorpLangDir == "" {
+		cfg.PDFCorpLangDir = "./conf/pdf_template_corporation"
+	}
+
+	if cfg.AgreementVerificationURL == "" {
+		cfg.AgreementVerificationURL = strings.TrimSuffix(cfg.CLAPlatformURL, "/") + "/api/v1/agreement/%s"
+	}
+
+	if cfg.DB == "" {
+		cfg.DB = DBMongodb
+	}
+
+	if cfg.FileStorage == "" {
+		cfg.FileStorage = FileStorageOBS
+	}
+
+	cfg.Mongodb.setDefault()
+	cfg.CLADownload.setDefault()
 }
 
 func (cfg *appConfig) validate() error {
-	if util.IsFileNotExist(cfg.PythonBin) {
+	if cfg.PythonBin != "" && util.IsFileNotExist(cfg.PythonBin) {
 		return fmt.Errorf("The file:%s is not exist", cfg.PythonBin)
 	}
 
@@ -104,6 +286,10 @@ func (cfg *appConfig) validate() error {
 		return fmt.Errorf("The symmetric encryption key or nonce is invalid, %s", err.Error())
 	}
 
+	if err := cfg.validateEncryptionKeys(); err != nil {
+		return err
+	}
+
 	if util.IsNotDir(cfg.PDFOrgSignatureDir) {
 		return fmt.Errorf("The directory:%s is not exist", cfg.PDFOrgSignatureDir)
 	}
@@ -112,6 +298,14 @@ func (cfg *appConfig) validate() error {
 		return fmt.Errorf("The directory:%s is not exist", cfg.PDFOutDir)
 	}
 
+	if err := cfg.PDFSigning.validate(); err != nil {
+		return err
+	}
+
+	if strings.Count(cfg.AgreementVerificationURL, "%s") != 1 || strings.Count(cfg.AgreementVerificationURL, "%") != 1 {
+		return fmt.Errorf("The agreement_verification_url must contain one %%s")
+	}
+
 	if util.IsFileNotExist(cfg.CodePlatformConfigFile) {
 		return fmt.Errorf("The file:%s is not exist", cfg.CodePlatformConfigFile)
 	}
@@ -120,5 +314,73 @@ func (cfg *appConfig) validate() error {
 		return fmt.Errorf("The file:%s is not exist", cfg.EmailPlatformConfigFile)
 	}
 
+	return cfg.validateDB()
+}
+
+func (cfg *appConfig) validateEncryptionKeys() error {
+	ids := map[string]bool{}
+	for i := range cfg.EncryptionKeys {
+		item := &cfg.EncryptionKeys[i]
+
+		if item.ID == "" || strings.Contains(item.ID, "$") {
+			return fmt.Errorf("The id:%s of encryption key should not be empty or contain '$'", item.ID)
+		}
+
+		if ids[item.ID] {
+			return fmt.Errorf("The id:%s of encryption key is duplicate", item.ID)
+		}
+		ids[item.ID] = true
+
+		if _, err := util.NewSymmetricEncryption(item.Key, ""); err != nil {
+			return fmt.Errorf("The encryption key:%s is invalid, %s", item.ID, err.Error())
+		}
+	}
+
+	if cfg.EncryptionKeyID != "" && !ids[cfg.EncryptionKeyID] {
+		return fmt.Errorf("The encryption_key_id:%s is not in encryption_keys", cfg.EncryptionKeyID)
+	}
+
+	return nil
+}
+
+func (cfg *appConfig) validateDB() error {
+	switch cfg.DB {
+	case DBMongodb:
+		if cfg.Mongodb.MongodbConn == "" {
+			return fmt.Errorf("The mongodb config is missing")
+		}
+
+		if len(cfg.Mongodb.EmailIndexKey) < 32 {
+			return fmt.Errorf("The length of email_index_key should not be less than 32")
+		}
+
+	case DBPostgresql:
+		if cfg.Postgresql.Conn == "" {
+			return fmt.Errorf("The postgresql config is missing")
+		}
+
+	case DBMemory:
+		// the memory db saves the files too.
+		return nil
+
+	default:
+		return fmt.Errorf("The db:%s is not supported", cfg.DB)
+	}
+
+	switch cfg.FileStorage {
+	case FileStorageOBS:
+		if cfg.OBS.Name == "" {
+			return fmt.Errorf("The obs config is missing")
+		}
+
+	case FileStorageGridFS:
+		if cfg.DB != DBMongodb {
+			return fmt.Errorf("The file_storage:%s can only work with mongodb", cfg.FileStorage)
+		}
+
+	default:
+		return fmt.Errorf("The file_storage:%s is not supported", cfg.FileStorage)
+	}
+
 	return nil
 }
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"github.com/opensourceways/app-cla-server/models"
+)
+
+type AgreementController struct {
+	baseController
+}
+
+func (this *AgreementController) Prepare() {
+	// anyone who has the pdf can verify the agreement by the QR code on it.
+	this.apiPrepare("")
+}
+
+// @Title Get
+// @Description verify the corporation signing by the agreement id printed on the pdf. It doesn't expose any email.
+// @Param	agreement_id	path 	string	true		"agreement id"
+// @Success 200 {object} dbmodels.CorpAgreement
+// @Failure 400 no_agreement: there is no signing of the agreement id
+// @router /:agreement_id [get]
+func (this *AgreementController) Get() {
+	action := "verify corp agreement"
+
+	v, merr := models.GetCorpAgreement(this.GetString(":agreement_id"))
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	type agreementInfo struct {
+		*models.CorpAgreement
+
+		OrgAlias   string `json:"org_alias,omitempty"`
+		ProjectURL string `json:"project_url,omitempty"`
+	}
+
+	r := agreementInfo{CorpAgreement: v}
+
+	// the org of the deleted link is unavailable, but the agreement is still shown.
+	if !v.Deleted {
+		orgInfo, merr := models.GetOrgOfLink(v.LinkID)
+		if merr != nil {
+			if !merr.IsErrorOf(models.ErrNoLink) {
+				this.sendModelErrorAsResp(merr, action)
+				return
+			}
+			r.Deleted = true
+		} else {
+			r.OrgAlias = orgInfo.OrgAlias
+			r.ProjectURL = orgInfo.ProjectURL()
+		}
+	}
+
+	this.sendSuccessResp(r)
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/astaxie/beego"
+
+	"github.com/opensourceways/app-cla-server/models"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+type AuditLogController struct {
+	baseController
+}
+
+func (this *AuditLogController) Prepare() {
+	if strings.HasSuffix(this.routerPattern(), "/") {
+		// corp admin reads the logs of corporation
+		this.apiPrepare(PermissionCorpAdmin)
+	} else {
+		this.apiPrepare(PermissionOwnerOfOrg)
+	}
+}
+
+// @Title List
+// @Description list the audit logs of link
+// @Param	:link_id	path 	string		true		"link id"
+// @Success 200 {object} dbmodels.AuditLog
+// @router /:link_id [get]
+func (this *AuditLogController) List() {
+	action := "list audit logs"
+	linkID := this.GetString(":link_id")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	r, merr := models.ListAuditLogs(linkID, "")
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	this.sendSuccessResp(r)
+}
+
+// @Title ListOfCorp
+// @Description corp admin lists the audit logs of corporation
+// @Success 200 {object} dbmodels.AuditLog
+// @router / [get]
+func (this *AuditLogController) ListOfCorp() {
+	action := "list audit logs of corp"
+
+	pl, fr := this.tokenPayloadBasedOnCorpManager()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	r, merr := models.ListAuditLogs(pl.LinkID, pl.Email)
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	this.sendSuccessResp(r)
+}
+
+// addAuditLog records the action done by the user of token. It should be called
+// after the action succeeds. corpEmail is any email of the corporation which
+// the target belongs to, and it is empty if the target is not in a corporation.
+func (this *baseController) addAuditLog(action, linkID, corpEmail, target string) {
+	actor := ""
+	if ac, fr := this.getAccessController(); fr == nil {
+		switch pl := ac.Payload.(type) {
+		case *acForCodePlatformPayload:
+			actor = pl.userID()
+		case *acForCorpManagerPayload:
+			actor = pl.Email
+		}
+	}
+
+	this.addAuditLogOfActor(actor, action, linkID, corpEmail, target)
+}
+
+// addAuditLogOfActor records the action done without login, such as signing,
+// in which case the actor is the signer.
+func (this *baseController) addAuditLogOfActor(actor, action, linkID, corpEmail, target string) {
+	addr, fr := this.getRemoteAddr()
+	if fr != nil {
+		addr = this.Ctx.Input.IP()
+	}
+
+	log := models.AuditLog{
+		Actor:      actor,
+		RemoteAddr: addr,
+		Action:     action,
+		LinkID:     linkID,
+		Target:     target,
+	}
+	if corpEmail != "" {
+		log.CorpID = util.EmailSuffix(corpEmail)
+	}
+
+	// the action has been done, so the failure is only logged.
+	if merr := models.AddAuditLog(&log); merr != nil {
+		beego.Error(fmt.Sprintf("Failed to add audit log of %s, err: %s", action, merr.Error()))
+	}
+}
@@ -254,6 +254,11 @@ type acForCodePlatformPayload struct {
 	Links map[string]models.OrgInfo `json:"links"`
 }
 
+// userID identifies the user among all the code platforms.
+func (this *acForCodePlatformPayload) userID() string {
+	return fmt.Sprintf("%s/%s", this.Platform, this.User)
+}
+
 func (this *acForCodePlatformPayload) orgInfo(linkID string) *models.OrgInfo {
 	if this.Links == nil {
 		return nil
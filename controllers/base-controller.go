@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -21,6 +22,10 @@ type failedApiResult struct {
 	reason     error
 	errCode    string
 	statusCode int
+
+	// invalidFields is the error code of each invalid field of signing info,
+	// or of each unmatched item of the uploaded pdf.
+	invalidFields map[string]string
 }
 
 func newFailedApiResult(statusCode int, errCode string, err error) *failedApiResult {
@@ -61,7 +66,7 @@ func (this *baseController) sendSuccessResp(body interface{}) {
 
 func (this *baseController) newFuncForSendingFailedResp(action string) func(fr *failedApiResult) {
 	return func(fr *failedApiResult) {
-		this.sendFailedResponse(fr.statusCode, fr.errCode, fr.reason, action)
+		this.sendFailedResultAsResp(fr, action)
 	}
 }
 
@@ -70,10 +75,14 @@ func (this *baseController) sendModelErrorAsResp(err models.IModelError, action
 }
 
 func (this *baseController) sendFailedResultAsResp(fr *failedApiResult, action string) {
-	this.sendFailedResponse(fr.statusCode, fr.errCode, fr.reason, action)
+	this.sendFailedResponseWithFields(fr.statusCode, fr.errCode, fr.reason, fr.invalidFields, action)
 }
 
 func (this *baseController) sendFailedResponse(statusCode int, errCode string, reason error, action string) {
+	this.sendFailedResponseWithFields(statusCode, errCode, reason, nil, action)
+}
+
+func (this *baseController) sendFailedResponseWithFields(statusCode int, errCode string, reason error, invalidFields map[string]string, action string) {
 	if statusCode >= 500 {
 		beego.Error(fmt.Sprintf("Failed to %s, errCode: %s, err: %s", action, errCode, reason.Error()))
 
@@ -82,11 +91,13 @@ func (this *baseController) sendFailedResponse(statusCode int, errCode string, r
 	}
 
 	d := struct {
-		ErrCode string `json:"error_code"`
-		ErrMsg  string `json:"error_message"`
+		ErrCode       string            `json:"error_code"`
+		ErrMsg        string            `json:"error_message"`
+		InvalidFields map[string]string `json:"invalid_fields,omitempty"`
 	}{
-		ErrCode: fmt.Sprintf("cla.%s", errCode),
-		ErrMsg:  reason.Error(),
+		ErrCode:       fmt.Sprintf("cla.%s", errCode),
+		ErrMsg:        reason.Error(),
+		InvalidFields: invalidFields,
 	}
 
 	this.sendResponse(d, statusCode)
@@ -296,6 +307,46 @@ func (this *baseController) readInputFile(fileName string, maxSize int) ([]byte,
 	return data, nil
 }
 
+// readCLAFile returns nil if the cla file is not uploaded, in which case
+// the cla should be downloaded from the url.
+func (this *baseController) readCLAFile(fileName string) (*[]byte, *failedApiResult) {
+	data, fr := this.readOptionalFile(fileName, config.AppConfig.MaxSizeOfCLAText)
+	if fr != nil {
+		if fr.errCode == errTooBigFile {
+			fr.errCode = errTooBigCLAFile
+		}
+		return nil, fr
+	}
+
+	if data == nil {
+		return nil, nil
+	}
+	return &data, nil
+}
+
+// readOptionalFile returns nil if the file is not uploaded.
+func (this *baseController) readOptionalFile(fileName string, maxSize int) ([]byte, *failedApiResult) {
+	f, _, err := this.GetFile(fileName)
+	if err != nil {
+		if err == http.ErrMissingFile {
+			return nil, nil
+		}
+		return nil, newFailedApiResult(400, errReadingFile, err)
+	}
+	defer f.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(f, int64(maxSize)+1))
+	if err != nil {
+		return nil, newFailedApiResult(500, errSystemError, err)
+	}
+
+	if len(data) > maxSize {
+		return nil, newFailedApiResult(400, errTooBigFile, fmt.Errorf("big file: %s", fileName))
+	}
+
+	return data, nil
+}
+
 func (this *baseController) downloadFile(file string) {
 	output := this.Ctx.Output
 
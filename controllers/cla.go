@@ -19,8 +19,8 @@ func (this *CLAController) Prepare() {
 	this.apiPrepare(PermissionOwnerOfOrg)
 }
 
-// @Title Link
-// @Description link org and cla
+// @Title Add
+// @Description add cla. The cla text is uploaded as cla_file, or downloaded from the url if it is not uploaded
 // @Param	body		body 	models.OrgCLA	true		"body for org-repo content"
 // @Success 201 {int} models.OrgCLA
 // @Failure 403 body is empty
@@ -57,6 +57,15 @@ func (this *CLAController) Add() {
 		input.SetOrgSignature(&data)
 	}
 
+	text, fr := this.readCLAFile(fileNameOfUploadingCLA)
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if text != nil {
+		input.SetCLAContent(text)
+	}
+
 	if merr := input.Validate(applyTo, pdf.GetPDFGenerator().LangSupported()); merr != nil {
 		this.sendModelErrorAsResp(merr, action)
 		return
@@ -75,6 +84,59 @@ func (this *CLAController) Add() {
 	}
 
 	this.sendSuccessResp("add cla successfully")
+	this.addAuditLog(action, linkID, "", fmt.Sprintf("%s/%s", applyTo, input.Language))
+}
+
+// @Title PublishDraft
+// @Description publish the draft saved when the url of cla was found changed as the new version
+// @Param	link_id		path 	string	true		"link id"
+// @Param	apply_to	path 	string	true		"apply to"
+// @Param	language	path 	string	true		"cla language"
+// @Param	policy		query 	string	false		"policy of the new version"
+// @Success 201 {string} publish successfully
+// @Failure 400 no_cla_draft: there is not draft of the cla
+// @router /:link_id/:apply_to/:language/draft [post]
+func (this *CLAController) PublishDraft() {
+	action := "publish cla draft"
+	linkID := this.GetString(":link_id")
+	applyTo := this.GetString(":apply_to")
+	claLang := this.GetString(":language")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	input, merr := models.NewCLAFromDraft(linkID, applyTo, claLang, this.GetString("policy"))
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	if merr := input.Validate(applyTo, pdf.GetPDFGenerator().LangSupported()); merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	unlock, fr := lockOnRepo(pl.orgInfo(linkID))
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	defer unlock()
+
+	if fr := addCLA(linkID, applyTo, input); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	this.sendSuccessResp("publish cla draft successfully")
+	this.addAuditLog(action, linkID, "", fmt.Sprintf("%s/%s", applyTo, claLang))
 }
 
 // @Title Delete CLA
@@ -112,10 +174,11 @@ func (this *CLAController) Delete() {
 	}
 
 	this.sendSuccessResp("delete cla successfully")
+	this.addAuditLog(action, linkID, "", fmt.Sprintf("%s/%s", applyTo, claLang))
 }
 
 // @Title List
-// @Description list clas of link
+// @Description list clas of link, including the drift between the url and the text of each cla
 // @Param	link_id		path 	string	true		"link id"
 // @Success 200 {string} delete success!
 // @Failure 403 uid is empty
@@ -143,17 +206,24 @@ func (this *CLAController) List() {
 	this.sendSuccessResp(clas)
 }
 
+// addCLA adds the cla as a new version if there is one of the same language.
 func addCLA(linkID, applyTo string, input *models.CLACreateOpt) *failedApiResult {
-	hasCLA, merr := models.HasCLA(linkID, applyTo, input.Language)
+	versions, merr := models.ListCLAVersions(linkID, applyTo, input.Language)
 	if merr != nil {
 		return parseModelError(merr)
 	}
-	if hasCLA {
-		return newFailedApiResult(400, errCLAExists, fmt.Errorf("recreate cla"))
+
+	claHash := input.CLAHash()
+	for i := range versions {
+		if versions[i].CLAHash == claHash {
+			return newFailedApiResult(400, errCLAExists, fmt.Errorf("recreate cla"))
+		}
 	}
 
-	if merr := models.DeleteCLAInfo(linkID, applyTo, input.Language); merr != nil {
-		return parseModelError(merr)
+	if len(versions) == 0 {
+		if merr := models.DeleteCLAInfo(linkID, applyTo, input.Language); merr != nil {
+			return parseModelError(merr)
+		}
 	}
 
 	if applyTo == dbmodels.ApplyToCorporation {
@@ -182,6 +252,12 @@ func deleteCLA(linkID, applyTo, claLang string) *failedApiResult {
 		return newFailedApiResult(400, errCLAIsUsed, fmt.Errorf("cla is used"))
 	}
 
+	// the versions are listed before being deleted, so that their local files can be removed.
+	versions, merr := models.ListCLAVersions(linkID, applyTo, claLang)
+	if merr != nil {
+		return parseModelError(merr)
+	}
+
 	if merr := models.DeleteCLA(linkID, applyTo, claLang); merr != nil {
 		return parseModelError(merr)
 	}
@@ -189,14 +265,18 @@ func deleteCLA(linkID, applyTo, claLang string) *failedApiResult {
 	models.DeleteCLAInfo(linkID, applyTo, claLang)
 
 	if applyTo == dbmodels.ApplyToCorporation {
-		path := genCLAFilePath(linkID, applyTo, claLang)
-		if !util.IsFileNotExist(path) {
-			os.Remove(path)
-		}
-
-		path = genOrgSignatureFilePath(linkID, claLang)
-		if !util.IsFileNotExist(path) {
-			os.Remove(path)
+		for i := range versions {
+			claHash := versions[i].CLAHash
+
+			path := genCLAFilePath(linkID, applyTo, claLang, claHash)
+			if !util.IsFileNotExist(path) {
+				os.Remove(path)
+			}
+
+			path = genOrgSignatureFilePath(linkID, claLang, claHash)
+			if !util.IsFileNotExist(path) {
+				os.Remove(path)
+			}
 		}
 	}
 	return nil
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/opensourceways/app-cla-server/config"
+	"github.com/opensourceways/app-cla-server/models"
+	"github.com/opensourceways/app-cla-server/pdf"
+)
+
+// @Title SaveTemplate
+// @Description save the templates of corporation signing pdf of the language
+// @Param	link_id			path 	string	true		"link id"
+// @Param	language		path 	string	true		"cla language"
+// @Param	welcome_file		formData 	file	true		"the template of welcome"
+// @Param	declaration_file	formData 	file	true		"the template of declaration"
+// @Param	logo_file		formData 	file	false		"the logo of png or jpeg"
+// @Success 201 {string} save successfully
+// @Failure 400 invalid_pdf_template: the template can't be parsed or rendered
+// @Failure 400 invalid_logo: the logo is not png or jpeg image
+// @router /template/:link_id/:language [put]
+func (this *CorporationPDFController) SaveTemplate() {
+	action := "save corp pdf template"
+	linkID := this.GetString(":link_id")
+	claLang := this.GetString(":language")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	tmpl, fr := this.readCorpPDFTemplate(claLang)
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	// make sure the templates can be rendered before saving them.
+	outFile, fr := genBlankCorpPDF(linkID, claLang, tmpl)
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	os.Remove(outFile)
+
+	if merr := models.SaveCorpPDFTemplate(linkID, tmpl); merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	this.sendSuccessResp("save corp pdf template successfully")
+	this.addAuditLog(action, linkID, "", claLang)
+}
+
+// @Title SampleOfTemplate
+// @Description render the uploaded templates to a blank pdf without saving them
+// @Param	link_id			path 	string	true		"link id"
+// @Param	language		path 	string	true		"cla language"
+// @Param	welcome_file		formData 	file	true		"the template of welcome"
+// @Param	declaration_file	formData 	file	true		"the template of declaration"
+// @Param	logo_file		formData 	file	false		"the logo of png or jpeg"
+// @Success 200 {file} the sample pdf
+// @Failure 400 invalid_pdf_template: the template can't be parsed or rendered
+// @router /template/:link_id/:language/sample [post]
+func (this *CorporationPDFController) SampleOfTemplate() {
+	action := "sample of corp pdf template"
+	linkID := this.GetString(":link_id")
+	claLang := this.GetString(":language")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	tmpl, fr := this.readCorpPDFTemplate(claLang)
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	outFile, fr := genBlankCorpPDF(linkID, claLang, tmpl)
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	defer func() { os.Remove(outFile) }()
+	this.downloadFile(outFile)
+}
+
+// @Title GetTemplate
+// @Description get the templates of corporation signing pdf of the language
+// @Param	link_id		path 	string	true		"link id"
+// @Param	language	path 	string	true		"cla language"
+// @Success 200 {object} models.CorpPDFTemplate
+// @router /template/:link_id/:language [get]
+func (this *CorporationPDFController) GetTemplate() {
+	action := "get corp pdf template"
+	linkID := this.GetString(":link_id")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	v, merr := models.GetCorpPDFTemplate(linkID, this.GetString(":language"))
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	this.sendSuccessResp(v)
+}
+
+// @Title DeleteTemplate
+// @Description delete the templates of the language, then the default ones will be used
+// @Param	link_id		path 	string	true		"link id"
+// @Param	language	path 	string	true		"cla language"
+// @Success 204 {string} delete successfully
+// @router /template/:link_id/:language [delete]
+func (this *CorporationPDFController) DeleteTemplate() {
+	action := "delete corp pdf template"
+	linkID := this.GetString(":link_id")
+	claLang := this.GetString(":language")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	if merr := models.DeleteCorpPDFTemplate(linkID, claLang); merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	this.sendSuccessResp("delete corp pdf template successfully")
+	this.addAuditLog(action, linkID, "", claLang)
+}
+
+func (this *CorporationPDFController) readCorpPDFTemplate(claLang string) (*models.CorpPDFTemplate, *failedApiResult) {
+	opt := models.CorpPDFTemplateCreateOpt{}
+
+	files := map[string]*[]byte{
+		fileNameOfUploadingWelcome:     &opt.Welcome,
+		fileNameOfUploadingDeclaration: &opt.Declaration,
+	}
+	for k, v := range files {
+		data, fr := this.readOptionalFile(k, config.AppConfig.MaxSizeOfCorpPDFTemplate)
+		if fr != nil {
+			return nil, fr
+		}
+		if data == nil {
+			return nil, newFailedApiResult(400, errMissingFile, fmt.Errorf("missing %s", k))
+		}
+		*v = data
+	}
+
+	logo, fr := this.readOptionalFile(fileNameOfUploadingLogo, config.AppConfig.MaxSizeOfCorpPDFLogo)
+	if fr != nil {
+		return nil, fr
+	}
+	opt.Logo = logo
+
+	tmpl, merr := opt.Validate(claLang, pdf.GetPDFGenerator().LangSupported())
+	if merr != nil {
+		return nil, parseModelError(merr)
+	}
+	return tmpl, nil
+}
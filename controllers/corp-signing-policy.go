@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"github.com/opensourceways/app-cla-server/models"
+)
+
+// @Title GetCorpSigningPolicy
+// @Description get the policy of corporation signing
+// @Param	link_id		path 	string	true		"link id"
+// @Success 200 {object} models.CorpSigningPolicy
+// @router /:link_id/corp-signing-policy [get]
+func (this *LinkController) GetCorpSigningPolicy() {
+	action := "get corp signing policy"
+	linkID := this.GetString(":link_id")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	v, merr := models.GetCorpSigningPolicy(linkID)
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	this.sendSuccessResp(v)
+}
+
+// @Title UpdateCorpSigningPolicy
+// @Description update the policy of corporation signing, such as whether the corporation can sign electronically
+// @Param	link_id		path 	string				true		"link id"
+// @Param	body		body 	models.CorpSigningPolicy	true		"the policy"
+// @Success 202 {string} update successfully
+// @router /:link_id/corp-signing-policy [put]
+func (this *LinkController) UpdateCorpSigningPolicy() {
+	action := "update corp signing policy"
+	linkID := this.GetString(":link_id")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	var p models.CorpSigningPolicy
+	if fr := this.fetchInputPayload(&p); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	if merr := models.UpdateCorpSigningPolicy(linkID, &p); merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	this.sendSuccessResp("update corp signing policy successfully")
+	this.addAuditLog(action, linkID, "", linkID)
+}
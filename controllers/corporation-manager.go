@@ -33,6 +33,7 @@ func (this *CorporationManagerController) Prepare() {
 // @Success 202 {int} map
 // @Failure util.ErrPDFHasNotUploaded
 // @Failure util.ErrNumOfCorpManagersExceeded
+// @Failure 400 scanned_pdf_required: the pdf is signed electronically, but the scanned one is required
 // @router /:link_id/:email [put]
 func (this *CorporationManagerController) Put() {
 	action := "add corp administrator"
@@ -78,6 +79,20 @@ func (this *CorporationManagerController) Put() {
 		return
 	}
 
+	if corpSigning.ESigned {
+		policy, merr := models.GetCorpSigningPolicy(linkID)
+		if merr != nil {
+			this.sendModelErrorAsResp(merr, action)
+			return
+		}
+		if policy.ScannedPDFRequired {
+			this.sendFailedResponse(
+				400, errScannedPDFRequired,
+				fmt.Errorf("the scanned pdf has not been uploaded"), action)
+			return
+		}
+	}
+
 	added, merr := models.CreateCorporationAdministrator(linkID, corpSigning.AdminName, corpEmail)
 	if merr != nil {
 		if merr.IsErrorOf(models.ErrNoLinkOrManagerExists) {
@@ -89,6 +104,7 @@ func (this *CorporationManagerController) Put() {
 	}
 
 	this.sendSuccessResp(action + " successfully")
+	this.addAuditLog(action, linkID, corpEmail, corpEmail)
 
 	notifyCorpAdmin(linkID, orgInfo, added)
 }
@@ -141,4 +157,5 @@ func (this *CorporationManagerController) Patch() {
 	}
 
 	this.sendSuccessResp("reset password successfully")
+	this.addAuditLog(action, pl.LinkID, pl.Email, pl.Email)
 }
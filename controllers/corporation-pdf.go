@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/opensourceways/app-cla-server/config"
@@ -59,6 +60,10 @@ func (this *CorporationPDFController) downloadCorpPDF(linkID, corpEmail string)
 // @Param	:org_cla_id	path 	string					true		"org cla id"
 // @Param	:email		path 	string					true		"email of corp"
 // @Success 204 {int} map
+// @Failure 400 not_pdf_file:          the file can't be parsed as pdf
+// @Failure 400 pdf_active_content:    the pdf has active content, such as JavaScript and embedded files
+// @Failure 400 invalid_pdf_signature: the signature of the community on the pdf is invalid, or the signed content is changed
+// @Failure 400 unmatched_pdf:         the pdf contradicts the signing, and invalid_fields has the unmatched items
 // @router /:link_id/:email [patch]
 func (this *CorporationPDFController) Upload() {
 	action := "upload corp's signing pdf"
@@ -99,12 +104,24 @@ func (this *CorporationPDFController) Upload() {
 		return
 	}
 
+	missing, fr := checkCorpSigningPDF(linkID, corpEmail, pl.orgInfo(linkID), data)
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
 	if err := models.UploadCorporationSigningPDF(linkID, corpEmail, data); err != nil {
 		this.sendModelErrorAsResp(err, action)
 		return
 	}
 
 	this.sendSuccessResp("upload pdf of signature page successfully")
+
+	// the scanned pdf can't be fully checked, so it is recorded for review.
+	if len(missing) > 0 {
+		action = fmt.Sprintf("%s without checking %s", action, strings.Join(missing, ", "))
+	}
+	this.addAuditLog(action, linkID, corpEmail, corpEmail)
 }
 
 // @Title Download
@@ -171,24 +188,34 @@ func (this *CorporationPDFController) Preview() {
 		return
 	}
 
+	outFile, fr := genBlankCorpPDF(linkID, claLang, nil)
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	defer func() { os.Remove(outFile) }()
+	this.downloadFile(outFile)
+}
+
+// genBlankCorpPDF generates the pdf of corporation signing with blank signing info.
+// It uses the templates saved by the community or the default ones if tmpl is nil.
+func genBlankCorpPDF(linkID, claLang string, tmpl *models.CorpPDFTemplate) (string, *failedApiResult) {
 	orgInfo, merr := models.GetOrgOfLink(linkID)
 	if merr != nil {
-		this.sendModelErrorAsResp(merr, action)
-		return
+		return "", parseModelError(merr)
 	}
 
 	claInfo, merr := models.GetCLAInfoToSign(linkID, claLang, dbmodels.ApplyToCorporation)
 	if merr != nil {
-		this.sendModelErrorAsResp(merr, action)
-		return
+		return "", parseModelError(merr)
 	}
 	if claInfo == nil {
-		this.sendFailedResponse(400, errUnsupportedCLALang, fmt.Errorf("unsupport language"), action)
-		return
+		return "", newFailedApiResult(400, errUnsupportedCLALang, fmt.Errorf("unsupport language"))
 	}
 
-	claFile := genCLAFilePath(linkID, dbmodels.ApplyToCorporation, claLang)
-	orgSignatureFile := genOrgSignatureFilePath(linkID, claLang)
+	claFile := genCLAFilePath(linkID, dbmodels.ApplyToCorporation, claLang, claInfo.CLAHash)
+	orgSignatureFile := genOrgSignatureFilePath(linkID, claLang, claInfo.CLAHash)
 
 	value := map[string]string{}
 	for _, item := range claInfo.Fields {
@@ -197,19 +224,85 @@ func (this *CorporationPDFController) Preview() {
 
 	signing := models.CorporationSigning{
 		CorporationSigningBasicInfo: dbmodels.CorporationSigningBasicInfo{
-			AdminEmail: "test@preview_blank_pdf.com",
-			Date:       util.Date(),
+			AdminEmail:  "test@preview_blank_pdf.com",
+			Date:        util.Date(),
+			CLALanguage: claLang,
 		},
 		Info: dbmodels.TypeSigningInfo(value),
 	}
 
-	outFile, err := pdf.GetPDFGenerator().GenPDFForCorporationSigning(
-		linkID, orgSignatureFile, claFile, orgInfo, &signing, claInfo.Fields)
+	var outFile string
+	var err error
+	g := pdf.GetPDFGenerator()
+	if tmpl == nil {
+		outFile, err = g.GenPDFForCorporationSigning(
+			linkID, orgSignatureFile, claFile, orgInfo, &signing, claInfo.Fields)
+	} else {
+		outFile, err = g.GenSamplePDFForCorporationSigning(
+			linkID, orgSignatureFile, claFile, orgInfo, &signing, claInfo.Fields, tmpl)
+	}
 	if err != nil {
-		this.sendFailedResponse(400, errSystemError, err, action)
-		return
+		if tmpl != nil {
+			return "", newFailedApiResult(400, errInvalidPDFTemplate, err)
+		}
+		return "", newFailedApiResult(400, errSystemError, err)
 	}
 
-	defer func() { os.Remove(outFile) }()
-	this.downloadFile(outFile)
+	return outFile, nil
+}
+
+// checkCorpSigningPDF checks the uploaded pdf against the one generated for the signing.
+// It is rejected only if it contradicts the signing, and the items which can't be checked,
+// such as the meta of scanned pdf, are returned.
+func checkCorpSigningPDF(linkID, corpEmail string, orgInfo *models.OrgInfo, data []byte) ([]string, *failedApiResult) {
+	active, err := pdf.FindActiveContent(data)
+	if err != nil {
+		return nil, newFailedApiResult(400, errNotPDFFile, err)
+	}
+	if len(active) > 0 {
+		return nil, newFailedApiResult(
+			400, errPDFActiveContent,
+			fmt.Errorf("the pdf has active content: %s", strings.Join(active, ", ")),
+		)
+	}
+
+	g := pdf.GetPDFGenerator()
+
+	if err := g.VerifySignature(data); err != nil {
+		return nil, newFailedApiResult(400, errInvalidPDFSignature, err)
+	}
+
+	fields, signing, merr := models.GetCorpSigningDetail(linkID, corpEmail)
+	if merr != nil {
+		return nil, parseModelError(merr)
+	}
+	if fields == nil {
+		return nil, newFailedApiResult(400, errUnsigned, fmt.Errorf("no data"))
+	}
+
+	claFile := genCLAFilePath(linkID, dbmodels.ApplyToCorporation, signing.CLALanguage, signing.CLAHash)
+
+	items, err := g.CheckCorpSigningPDF(data, linkID, claFile, orgInfo, signing, fields)
+	if err != nil {
+		return nil, newFailedApiResult(500, errSystemError, err)
+	}
+
+	var missing []string
+	unmatched := map[string]string{}
+	for k, v := range items {
+		if v == pdf.PDFErrUnmatched {
+			unmatched[k] = v
+		} else {
+			missing = append(missing, k)
+		}
+	}
+
+	if len(unmatched) > 0 {
+		fr := newFailedApiResult(400, errUnmatchedPDF, fmt.Errorf("the pdf is unmatched with the signing"))
+		fr.invalidFields = unmatched
+		return nil, fr
+	}
+
+	sort.Strings(missing)
+	return missing, nil
 }
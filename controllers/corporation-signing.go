@@ -38,6 +38,9 @@ func (this *CorporationSigningController) Prepare() {
 // @Failure 405 no_link:                    the link id is not exists
 // @Failure 406 unmatched_cla:              the cla hash is not equal to the one of backend server
 // @Failure 407 resigned:                   the signer has signed the cla
+// @Failure 408 invalid_signing_info:       some fields are invalid, and invalid_fields has the error code of each one
+// @Failure 409 invalid_esignature:         the electronic signature is invalid
+// @Failure 410 esigning_disabled:          the community does not allow to sign electronically
 // @Failure 500 system_error:               system error
 // @router /:link_id/:cla_lang/:cla_hash [post]
 func (this *CorporationSigningController) Post() {
@@ -63,6 +66,18 @@ func (this *CorporationSigningController) Post() {
 		return
 	}
 
+	var policy *models.CorpSigningPolicy
+	if info.ESignature != nil {
+		if policy, merr = models.GetCorpSigningPolicy(linkID); merr != nil {
+			this.sendModelErrorAsResp(merr, action)
+			return
+		}
+		if !policy.ESigning {
+			this.sendFailedResponse(400, errESigningDisabled, fmt.Errorf("esigning is disabled"), action)
+			return
+		}
+	}
+
 	fr := signHelper(
 		linkID, claLang, dbmodels.ApplyToCorporation,
 		func(claInfo *models.CLAInfo) *failedApiResult {
@@ -70,13 +85,18 @@ func (this *CorporationSigningController) Post() {
 				return newFailedApiResult(400, errUnmatchedCLA, fmt.Errorf("unmatched cla"))
 			}
 
-			claFile := genCLAFilePath(linkID, dbmodels.ApplyToCorporation, claLang)
-			orgSignatureFile := genOrgSignatureFilePath(linkID, claLang)
+			claFile := genCLAFilePath(linkID, dbmodels.ApplyToCorporation, claLang, claInfo.CLAHash)
+			orgSignatureFile := genOrgSignatureFilePath(linkID, claLang, claInfo.CLAHash)
 			if fr := this.checkCLAForSigning(claFile, orgSignatureFile, claInfo); fr != nil {
 				return fr
 			}
 
-			info.Info = getSingingInfo(info.Info, claInfo.Fields)
+			v, fr := getSingingInfo(info.Info, claInfo.Fields)
+			if fr != nil {
+				return fr
+			}
+			info.Info = v
+			info.CLAHash = claInfo.CLAHash
 
 			if err := (&info).Create(linkID); err != nil {
 				if err.IsErrorOf(models.ErrNoLinkOrResigned) {
@@ -85,10 +105,18 @@ func (this *CorporationSigningController) Post() {
 				return parseModelError(err)
 			}
 
-			worker.GetEmailWorker().GenCLAPDFForCorporationAndSendIt(
-				linkID, orgSignatureFile, claFile, *orgInfo,
-				info.CorporationSigning, claInfo.Fields,
-			)
+			if info.ESignature != nil {
+				worker.GetEmailWorker().GenESignedCLAPDFForCorporationAndSendIt(
+					linkID, orgSignatureFile, claFile, *orgInfo,
+					info.CorporationSigning, claInfo.Fields,
+					*info.ESignature, policy.ScannedPDFRequired,
+				)
+			} else {
+				worker.GetEmailWorker().GenCLAPDFForCorporationAndSendIt(
+					linkID, orgSignatureFile, claFile, *orgInfo,
+					info.CorporationSigning, claInfo.Fields,
+				)
+			}
 
 			return nil
 		},
@@ -97,6 +125,8 @@ func (this *CorporationSigningController) Post() {
 		this.sendFailedResultAsResp(fr, action)
 	} else {
 		this.sendSuccessResp("sign successfully")
+		this.addAuditLogOfActor(info.AdminEmail, action, linkID, info.AdminEmail, info.AdminEmail)
+		resignInCampaigns(linkID, info.AdminEmail, true)
 	}
 }
 
@@ -168,12 +198,13 @@ func (this *CorporationSigningController) Delete() {
 		return
 	}
 
-	if err := models.DeleteCorpSigning(linkID, corpEmail); err != nil {
+	if err := models.DeleteCorpSigning(linkID, corpEmail, pl.userID()); err != nil {
 		this.sendModelErrorAsResp(err, action)
 		return
 	}
 
 	this.sendSuccessResp("delete corp signing successfully")
+	this.addAuditLog(action, linkID, corpEmail, corpEmail)
 }
 
 // @Title ResendCorpSigningEmail
@@ -207,8 +238,8 @@ func (this *CorporationSigningController) ResendCorpSigningEmail() {
 		return
 	}
 
-	claFile := genCLAFilePath(linkID, dbmodels.ApplyToCorporation, signingInfo.CLALanguage)
-	orgSignatureFile := genOrgSignatureFilePath(linkID, signingInfo.CLALanguage)
+	claFile := genCLAFilePath(linkID, dbmodels.ApplyToCorporation, signingInfo.CLALanguage, signingInfo.CLAHash)
+	orgSignatureFile := genOrgSignatureFilePath(linkID, signingInfo.CLALanguage, signingInfo.CLAHash)
 
 	worker.GetEmailWorker().GenCLAPDFForCorporationAndSendIt(
 		linkID, orgSignatureFile, claFile, *pl.orgInfo(linkID),
@@ -319,3 +350,84 @@ func (this *CorporationSigningController) ListDeleted() {
 
 	this.sendSuccessResp(r)
 }
+
+// @Title Restore
+// @Description restore the latest deleted signing of corporation
+// @Param	:link_id	path 	string		true		"link id"
+// @Param	:email		path 	string		true		"corp email"
+// @Success 202 {object} map
+// @Failure 400 no_link_or_not_deleted: the signing is not deleted
+// @Failure 401 resigned:               the corporation has signed again
+// @router /deleted/:link_id/:email [put]
+func (this *CorporationSigningController) Restore() {
+	action := "restore corp signing"
+	linkID := this.GetString(":link_id")
+	corpEmail := this.GetString(":email")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	unlock, fr := lockOnRepo(pl.orgInfo(linkID))
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	defer unlock()
+
+	if merr := models.RestoreCorpSigning(linkID, corpEmail, pl.userID()); merr != nil {
+		if merr.IsErrorOf(models.ErrNoLinkOrResigned) {
+			this.sendFailedResponse(400, errResigned, merr, action)
+		} else {
+			this.sendModelErrorAsResp(merr, action)
+		}
+		return
+	}
+
+	this.addAuditLog(action, linkID, corpEmail, corpEmail)
+
+	// The pdf is kept by corporation, so it belongs to the restored signing automatically.
+	uploaded, merr := models.IsCorpSigningPDFUploaded(linkID, corpEmail)
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	this.sendSuccessResp(map[string]bool{"pdf_uploaded": uploaded})
+}
+
+// @Title History
+// @Description list the deletions and restorations of corporation signing
+// @Param	:link_id	path 	string		true		"link id"
+// @Param	:email		path 	string		true		"corp email"
+// @Success 200 {object} dbmodels.CorpSigningEvent
+// @Failure 400 no_link: the link id is not exists
+// @router /history/:link_id/:email [get]
+func (this *CorporationSigningController) History() {
+	action := "list history of corp signing"
+	linkID := this.GetString(":link_id")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	r, merr := models.ListCorpSigningHistory(linkID, this.GetString(":email"))
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	this.sendSuccessResp(r)
+}
@@ -49,6 +49,9 @@ func (this *EmployeeManagerController) Post() {
 	}
 
 	this.sendSuccessResp(action + " successfully")
+	for i := range added {
+		this.addAuditLog(action, pl.LinkID, pl.Email, added[i].Email)
+	}
 
 	notifyCorpManagerWhenAdding(pl.LinkID, &pl.OrgInfo, added)
 }
@@ -86,6 +89,9 @@ func (this *EmployeeManagerController) Delete() {
 	}
 
 	this.sendSuccessResp(action + "successfully")
+	for i := range deleted {
+		this.addAuditLog(action, pl.LinkID, pl.Email, deleted[i].Email)
+	}
 
 	subject := fmt.Sprintf("Revoking the authorization on project of \"%s\"", pl.OrgAlias)
 
@@ -49,6 +49,7 @@ func (this *EmployeeSigningController) Prepare() {
 // @Failure 411 no_employee_manager:        there is not any employee managers for the corresponding corp
 // @Failure 412 unmatched_cla:              the cla hash is not equal to the one of backend server
 // @Failure 413 resigned:                   the signer has signed the cla
+// @Failure 414 invalid_signing_info:       some fields are invalid, and invalid_fields has the error code of each one
 // @Failure 500 system_error:               system error
 // @router /:link_id/:cla_lang/:cla_hash [post]
 func (this *EmployeeSigningController) Post() {
@@ -97,7 +98,12 @@ func (this *EmployeeSigningController) Post() {
 				return newFailedApiResult(400, errUnmatchedCLA, fmt.Errorf("invalid cla"))
 			}
 
-			info.Info = getSingingInfo(info.Info, claInfo.Fields)
+			v, fr := getSingingInfo(info.Info, claInfo.Fields)
+			if fr != nil {
+				return fr
+			}
+			info.Info = v
+			info.CLAHash = claInfo.CLAHash
 
 			if err := (&info).Create(linkID, false); err != nil {
 				if err.IsErrorOf(models.ErrNoLinkOrResigned) {
@@ -112,6 +118,8 @@ func (this *EmployeeSigningController) Post() {
 		this.sendFailedResultAsResp(fr, action)
 	} else {
 		this.sendSuccessResp("sign successfully")
+		this.addAuditLog(action, linkID, info.Email, info.Email)
+		resignInCampaigns(linkID, info.Email, false)
 		this.notifyManagers(linkID, managers, &info, orgInfo)
 	}
 }
@@ -218,6 +226,10 @@ func (this *EmployeeSigningController) Update() {
 	}
 
 	this.sendSuccessResp("enabled employee successfully")
+	this.addAuditLog(
+		fmt.Sprintf("%s, enabled: %t", action, info.Enabled),
+		pl.LinkID, employeeEmail, employeeEmail,
+	)
 
 	msg := this.newEmployeeNotification(pl, employeeEmail)
 	if info.Enabled {
@@ -230,8 +242,9 @@ func (this *EmployeeSigningController) Update() {
 }
 
 // @Title Delete
-// @Description delete employee signing
+// @Description delete employee signing, and it can be restored later
 // @Param	:email		path 	string	true		"email"
+// @Param	reason		query 	string	false		"the reason of deleting"
 // @Success 204 {string} delete success!
 // @router /:email [delete]
 func (this *EmployeeSigningController) Delete() {
@@ -249,18 +262,77 @@ func (this *EmployeeSigningController) Delete() {
 		return
 	}
 
-	if err := models.DeleteEmployeeSigning(pl.LinkID, employeeEmail); err != nil {
+	err := models.DeleteIndividualSigning(pl.LinkID, employeeEmail, pl.Email, this.GetString("reason"))
+	if err != nil {
 		this.sendModelErrorAsResp(err, action)
 		return
 	}
 
 	this.sendSuccessResp("delete employee successfully")
+	this.addAuditLog(action, pl.LinkID, employeeEmail, employeeEmail)
 
 	msg := this.newEmployeeNotification(pl, employeeEmail)
 	msg.Removing = true
 	sendEmailToIndividual(pl.LinkID, employeeEmail, "Remove employee", msg)
 }
 
+// @Title ListDeleted
+// @Description get all the deleted employee signings which can be restored
+// @Success 200 {object} dbmodels.DeletedIndividualSigning
+// @router /deleted [get]
+func (this *EmployeeSigningController) ListDeleted() {
+	action := "list deleted employees"
+
+	pl, fr := this.tokenPayloadBasedOnCorpManager()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	r, merr := models.ListDeletedIndividualSignings(pl.LinkID, pl.Email)
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	this.sendSuccessResp(r)
+}
+
+// @Title Restore
+// @Description restore the deleted employee signing
+// @Param	:email		path 	string	true		"email"
+// @Success 202 {string} "restore employee successfully"
+// @Failure 400 no_link_or_not_deleted: the signing is not deleted or has been purged
+// @Failure 401 resigned:               the employee has signed again
+// @router /deleted/:email [put]
+func (this *EmployeeSigningController) Restore() {
+	action := "restore employee signing"
+	employeeEmail := this.GetString(":email")
+
+	pl, fr := this.tokenPayloadBasedOnCorpManager()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	if !pl.hasEmployee(employeeEmail) {
+		this.sendFailedResponse(400, errNotSameCorp, fmt.Errorf("not same corp"), action)
+		return
+	}
+
+	if err := models.RestoreIndividualSigning(pl.LinkID, employeeEmail); err != nil {
+		if err.IsErrorOf(models.ErrNoLinkOrResigned) {
+			this.sendFailedResponse(400, errResigned, err, action)
+		} else {
+			this.sendModelErrorAsResp(err, action)
+		}
+		return
+	}
+
+	this.sendSuccessResp("restore employee successfully")
+	this.addAuditLog(action, pl.LinkID, employeeEmail, employeeEmail)
+}
+
 func (this *EmployeeSigningController) notifyManagers(linkID string, managers []dbmodels.CorporationManagerListResult, info *models.EmployeeSigning, orgInfo *models.OrgInfo) {
 	ms := make([]string, 0, len(managers))
 	to := make([]string, 0, len(managers))
@@ -41,6 +41,17 @@ const (
 	errFrequentOperation        = "frequent_operation"
 	errCanNotFetchClientIP      = "can_not_fetch_client_ip"
 	errNotPDFFile               = "not_pdf_file"
+	errEmployeeSigning          = "employee_signing"
+	errNoCampaign               = "no_campaign"
+	errTooBigCLAFile            = "too_big_cla_file"
+	errTooBigFile               = "too_big_file"
+	errMissingFile              = "missing_file"
+	errInvalidPDFSignature      = "invalid_pdf_signature"
+	errInvalidPDFTemplate       = string(models.ErrInvalidPDFTemplate)
+	errESigningDisabled         = string(models.ErrESigningDisabled)
+	errScannedPDFRequired       = "scanned_pdf_required"
+	errPDFActiveContent         = "pdf_active_content"
+	errUnmatchedPDF             = "unmatched_pdf"
 )
 
 func parseModelError(err models.IModelError) *failedApiResult {
@@ -63,5 +74,9 @@ func parseModelError(err models.IModelError) *failedApiResult {
 		code = string(err.ErrCode())
 	}
 
-	return newFailedApiResult(sc, code, err)
+	fr := newFailedApiResult(sc, code, err)
+	if v, ok := err.(models.InvalidFieldsError); ok {
+		fr.invalidFields = v.Fields
+	}
+	return fr
 }
@@ -45,6 +45,7 @@ func (this *IndividualSigningController) Prepare() {
 // @Failure 409 resigned:                   the signer has signed the cla
 // @Failure 410 no_link:                    the link id is not exists
 // @Failure 411 go_to_sign_employee_cla:    should sign employee cla instead
+// @Failure 412 invalid_signing_info:       some fields are invalid, and invalid_fields has the error code of each one
 // @Failure 500 system_error:               system error
 // @router /:link_id/:cla_lang/:cla_hash [post]
 func (this *IndividualSigningController) Post() {
@@ -87,7 +88,12 @@ func (this *IndividualSigningController) Post() {
 				return newFailedApiResult(400, errUnmatchedCLA, fmt.Errorf("invalid cla"))
 			}
 
-			info.Info = getSingingInfo(info.Info, claInfo.Fields)
+			v, fr := getSingingInfo(info.Info, claInfo.Fields)
+			if fr != nil {
+				return fr
+			}
+			info.Info = v
+			info.CLAHash = claInfo.CLAHash
 
 			if err := (&info).Create(linkID, true); err != nil {
 				if err.IsErrorOf(models.ErrNoLinkOrResigned) {
@@ -102,11 +108,13 @@ func (this *IndividualSigningController) Post() {
 		this.sendFailedResultAsResp(fr, action)
 	} else {
 		this.sendSuccessResp("sign successfully")
+		this.addAuditLog(action, linkID, "", info.Email)
+		resignInCampaigns(linkID, info.Email, false)
 	}
 }
 
 // @Title Check
-// @Description check whether contributor has signed cla
+// @Description check whether contributor has signed cla. needs_resign is true if the contributor should sign the current version of cla
 // @Param	platform	path 	string	true		"code platform"
 // @Param	org_repo	path 	string	true		"org:repo"
 // @Param	email		query 	string	true		"email of contributor"
@@ -124,10 +132,10 @@ func (this *IndividualSigningController) Check() {
 		return
 	}
 
-	if v, merr := models.IsIndividualSigned(linkID, this.GetString("email")); merr != nil {
+	if v, merr := models.GetIndividualSigningStatus(linkID, this.GetString("email")); merr != nil {
 		this.sendModelErrorAsResp(merr, action)
 	} else {
-		this.sendSuccessResp(map[string]bool{"signed": v})
+		this.sendSuccessResp(v)
 	}
 }
 
@@ -164,20 +172,98 @@ func (this *IndividualSigningController) List() {
 		return
 	}
 
-	corps, merr := models.ListCorpSignings(linkID, "")
+	m, merr := corpsManagingEmployees(linkID)
 	if merr != nil {
 		this.sendModelErrorAsResp(merr, action)
 		return
 	}
 
-	m := make(map[string]bool, len(corps))
-	for i := range corps {
-		if corps[i].AdminAdded {
-			m[util.EmailSuffix(corps[i].AdminEmail)] = true
+	result := make([]*dbmodels.IndividualSigningBasicInfo, 0, len(r))
+	for i := range r {
+		if !m[util.EmailSuffix(r[i].Email)] {
+			result = append(result, &r[i])
 		}
 	}
+	this.sendSuccessResp(result)
+}
 
-	result := make([]*dbmodels.IndividualSigningBasicInfo, 0, len(r))
+// @Title Delete
+// @Description delete individual signing by community manager, and it can be restored later
+// @Param	:link_id	path 	string		true		"link id"
+// @Param	:email		path 	string		true		"email"
+// @Param	reason		query 	string		false		"the reason of deleting"
+// @Success 204 {string} delete success!
+// @Failure 400 employee_signing: it is the employee signing which is managed by the corporation
+// @router /:link_id/:email [delete]
+func (this *IndividualSigningController) Delete() {
+	action := "delete individual signing"
+	linkID := this.GetString(":link_id")
+	email := this.GetString(":email")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	m, merr := corpsManagingEmployees(linkID)
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+	if m[util.EmailSuffix(email)] {
+		this.sendFailedResponse(
+			400, errEmployeeSigning,
+			fmt.Errorf("the employee signing is managed by the corporation"), action,
+		)
+		return
+	}
+
+	if merr := models.DeleteIndividualSigning(linkID, email, pl.userID(), this.GetString("reason")); merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	this.sendSuccessResp("delete individual signing successfully")
+	this.addAuditLog(action, linkID, "", email)
+}
+
+// @Title ListDeleted
+// @Description get all the deleted individual signings which can be restored
+// @Param	:link_id	path 	string		true		"link id"
+// @Success 200 {object} dbmodels.DeletedIndividualSigning
+// @router /deleted/:link_id [get]
+func (this *IndividualSigningController) ListDeleted() {
+	action := "list deleted individual signings"
+	linkID := this.GetString(":link_id")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	r, merr := models.ListDeletedIndividualSignings(linkID, "")
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	m, merr := corpsManagingEmployees(linkID)
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	result := make([]*models.DeletedIndividualSigning, 0, len(r))
 	for i := range r {
 		if !m[util.EmailSuffix(r[i].Email)] {
 			result = append(result, &r[i])
@@ -185,3 +271,70 @@ func (this *IndividualSigningController) List() {
 	}
 	this.sendSuccessResp(result)
 }
+
+// @Title Restore
+// @Description restore the deleted individual signing by community manager
+// @Param	:link_id	path 	string		true		"link id"
+// @Param	:email		path 	string		true		"email"
+// @Success 202 {string} "restore individual signing successfully"
+// @Failure 400 no_link_or_not_deleted: the signing is not deleted or has been purged
+// @Failure 401 resigned:               the individual has signed again
+// @Failure 402 employee_signing:       it is the employee signing which is managed by the corporation
+// @router /deleted/:link_id/:email [put]
+func (this *IndividualSigningController) Restore() {
+	action := "restore individual signing"
+	linkID := this.GetString(":link_id")
+	email := this.GetString(":email")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	m, merr := corpsManagingEmployees(linkID)
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+	if m[util.EmailSuffix(email)] {
+		this.sendFailedResponse(
+			400, errEmployeeSigning,
+			fmt.Errorf("the employee signing is managed by the corporation"), action,
+		)
+		return
+	}
+
+	if merr := models.RestoreIndividualSigning(linkID, email); merr != nil {
+		if merr.IsErrorOf(models.ErrNoLinkOrResigned) {
+			this.sendFailedResponse(400, errResigned, merr, action)
+		} else {
+			this.sendModelErrorAsResp(merr, action)
+		}
+		return
+	}
+
+	this.sendSuccessResp("restore individual signing successfully")
+	this.addAuditLog(action, linkID, "", email)
+}
+
+// corpsManagingEmployees returns the corporations whose administrators have been added,
+// and the signings of their employees are managed by them instead of the community manager.
+func corpsManagingEmployees(linkID string) (map[string]bool, models.IModelError) {
+	corps, merr := models.ListCorpSignings(linkID, "")
+	if merr != nil {
+		return nil, merr
+	}
+
+	m := make(map[string]bool, len(corps))
+	for i := range corps {
+		if corps[i].AdminAdded {
+			m[util.EmailSuffix(corps[i].AdminEmail)] = true
+		}
+	}
+	return m, nil
+}
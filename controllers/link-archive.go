@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/opensourceways/app-cla-server/config"
+	"github.com/opensourceways/app-cla-server/models"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+// @Title Export
+// @Description export all the data of link to an archive
+// @Param	:link_id	path 	string		true		"link id"
+// @Success 200 {int} map
+// @router /:link_id/archive [get]
+func (this *LinkController) Export() {
+	action := "export link"
+	sendResp := this.newFuncForSendingFailedResp(action)
+	linkID := this.GetString(":link_id")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		sendResp(fr)
+		return
+	}
+
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		sendResp(fr)
+		return
+	}
+
+	archive, merr := models.ExportLink(linkID)
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	dir := util.GenFilePath(config.AppConfig.PDFOutDir, "tmp")
+	f, err := ioutil.TempFile(dir, fmt.Sprintf("%s_*.json.gz", linkID))
+	if err != nil {
+		this.sendFailedResponse(500, errSystemError, err, action)
+		return
+	}
+	path := f.Name()
+
+	defer func() {
+		os.Remove(path)
+	}()
+
+	err = archive.Write(f)
+	f.Close()
+	if err != nil {
+		this.sendFailedResponse(500, errSystemError, err, action)
+		return
+	}
+
+	this.downloadFile(path)
+}
+
+// @Title Import
+// @Description import the link from the archive exported by Export
+// @Param	archive		formData 	file	true		"the archive of link"
+// @Success 201 {string} "import link successfully"
+// @router /archive [post]
+func (this *LinkController) Import() {
+	action := "import link"
+	sendResp := this.newFuncForSendingFailedResp(action)
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		sendResp(fr)
+		return
+	}
+
+	f, _, err := this.GetFile(fileNameOfUploadingLinkArchive)
+	if err != nil {
+		this.sendFailedResponse(400, errReadingFile, err, action)
+		return
+	}
+	defer f.Close()
+
+	archive, merr := models.ReadLinkArchive(f)
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	if archive.Platform != pl.Platform {
+		this.sendFailedResponse(400, errNotYoursOrg, fmt.Errorf("not the platform of owner"), action)
+		return
+	}
+
+	if fr := pl.isOwnerOfOrg(archive.OrgID); fr != nil {
+		sendResp(fr)
+		return
+	}
+
+	filePath := genOrgFileLockPath(archive.Platform, archive.OrgID, archive.RepoID)
+	if err := util.CreateLockedFile(filePath); err != nil {
+		this.sendFailedResponse(500, errSystemError, err, action)
+		return
+	}
+
+	unlock, err := util.Lock(filePath)
+	if err != nil {
+		this.sendFailedResponse(500, errSystemError, err, action)
+		return
+	}
+	defer unlock()
+
+	if merr := archive.Import(); merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	if err := saveCorpCLAsOfLinkAtLocal(archive.LinkID); err != nil {
+		this.sendFailedResponse(500, errSystemError, err, action)
+		return
+	}
+
+	this.sendResponse("import link successfully", 0)
+	this.addAuditLog(action, archive.LinkID, "", archive.OrgRepoID())
+}
@@ -22,7 +22,7 @@ func (this *LinkController) Prepare() {
 }
 
 // @Title Link
-// @Description link org and cla
+// @Description link org and cla. The cla text is uploaded as individual_cla_file or corp_cla_file, or downloaded from the url if it is not uploaded
 // @Param	body		body 	models.LinkCreateOption	true		"body for creating link"
 // @Success 201 {string} "create org cla successfully"
 // @router / [post]
@@ -53,6 +53,28 @@ func (this *LinkController) Link() {
 		input.CorpCLA.SetOrgSignature(&data)
 	}
 
+	clas := []struct {
+		fileName string
+		opt      *models.CLACreateOpt
+	}{
+		{fileNameOfUploadingIndividualCLA, input.IndividualCLA},
+		{fileNameOfUploadingCorpCLA, input.CorpCLA},
+	}
+	for _, item := range clas {
+		if item.opt == nil {
+			continue
+		}
+
+		text, fr := this.readCLAFile(item.fileName)
+		if fr != nil {
+			sendResp(fr)
+			return
+		}
+		if text != nil {
+			item.opt.SetCLAContent(text)
+		}
+	}
+
 	if merr := input.Validate(pdf.GetPDFGenerator().LangSupported()); merr != nil {
 		this.sendModelErrorAsResp(merr, action)
 		return
@@ -104,6 +126,7 @@ func (this *LinkController) Link() {
 	}
 
 	this.sendResponse("create org cla successfully", 0)
+	this.addAuditLog(action, linkID, "", orgRepo.OrgRepoID())
 }
 
 func (this *LinkController) initializeSigning(input *models.LinkCreateOption, linkID string, orgRepo *dbmodels.OrgRepo) *failedApiResult {
@@ -160,6 +183,7 @@ func (this *LinkController) Unlink() {
 	}
 
 	this.sendSuccessResp(action + "successfully")
+	this.addAuditLog(action, linkID, "", linkID)
 }
 
 // @Title ListLinks
@@ -214,18 +238,31 @@ func (this *LinkController) GetCLAForSigning() {
 	org, repo := parseOrgAndRepo(this.GetString(":org_id"))
 	orgRepo := buildOrgRepo(this.GetString(":platform"), org, repo)
 
-	if linkID, r, err := models.GetCLAByType(orgRepo, applyTo); err != nil {
+	linkID, r, err := models.GetCLAByType(orgRepo, applyTo)
+	if err != nil {
 		this.sendModelErrorAsResp(err, action)
-	} else {
-		result := struct {
-			LinkID string               `json:"link_id"`
-			CLAs   []dbmodels.CLADetail `json:"clas"`
-		}{
-			LinkID: linkID,
-			CLAs:   r,
+		return
+	}
+
+	result := struct {
+		LinkID string               `json:"link_id"`
+		CLAs   []dbmodels.CLADetail `json:"clas"`
+
+		// CorpSigningPolicy is only for the corporation signing.
+		CorpSigningPolicy *models.CorpSigningPolicy `json:"corp_signing_policy,omitempty"`
+	}{
+		LinkID: linkID,
+		CLAs:   r,
+	}
+
+	if applyTo == dbmodels.ApplyToCorporation {
+		if result.CorpSigningPolicy, err = models.GetCorpSigningPolicy(linkID); err != nil {
+			this.sendModelErrorAsResp(err, action)
+			return
 		}
-		this.sendSuccessResp(result)
 	}
+
+	this.sendSuccessResp(result)
 }
 
 func LoadLinks() error {
@@ -243,30 +280,40 @@ func LoadLinks() error {
 			return err
 		}
 
-		linkID := link.LinkID
-
-		info, err := models.GetAllCLA(linkID)
-		if err != nil {
+		if err := saveCorpCLAsOfLinkAtLocal(link.LinkID); err != nil {
 			return err
 		}
+	}
+
+	return nil
+}
+
+// saveCorpCLAsOfLinkAtLocal saves the corp clas and org signatures of the link
+// at local, which are used to generate the pdf of corp signing. All the versions
+// are saved, because the pdf of signing of old version is regenerated by its own cla.
+func saveCorpCLAsOfLinkAtLocal(linkID string) error {
+	info, err := models.GetAllCLA(linkID)
+	if err != nil {
+		return err
+	}
 
-		for j := range info.CorpCLAs {
-			cla := &info.CorpCLAs[j]
-			text := []byte(cla.Text)
+	clas := info.CorpCLAs
+	for i := range clas {
+		cla := &clas[i]
+		text := []byte(cla.Text)
 
-			signature, err := models.DownloadCorpCLAPDF(linkID, cla.Language)
-			if err != nil {
-				return err
-			}
+		signature, err := models.DownloadCorpCLAPDF(linkID, cla.Language, cla.Version)
+		if err != nil {
+			return err
+		}
 
-			opt := &models.CLACreateOpt{}
-			opt.Language = cla.Language
-			opt.SetCLAContent(&text)
-			opt.SetOrgSignature(&signature)
+		opt := &models.CLACreateOpt{}
+		opt.Language = cla.Language
+		opt.SetCLAContent(&text)
+		opt.SetOrgSignature(&signature)
 
-			if fr := saveCorpCLAAtLocal(opt, linkID); fr != nil {
-				return fr.reason
-			}
+		if fr := saveCorpCLAAtLocal(opt, linkID); fr != nil {
+			return fr.reason
 		}
 	}
 
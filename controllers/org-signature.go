@@ -3,6 +3,8 @@ package controllers
 import (
 	"fmt"
 
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/models"
 	"github.com/opensourceways/app-cla-server/pdf"
 	"github.com/opensourceways/app-cla-server/util"
 )
@@ -34,7 +36,18 @@ func (this *OrgSignatureController) Get() {
 		return
 	}
 
-	path := genOrgSignatureFilePath(linkID, claLang)
+	claInfo, merr := models.GetCLAInfoToSign(linkID, claLang, dbmodels.ApplyToCorporation)
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+	if claInfo == nil {
+		this.sendFailedResponse(400, errFileNotExists, fmt.Errorf(errFileNotExists), action)
+		return
+	}
+
+	// the org signature of the current version
+	path := genOrgSignatureFilePath(linkID, claLang, claInfo.CLAHash)
 	if util.IsFileNotExist(path) {
 		this.sendFailedResponse(400, errFileNotExists, fmt.Errorf(errFileNotExists), action)
 		return
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/astaxie/beego"
+
+	"github.com/opensourceways/app-cla-server/email"
+	"github.com/opensourceways/app-cla-server/models"
+)
+
+type ResignCampaignController struct {
+	baseController
+}
+
+func (this *ResignCampaignController) Prepare() {
+	this.apiPrepare(PermissionOwnerOfOrg)
+}
+
+// @Title Post
+// @Description start a campaign to ask the signers of older version of cla to sign the current one
+// @Param	:link_id	path 	string					true		"link id"
+// @Param	body		body 	models.ResignCampaignCreateOpt	true		"body for campaign"
+// @Success 201 {object} models.ResignCampaign
+// @Failure 400 no_signer_to_resign: no signer signed an older version of cla
+// @router /:link_id [post]
+func (this *ResignCampaignController) Post() {
+	action := "start re-signing campaign"
+	linkID := this.GetString(":link_id")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	var opt models.ResignCampaignCreateOpt
+	if fr := this.fetchInputPayload(&opt); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	if merr := (&opt).Validate(); merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	campaign, merr := (&opt).Create(linkID, pl.userID())
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	this.sendSuccessResp(campaign)
+	this.addAuditLog(action, linkID, "", campaign.ID)
+
+	notifySignersToResign(linkID, pl.orgInfo(linkID), opt.SigningURL, campaign)
+}
+
+// @Title List
+// @Description list the re-signing campaigns of link
+// @Param	:link_id	path 	string		true		"link id"
+// @Success 200 {object} models.ResignCampaign
+// @router /:link_id [get]
+func (this *ResignCampaignController) List() {
+	action := "list re-signing campaigns"
+	linkID := this.GetString(":link_id")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	r, merr := models.ListResignCampaigns(linkID)
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+
+	this.sendSuccessResp(r)
+}
+
+// @Title Get
+// @Description get the re-signing campaign including the status of each signer
+// @Param	:link_id	path 	string		true		"link id"
+// @Param	:id		path 	string		true		"campaign id"
+// @Success 200 {object} models.ResignCampaign
+// @Failure 400 no_campaign: there is not such campaign
+// @router /:link_id/:id [get]
+func (this *ResignCampaignController) Get() {
+	action := "get re-signing campaign"
+	linkID := this.GetString(":link_id")
+
+	pl, fr := this.tokenPayloadBasedOnCodePlatform()
+	if fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+	if fr := pl.isOwnerOfLink(linkID); fr != nil {
+		this.sendFailedResultAsResp(fr, action)
+		return
+	}
+
+	r, merr := models.GetResignCampaign(linkID, this.GetString(":id"))
+	if merr != nil {
+		this.sendModelErrorAsResp(merr, action)
+		return
+	}
+	if r == nil {
+		this.sendFailedResponse(400, errNoCampaign, fmt.Errorf("no campaign"), action)
+		return
+	}
+
+	this.sendSuccessResp(r)
+}
+
+func notifySignersToResign(linkID string, orgInfo *models.OrgInfo, signingURL string, campaign *models.ResignCampaign) {
+	subject := fmt.Sprintf("Signing the new version of CLA on project of \"%s\"", orgInfo.OrgAlias)
+	deadline := time.Unix(campaign.Deadline, 0).Format("2006-01-02 15:04 MST")
+
+	for i := range campaign.Signers {
+		item := &campaign.Signers[i]
+
+		d := email.ResignCLA{
+			Name:       item.Name,
+			Org:        orgInfo.OrgAlias,
+			ProjectURL: orgInfo.ProjectURL(),
+			SigningURL: signingURL,
+			Deadline:   deadline,
+		}
+
+		sendEmailToIndividual(linkID, item.Email, subject, d)
+	}
+}
+
+// resignInCampaigns should be called after the signer signs the current version of cla.
+// corp means the signer is the corporation whose admin email is email.
+func resignInCampaigns(linkID, email string, corp bool) {
+	// the signing has been done, so the failure is only logged.
+	if merr := models.ResignInCampaigns(linkID, email, corp); merr != nil {
+		beego.Error(fmt.Sprintf("Failed to update the re-signing campaigns, err: %s", merr.Error()))
+	}
+}
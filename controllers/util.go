@@ -17,11 +17,18 @@ import (
 )
 
 const (
-	apiHeaderToken                 = "Token"
-	apiAccessToken                 = "access_token"
-	apiAccessController            = "access_controller"
-	contentTypeOfPDF               = "application/pdf"
-	fileNameOfUploadingOrgSignatue = "org_signature_file"
+	apiHeaderToken                   = "Token"
+	apiAccessToken                   = "access_token"
+	apiAccessController              = "access_controller"
+	contentTypeOfPDF                 = "application/pdf"
+	fileNameOfUploadingOrgSignatue   = "org_signature_file"
+	fileNameOfUploadingLinkArchive   = "archive"
+	fileNameOfUploadingCLA           = "cla_file"
+	fileNameOfUploadingIndividualCLA = "individual_cla_file"
+	fileNameOfUploadingCorpCLA       = "corp_cla_file"
+	fileNameOfUploadingWelcome       = "welcome_file"
+	fileNameOfUploadingDeclaration   = "declaration_file"
+	fileNameOfUploadingLogo          = "logo_file"
 )
 
 func sendEmailToIndividual(linkID, to, subject string, builder email.IEmailMessageBulder) {
@@ -66,19 +73,13 @@ func notifyCorpManagerWhenAdding(linkID string, orgInfo *models.OrgInfo, info []
 	}
 }
 
-func getSingingInfo(info dbmodels.TypeSigningInfo, fields []dbmodels.Field) dbmodels.TypeSigningInfo {
-	if len(info) == 0 {
-		return info
+// getSingingInfo validates the info against the fields of cla and keeps the values of fields only.
+func getSingingInfo(info dbmodels.TypeSigningInfo, fields []dbmodels.Field) (dbmodels.TypeSigningInfo, *failedApiResult) {
+	r, merr := models.ValidateSigningInfo(info, fields)
+	if merr != nil {
+		return nil, parseModelError(merr)
 	}
-
-	r := dbmodels.TypeSigningInfo{}
-	for i := range fields {
-		fid := fields[i].ID
-		if v, ok := info[fid]; ok {
-			r[fid] = v
-		}
-	}
-	return r
+	return r, nil
 }
 
 func parseOrgAndRepo(s string) (string, string) {
@@ -104,16 +105,19 @@ func genOrgFileLockPath(platform, org, repo string) string {
 	)
 }
 
-func genCLAFilePath(linkID, applyTo, language string) string {
+// genCLAFilePath returns the path of the cla whose hash is claHash, so that each
+// version is saved separately and the signings of old version still use their own cla.
+func genCLAFilePath(linkID, applyTo, language, claHash string) string {
 	return util.GenFilePath(
 		config.AppConfig.PDFOrgSignatureDir,
-		util.GenFileName("cla", linkID, applyTo, language, ".txt"))
+		util.GenFileName("cla", linkID, applyTo, language, claHash, ".txt"))
 }
 
-func genOrgSignatureFilePath(linkID, language string) string {
+// genOrgSignatureFilePath returns the path of the org signature of the cla whose hash is claHash.
+func genOrgSignatureFilePath(linkID, language, claHash string) string {
 	return util.GenFilePath(
 		config.AppConfig.PDFOrgSignatureDir,
-		util.GenFileName("signature", linkID, language, ".pdf"))
+		util.GenFileName("signature", linkID, language, claHash, ".pdf"))
 }
 
 func genLinkID(v *dbmodels.OrgRepo) string {
@@ -139,33 +143,15 @@ func getCLAInfoSigned(linkID, claLang, applyTo string) (*models.CLAInfo, *failed
 	return nil, parseModelError(merr)
 }
 
+// signHelper signs the current version of cla. The cla info of each version
+// is saved before the version is added, so it is ready for rendering the signing.
 func signHelper(linkID, claLang, applyTo string, doSign func(*models.CLAInfo) *failedApiResult) *failedApiResult {
-	claInfo, fr := getCLAInfoSigned(linkID, claLang, applyTo)
-	if fr != nil {
-		return fr
+	claInfo, merr := models.GetCLAInfoToSign(linkID, claLang, applyTo)
+	if merr != nil {
+		return parseModelError(merr)
 	}
-
 	if claInfo == nil {
-		orgInfo, merr := models.GetOrgOfLink(linkID)
-		if merr != nil {
-			return parseModelError(merr)
-		}
-
-		// no contributor signed for this language. lock to avoid the cla to be changed
-		// before writing to the db.
-		unlock, fr := lockOnRepo(orgInfo)
-		if fr != nil {
-			return fr
-		}
-		defer unlock()
-
-		claInfo, merr = models.GetCLAInfoToSign(linkID, claLang, applyTo)
-		if merr != nil {
-			return parseModelError(merr)
-		}
-		if claInfo == nil {
-			return newFailedApiResult(500, errSystemError, fmt.Errorf("no cla info, impossible"))
-		}
+		return newFailedApiResult(400, errUnsupportedCLALang, fmt.Errorf("no cla of the language"))
 	}
 
 	return doSign(claInfo)
@@ -182,12 +168,14 @@ func fetchInputPayloadData(input *[]byte, info interface{}) *failedApiResult {
 
 func saveCorpCLAAtLocal(cla *models.CLACreateOpt, linkID string) *failedApiResult {
 	if cla != nil {
-		path := genCLAFilePath(linkID, dbmodels.ApplyToCorporation, cla.Language)
+		claHash := cla.CLAHash()
+
+		path := genCLAFilePath(linkID, dbmodels.ApplyToCorporation, cla.Language, claHash)
 		if err := cla.SaveCLAAtLocal(path); err != nil {
 			return newFailedApiResult(500, errSystemError, err)
 		}
 
-		path = genOrgSignatureFilePath(linkID, cla.Language)
+		path = genOrgSignatureFilePath(linkID, cla.Language, claHash)
 		if err := cla.SaveSignatueAtLocal(path); err != nil {
 			return newFailedApiResult(500, errSystemError, err)
 		}
@@ -0,0 +1,24 @@
+package dbmodels
+
+// AuditLog records who did a state-changing action on the link.
+type AuditLog struct {
+	// Actor is the user of code platform or the email of corporation manager,
+	// or the email of signer when the action is done without login.
+	Actor      string `json:"actor"`
+	RemoteAddr string `json:"remote_addr"`
+	Action     string `json:"action"`
+	LinkID     string `json:"link_id"`
+
+	// CorpID is the corporation which the target belongs to, and it is
+	// empty if the target is not in any corporation.
+	CorpID string `json:"corp_id"`
+	Target string `json:"target"`
+	Time   int64  `json:"time"`
+}
+
+// AuditLogListOption lists the logs of link, and only the logs of
+// the corporation if CorpID is not empty.
+type AuditLogListOption struct {
+	LinkID string
+	CorpID string
+}
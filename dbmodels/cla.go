@@ -5,6 +5,14 @@ const (
 	ApplyToIndividual  = "individual"
 )
 
+// The policy of a cla version says what happens to the signings of older versions.
+const (
+	// CLAPolicyKeep means the signings of older versions stay valid.
+	CLAPolicyKeep = "keep"
+	// CLAPolicyRenew means the signings of older versions must be renewed.
+	CLAPolicyRenew = "renew"
+)
+
 type CLA struct {
 	ID       string  `json:"id"`
 	Name     string  `json:"name"`
@@ -13,12 +21,30 @@ type CLA struct {
 	Fields   []Field `json:"fields"`
 }
 
+// The types of cla field, which the value of signing info is validated against.
+const (
+	FieldTypeText         = "text"
+	FieldTypeEmail        = "email"
+	FieldTypePhone        = "phone"
+	FieldTypeDate         = "date"
+	FieldTypeCountry      = "country"
+	FieldTypeSingleChoice = "single_choice"
+	FieldTypeNumber       = "number"
+)
+
 type Field struct {
 	ID          string `json:"id"`
 	Title       string `json:"title"`
 	Type        string `json:"type"`
 	Description string `json:"description"`
 	Required    bool   `json:"required"`
+
+	// Options are the choices of single_choice field.
+	Options []string `json:"options,omitempty"`
+	// Regex is the pattern which the value must match. It is optional.
+	Regex string `json:"regex,omitempty"`
+	// MaxLength is the max number of characters of the value. It is unlimited if 0.
+	MaxLength int `json:"max_length,omitempty"`
 }
 
 type CLAListOptions struct {
@@ -32,12 +58,45 @@ type CLAData struct {
 	URL      string  `json:"url"`
 	Language string  `json:"language"`
 	Fields   []Field `json:"fields"`
+	Policy   string  `json:"policy"`
 }
 
+// CLADetail is a version of cla. The one with the biggest version
+// is the current one of the language.
 type CLADetail struct {
 	CLAData
 	CLAHash string `json:"cla_hash"`
 	Text    string `json:"text"`
+	Version int    `json:"version"`
+
+	// Drift is nil if the url has not been checked.
+	Drift *CLADrift `json:"drift,omitempty"`
+}
+
+// The status of checking whether the text at the url of cla has changed.
+const (
+	CLADriftUnchanged = "unchanged"
+	CLADriftChanged   = "changed"
+	CLADriftFailed    = "failed"
+)
+
+// CLADrift is the result of the latest check on the url of cla.
+type CLADrift struct {
+	Status    string `json:"status"`
+	CheckedAt int64  `json:"checked_at"`
+
+	// URLHash is the hash of text at the url which is different from the cla.
+	// It is kept when the check fails.
+	URLHash string `json:"url_hash,omitempty"`
+
+	// Draft is the text at the url which can be added as the next version.
+	Draft string `json:"draft,omitempty"`
+}
+
+type CLAVersion struct {
+	Version int    `json:"version"`
+	CLAHash string `json:"cla_hash"`
+	Policy  string `json:"policy"`
 }
 
 type CLACreateOption struct {
@@ -0,0 +1,13 @@
+package dbmodels
+
+// CorpPDFTemplate is the templates of corporation signing pdf customized by the
+// community for a language, which replaces the default ones of the language.
+type CorpPDFTemplate struct {
+	Language    string `json:"language"`
+	Welcome     string `json:"welcome"`
+	Declaration string `json:"declaration"`
+
+	// Logo is the png or jpeg image drawn at the top of the first page. It is optional.
+	Logo     []byte `json:"-"`
+	LogoType string `json:"logo_type,omitempty"`
+}
@@ -4,10 +4,19 @@ type TypeSigningInfo map[string]string
 
 type CorporationSigningBasicInfo struct {
 	CLALanguage     string `json:"cla_language"`
+	CLAHash         string `json:"cla_hash"`
 	AdminEmail      string `json:"admin_email"`
 	AdminName       string `json:"admin_name"`
 	CorporationName string `json:"corporation_name"`
 	Date            string `json:"date"`
+
+	// ESigned means the pdf saved is the one signed electronically,
+	// and it is reset when the scanned pdf is uploaded instead.
+	ESigned bool `json:"esigned"`
+
+	// AgreementID identifies the signing publicly, and it is printed on the pdf
+	// so that anyone can verify the signing by it.
+	AgreementID string `json:"agreement_id"`
 }
 
 type CorporationSigningSummary struct {
@@ -21,3 +30,29 @@ type CorpSigningCreateOpt struct {
 
 	Info TypeSigningInfo `json:"info"`
 }
+
+const (
+	CorpSigningDeleted  = "deleted"
+	CorpSigningRestored = "restored"
+)
+
+// CorpSigningEvent is a deletion or restoration of the corporation signing.
+type CorpSigningEvent struct {
+	Action   string `json:"action"`
+	Operator string `json:"operator"`
+	Time     int64  `json:"time"`
+}
+
+// CorpAgreement is the public info of the corporation signing which is found
+// by the agreement id. It must not include any email.
+type CorpAgreement struct {
+	LinkID          string `json:"link_id"`
+	AgreementID     string `json:"agreement_id"`
+	CLALanguage     string `json:"cla_language"`
+	CLAHash         string `json:"cla_hash"`
+	CorporationName string `json:"corporation_name"`
+	Date            string `json:"date"`
+
+	// Deleted is true if the signing or the link has been deleted since.
+	Deleted bool `json:"deleted"`
+}
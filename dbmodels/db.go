@@ -23,17 +23,29 @@ type IModel interface {
 	IIndividualSigning
 	ICLA
 	IVerificationCode
+	IAuditLog
+	IResignCampaign
+	ICorpPDFTemplate
 }
 
 type ICorporationSigning interface {
 	InitializeCorpSigning(linkID string, info *OrgInfo, cla *CLAInfo) IDBError
 	SignCorpCLA(orgCLAID string, info *CorpSigningCreateOpt) IDBError
-	DeleteCorpSigning(linkID, email string) IDBError
+	// ResignCorpCLA replaces the signing of corporation whose cla hash is not info.CLAHash.
+	// It returns ErrNoDBRecord if there is no such signing.
+	ResignCorpCLA(linkID string, info *CorpSigningCreateOpt) IDBError
+	// The event is recorded in the history of corporation if it is not nil.
+	DeleteCorpSigning(linkID, email string, event *CorpSigningEvent) IDBError
+	RestoreCorpSigning(linkID, email string, event *CorpSigningEvent) IDBError
+	ListCorpSigningHistory(linkID, email string) ([]CorpSigningEvent, IDBError)
 	IsCorpSigned(linkID, email string) (bool, IDBError)
 	ListCorpSignings(linkID, language string) ([]CorporationSigningSummary, IDBError)
 	ListDeletedCorpSignings(linkID string) ([]CorporationSigningBasicInfo, IDBError)
 	GetCorpSigningDetail(linkID, email string) ([]Field, *CorpSigningCreateOpt, IDBError)
 	GetCorpSigningBasicInfo(linkID, email string) (*CorporationSigningBasicInfo, IDBError)
+	UpdateCorpSigningESigned(linkID, email string, esigned bool) IDBError
+	// GetCorpAgreement returns ErrNoDBRecord if there is no signing of the agreement id.
+	GetCorpAgreement(agreementID string) (*CorpAgreement, IDBError)
 }
 
 type IFile interface {
@@ -41,6 +53,8 @@ type IFile interface {
 	DownloadCorporationSigningPDF(linkID, email, path string) IDBError
 	IsCorporationSigningPDFUploaded(linkID, email string) (bool, IDBError)
 	ListCorporationsWithPDFUploaded(linkID string) ([]string, IDBError)
+	// DeleteCorporationSigningPDF doesn't return error if there is no pdf.
+	DeleteCorporationSigningPDF(linkID, email string) IDBError
 }
 
 type ICorporationManager interface {
@@ -62,25 +76,39 @@ type IOrgEmail interface {
 type IIndividualSigning interface {
 	InitializeIndividualSigning(linkID string, info *CLAInfo) IDBError
 	SignIndividualCLA(linkID string, info *IndividualSigningInfo) IDBError
-	DeleteIndividualSigning(linkID, email string) IDBError
+	// ResignIndividualCLA replaces the signing whose cla hash is not info.CLAHash.
+	// It returns ErrNoDBRecord if there is no such signing.
+	ResignIndividualCLA(linkID string, info *IndividualSigningInfo) IDBError
+	DeleteIndividualSigning(linkID, email string, tombstone *SigningTombstone) IDBError
+	ListDeletedIndividualSignings(linkID, corpEmail string) ([]DeletedIndividualSigning, IDBError)
+	RestoreIndividualSigning(linkID, email string) IDBError
+	PurgeDeletedIndividualSignings(deletedBefore int64) IDBError
 	UpdateIndividualSigning(linkID, email string, enabled bool) IDBError
 	IsIndividualSigned(linkID, email string) (bool, IDBError)
 	ListIndividualSigning(linkID, corpEmail, claLang string) ([]IndividualSigningBasicInfo, IDBError)
+	GetIndividualSigningDetail(linkID, email string) (*IndividualSigningInfo, IDBError)
 
 	GetCLAInfoSigned(linkID, claLang, applyTo string) (*CLAInfo, IDBError)
+	// GetCLAInfoOfVersion returns nil if there is no cla info of the version.
+	GetCLAInfoOfVersion(linkID, claLang, claHash, applyTo string) (*CLAInfo, IDBError)
 }
 
 type ICLA interface {
 	GetCLAByType(orgRepo *OrgRepo, applyTo string) (string, []CLADetail, IDBError)
 	GetAllCLA(linkID string) (*CLAOfLink, IDBError)
 	HasCLA(linkID, applyTo, language string) (bool, IDBError)
-	DownloadCorpCLAPDF(linkID, lang string) ([]byte, IDBError)
+	// ListCLAVersions returns the versions of cla sorted by version.
+	ListCLAVersions(linkID, applyTo, language string) ([]CLAVersion, IDBError)
+	DownloadCorpCLAPDF(linkID, lang string, version int) ([]byte, IDBError)
 
 	AddCLA(linkID, applyTo string, cla *CLACreateOption) IDBError
+	// DeleteCLA deletes all the versions of cla.
 	DeleteCLA(linkID, applyTo, language string) IDBError
 	DeleteCLAInfo(linkID, applyTo, claLang string) IDBError
 	AddCLAInfo(linkID, applyTo string, info *CLAInfo) IDBError
+	// GetCLAInfoToSign returns the cla info of the current version.
 	GetCLAInfoToSign(linkID, claLang, applyTo string) (*CLAInfo, IDBError)
+	UpdateCLADrift(linkID, applyTo, language string, version int, drift *CLADrift) IDBError
 }
 
 type IVerificationCode interface {
@@ -88,11 +116,43 @@ type IVerificationCode interface {
 	GetVerificationCode(opt *VerificationCode) IDBError
 }
 
+type IAuditLog interface {
+	AddAuditLog(log *AuditLog) IDBError
+	ListAuditLogs(opt *AuditLogListOption) ([]AuditLog, IDBError)
+}
+
+type IResignCampaign interface {
+	// AddResignCampaign returns the id of campaign.
+	AddResignCampaign(linkID string, campaign *ResignCampaign) (string, IDBError)
+	// ListResignCampaigns returns the campaigns sorted by the time created.
+	ListResignCampaigns(linkID string) ([]ResignCampaign, IDBError)
+	// ResignInCampaigns sets the status of signer whose type is one of signerTypes
+	// to be resigned in all the campaigns where it is pending.
+	ResignInCampaigns(linkID, email string, signerTypes []string, resignedAt int64) IDBError
+	// HasOverdueResign reports whether the signer whose type is one of signerTypes
+	// is still pending in any campaign whose deadline is before now.
+	HasOverdueResign(linkID, email string, signerTypes []string, now int64) (bool, IDBError)
+}
+
+type ICorpPDFTemplate interface {
+	// SaveCorpPDFTemplate replaces the template of the same language.
+	SaveCorpPDFTemplate(linkID string, t *CorpPDFTemplate) IDBError
+	// GetCorpPDFTemplate returns nil if the link has no template of the language.
+	GetCorpPDFTemplate(linkID, language string) (*CorpPDFTemplate, IDBError)
+	DeleteCorpPDFTemplate(linkID, language string) IDBError
+}
+
 type ILink interface {
 	GetLinkID(orgRepo *OrgRepo) (string, IDBError)
+	// HasLink checks whether the link id is used, including by the deleted link.
+	HasLink(linkID string) (bool, IDBError)
 	CreateLink(info *LinkCreateOption) (string, IDBError)
 	Unlink(linkID string) IDBError
 	GetOrgOfLink(linkID string) (*OrgInfo, IDBError)
 	ListLinks(opt *LinkListOption) ([]LinkInfo, IDBError)
 	GetAllLinks() ([]LinkInfo, IDBError)
+
+	// GetCorpSigningPolicy returns nil if the policy has not been set.
+	GetCorpSigningPolicy(linkID string) (*CorpSigningPolicy, IDBError)
+	UpdateCorpSigningPolicy(linkID string, p *CorpSigningPolicy) IDBError
 }
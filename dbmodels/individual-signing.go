@@ -1,16 +1,30 @@
 package dbmodels
 
 type IndividualSigningBasicInfo struct {
-	ID      string `json:"id"`
-	Email   string `json:"email"`
-	Name    string `json:"name"`
-	Date    string `json:"date"`
-	Enabled bool   `json:"enabled"`
+	ID          string `json:"id"`
+	Email       string `json:"email"`
+	Name        string `json:"name"`
+	Date        string `json:"date"`
+	Enabled     bool   `json:"enabled"`
+	CLALanguage string `json:"cla_language"`
+	CLAHash     string `json:"cla_hash"`
 }
 
 type IndividualSigningInfo struct {
 	IndividualSigningBasicInfo
 
-	CLALanguage string          `json:"cla_language"`
-	Info        TypeSigningInfo `json:"info"`
+	Info TypeSigningInfo `json:"info"`
+}
+
+// SigningTombstone records who deleted the signing, why and when.
+// The signing can be restored until the tombstone is purged.
+type SigningTombstone struct {
+	DeletedBy string `json:"deleted_by"`
+	Reason    string `json:"reason"`
+	DeletedAt int64  `json:"deleted_at"`
+}
+
+type DeletedIndividualSigning struct {
+	IndividualSigningBasicInfo
+	SigningTombstone
 }
@@ -18,6 +18,17 @@ type LinkCreateOption struct {
 	CorpCLAs       []CLACreateOption `json:"corp_clas"`
 }
 
+// CorpSigningPolicy is how the corporations sign the cla of a link.
+type CorpSigningPolicy struct {
+	// ESigning means the corporation can sign electronically, and then
+	// the signed pdf is generated and saved by the server directly.
+	ESigning bool `json:"esigning"`
+
+	// ScannedPDFRequired means the pdf signed on paper must still be scanned and
+	// uploaded before the administrator is added, even if it is signed electronically.
+	ScannedPDFRequired bool `json:"scanned_pdf_required"`
+}
+
 type LinkListOption struct {
 	Platform string
 	Orgs     []string
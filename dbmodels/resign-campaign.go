@@ -0,0 +1,31 @@
+package dbmodels
+
+const (
+	ResignStatusPending  = "pending"
+	ResignStatusResigned = "resigned"
+
+	SignerIndividual  = "individual"
+	SignerEmployee    = "employee"
+	SignerCorporation = "corporation"
+)
+
+// ResignCampaignSigner is the signer who signed an older version of cla.
+// Email is the email of corporation admin if the type is corporation.
+type ResignCampaignSigner struct {
+	Email       string `json:"email"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	CLALanguage string `json:"cla_language"`
+	Status      string `json:"status"`
+	ResignedAt  int64  `json:"resigned_at"`
+}
+
+// ResignCampaign asks the signers to sign the current version of cla
+// before the deadline.
+type ResignCampaign struct {
+	ID        string                 `json:"id"`
+	CreatedBy string                 `json:"created_by"`
+	CreatedAt int64                  `json:"created_at"`
+	Deadline  int64                  `json:"deadline"`
+	Signers   []ResignCampaignSigner `json:"signers"`
+}
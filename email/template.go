@@ -19,6 +19,8 @@ const (
 	TmplActivatingEmployee  = "activating employee"
 	TmplInactivaingEmployee = "inactivating employee"
 	TmplRemovingingEmployee = "removing employee"
+	TmplResignCLA           = "resign cla"
+	TmplCLADrift            = "cla drift"
 )
 
 var msgTmpl = map[string]*template.Template{}
@@ -36,6 +38,8 @@ func initTemplate() error {
 		TmplActivatingEmployee:  "./conf/email-template/activating-employee.tmpl",
 		TmplInactivaingEmployee: "./conf/email-template/inactivating-employee.tmpl",
 		TmplRemovingingEmployee: "./conf/email-template/removing-employee.tmpl",
+		TmplResignCLA:           "./conf/email-template/resign-cla.tmpl",
+		TmplCLADrift:            "./conf/email-template/cla-drift.tmpl",
 	}
 
 	for name, path := range items {
@@ -81,6 +85,11 @@ type CorporationSigning struct {
 	AdminName   string
 	ProjectURL  string
 	SigningInfo string
+
+	// ESigned means the corporation has signed electronically, and
+	// ScannedPDFRequired means the scanned pdf should still be replied.
+	ESigned            bool
+	ScannedPDFRequired bool
 }
 
 func (this CorporationSigning) GenEmailMsg() (*EmailMessage, error) {
@@ -182,3 +191,33 @@ func (this EmployeeNotification) GenEmailMsg() (*EmailMessage, error) {
 
 	return nil, fmt.Errorf("do nothing")
 }
+
+type ResignCLA struct {
+	Name       string
+	Org        string
+	ProjectURL string
+	SigningURL string
+	Deadline   string
+}
+
+func (this ResignCLA) GenEmailMsg() (*EmailMessage, error) {
+	return genEmailMsg(TmplResignCLA, this)
+}
+
+type CLADriftItem struct {
+	ApplyTo  string
+	Language string
+	URL      string
+}
+
+type CLADrift struct {
+	Org              string
+	ProjectURL       string
+	CLAs             []CLADriftItem
+	DraftSaved       bool
+	URLOfCLAPlatform string
+}
+
+func (this CLADrift) GenEmailMsg() (*EmailMessage, error) {
+	return genEmailMsg(TmplCLADrift, this)
+}
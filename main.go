@@ -10,10 +10,14 @@ import (
 	"github.com/opensourceways/app-cla-server/controllers"
 	"github.com/opensourceways/app-cla-server/dbmodels"
 	"github.com/opensourceways/app-cla-server/email"
+	"github.com/opensourceways/app-cla-server/memorydb"
 	"github.com/opensourceways/app-cla-server/mongodb"
 	"github.com/opensourceways/app-cla-server/obs"
 	_ "github.com/opensourceways/app-cla-server/obs/huaweicloud"
+	_ "github.com/opensourceways/app-cla-server/obs/local"
+	_ "github.com/opensourceways/app-cla-server/obs/s3"
 	"github.com/opensourceways/app-cla-server/pdf"
+	"github.com/opensourceways/app-cla-server/postgresql"
 	_ "github.com/opensourceways/app-cla-server/routers"
 	"github.com/opensourceways/app-cla-server/util"
 	"github.com/opensourceways/app-cla-server/worker"
@@ -40,31 +44,12 @@ func main() {
 		}
 	}
 
-	mongoClient, err := mongodb.Initialize(
-		&AppConfig.Mongodb,
-		AppConfig.SymmetricEncryptionKey,
-		AppConfig.SymmetricEncryptionNonce,
-	)
-	if err != nil {
+	if err := initDB(); err != nil {
 		beego.Error(err)
 		os.Exit(1)
 	}
 
-	obsClient, err := obs.Initialize(AppConfig.OBS)
-	if err != nil {
-		beego.Error(err)
-		os.Exit(1)
-	}
-
-	dbmodels.RegisterDB(struct {
-		dbmodels.IModel
-		dbmodels.IFile
-	}{
-		IModel: mongoClient,
-		IFile:  obs.NewFileStorage(obsClient),
-	})
-
-	if err = email.Initialize(AppConfig.EmailPlatformConfigFile); err != nil {
+	if err := email.Initialize(AppConfig.EmailPlatformConfigFile); err != nil {
 		beego.Error(err)
 		os.Exit(1)
 	}
@@ -78,12 +63,20 @@ func main() {
 		AppConfig.PythonBin,
 		AppConfig.PDFOutDir,
 		AppConfig.PDFOrgSignatureDir,
+		AppConfig.PDFCorpLangDir,
+		AppConfig.PDFSigning.CertFile,
+		AppConfig.PDFSigning.KeyFile,
+		AppConfig.AgreementVerificationURL,
 	); err != nil {
 		beego.Error(err)
 		os.Exit(1)
 	}
 
 	worker.InitEmailWorker(pdf.GetPDFGenerator())
+	worker.StartPurgingDeletedSignings(AppConfig.DeletedSigningRetention)
+	if v := AppConfig.CLADrift; v.Interval > 0 {
+		worker.StartCheckingCLADrift(v.Interval, v.SaveDraft)
+	}
 
 	if err := controllers.LoadLinks(); err != nil {
 		beego.Error(err)
@@ -92,3 +85,86 @@ func main() {
 
 	beego.Run()
 }
+
+func initDB() error {
+	AppConfig := config.AppConfig
+
+	var model dbmodels.IModel
+	var file dbmodels.IFile
+
+	switch AppConfig.DB {
+	case config.DBMemory:
+		dbmodels.RegisterDB(memorydb.Initialize())
+		return nil
+
+	case config.DBPostgresql:
+		c, err := postgresql.Initialize(
+			&AppConfig.Postgresql,
+			AppConfig.SymmetricEncryptionKey,
+			AppConfig.SymmetricEncryptionNonce,
+		)
+		if err != nil {
+			return err
+		}
+		model = c
+
+		applied, err := c.Migrate()
+		for _, item := range applied {
+			beego.Info("db migration is applied:", item)
+		}
+		if err != nil {
+			return err
+		}
+
+	default:
+		keys, keyID := AppConfig.DBEncryptionKeys()
+		c, err := mongodb.Initialize(&AppConfig.Mongodb, keys, keyID)
+		if err != nil {
+			return err
+		}
+		model = c
+
+		if AppConfig.Mongodb.SkipMigration {
+			err = c.CheckSchemaVersion()
+		} else {
+			var applied []string
+			applied, err = c.Migrate(false)
+			for _, item := range applied {
+				beego.Info("db migration is applied:", item)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		drift, err := c.CheckIndexes()
+		if err != nil {
+			return err
+		}
+		for _, item := range drift {
+			beego.Warning("index drift:", item)
+		}
+
+		if AppConfig.FileStorage == config.FileStorageGridFS {
+			file = c
+		}
+	}
+
+	if file == nil {
+		obsClient, err := obs.Initialize(AppConfig.OBS)
+		if err != nil {
+			return err
+		}
+		file = obs.NewFileStorage(obsClient)
+	}
+
+	dbmodels.RegisterDB(struct {
+		dbmodels.IModel
+		dbmodels.IFile
+	}{
+		IModel: model,
+		IFile:  file,
+	})
+
+	return nil
+}
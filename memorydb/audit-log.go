@@ -0,0 +1,34 @@
+package memorydb
+
+import (
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) AddAuditLog(log *dbmodels.AuditLog) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.auditLogs = append(this.auditLogs, *log)
+	return nil
+}
+
+func (this *client) ListAuditLogs(opt *dbmodels.AuditLogListOption) ([]dbmodels.AuditLog, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	var r []dbmodels.AuditLog
+	for i := range this.auditLogs {
+		item := &this.auditLogs[i]
+
+		if item.LinkID != opt.LinkID {
+			continue
+		}
+		if opt.CorpID != "" && item.CorpID != opt.CorpID {
+			continue
+		}
+
+		r = append(r, *item)
+	}
+
+	return r, nil
+}
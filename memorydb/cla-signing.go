@@ -0,0 +1,163 @@
+package memorydb
+
+import (
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) InitializeIndividualSigning(linkID string, claInfo *dbmodels.CLAInfo) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := &cIndividualSigning{
+		LinkID:     linkID,
+		LinkStatus: linkStatusReady,
+	}
+	if claInfo != nil {
+		doc.CLAInfos = []dbmodels.CLAInfo{copyCLAInfo(claInfo)}
+	}
+
+	if v := this.getIndividualSigningDoc(linkID); v != nil {
+		*v = *doc
+	} else {
+		this.individualSignings = append(this.individualSignings, doc)
+	}
+
+	return nil
+}
+
+func (this *client) InitializeCorpSigning(linkID string, info *dbmodels.OrgInfo, claInfo *dbmodels.CLAInfo) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := &cCorpSigning{
+		LinkID:     linkID,
+		LinkStatus: linkStatusReady,
+		OrgInfo:    *info,
+	}
+	if claInfo != nil {
+		doc.CLAInfos = []dbmodels.CLAInfo{copyCLAInfo(claInfo)}
+	}
+
+	if v := this.getCorpSigningDoc(linkID); v != nil {
+		*v = *doc
+	} else {
+		this.corpSignings = append(this.corpSignings, doc)
+	}
+
+	return nil
+}
+
+// claInfosOfSigning returns the cla infos of the signing doc. The second
+// return value reports whether there is a signing in the language of claLang.
+func (this *client) claInfosOfSigning(linkID, applyTo, claLang string) (*[]dbmodels.CLAInfo, bool) {
+	if applyTo == dbmodels.ApplyToCorporation {
+		doc := this.getCorpSigningDoc(linkID)
+		if doc == nil {
+			return nil, false
+		}
+
+		for i := range doc.Signings {
+			if doc.Signings[i].CLALanguage == claLang {
+				return &doc.CLAInfos, true
+			}
+		}
+		return &doc.CLAInfos, false
+	}
+
+	doc := this.getIndividualSigningDoc(linkID)
+	if doc == nil {
+		return nil, false
+	}
+
+	for i := range doc.Signings {
+		if doc.Signings[i].CLALanguage == claLang {
+			return &doc.CLAInfos, true
+		}
+	}
+	return &doc.CLAInfos, false
+}
+
+// findCLAInfo returns the index of cla info of the version whose hash is claHash.
+// It returns the latest one of the language if claHash is empty.
+func findCLAInfo(infos []dbmodels.CLAInfo, claLang, claHash string) int {
+	for i := len(infos) - 1; i >= 0; i-- {
+		if item := &infos[i]; item.CLALang == claLang && (claHash == "" || item.CLAHash == claHash) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (this *client) DeleteCLAInfo(linkID, applyTo, claLang string) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	infos, _ := this.claInfosOfSigning(linkID, applyTo, claLang)
+	if infos == nil {
+		return errNoDBRecord
+	}
+
+	v := (*infos)[:0]
+	for _, item := range *infos {
+		if item.CLALang != claLang {
+			v = append(v, item)
+		}
+	}
+	*infos = v
+	return nil
+}
+
+func (this *client) AddCLAInfo(linkID, applyTo string, info *dbmodels.CLAInfo) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	infos, _ := this.claInfosOfSigning(linkID, applyTo, info.CLALang)
+	if infos == nil || findCLAInfo(*infos, info.CLALang, info.CLAHash) >= 0 {
+		return errNoDBRecord
+	}
+
+	*infos = append(*infos, copyCLAInfo(info))
+	return nil
+}
+
+func (this *client) GetCLAInfoSigned(linkID, claLang, applyTo string) (*dbmodels.CLAInfo, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	infos, signed := this.claInfosOfSigning(linkID, applyTo, claLang)
+	if !signed {
+		return nil, errNoDBRecord
+	}
+
+	i := findCLAInfo(*infos, claLang, "")
+	if i < 0 {
+		return nil, nil
+	}
+
+	r := copyCLAInfo(&(*infos)[i])
+	return &r, nil
+}
+
+func (this *client) GetCLAInfoOfVersion(linkID, claLang, claHash, applyTo string) (*dbmodels.CLAInfo, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	infos, _ := this.claInfosOfSigning(linkID, applyTo, claLang)
+	if infos == nil {
+		return nil, errNoDBRecord
+	}
+
+	i := findCLAInfo(*infos, claLang, claHash)
+	if i < 0 {
+		return nil, nil
+	}
+
+	r := copyCLAInfo(&(*infos)[i])
+	return &r, nil
+}
+
+func copyCLAInfo(info *dbmodels.CLAInfo) dbmodels.CLAInfo {
+	r := *info
+	r.Fields = copyFields(info.Fields)
+	return r
+}
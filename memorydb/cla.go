@@ -0,0 +1,226 @@
+package memorydb
+
+import (
+	"sort"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (doc *cLink) clas(applyTo string) *[]dCLA {
+	if applyTo == dbmodels.ApplyToCorporation {
+		return &doc.CorpCLAs
+	}
+	return &doc.IndividualCLAs
+}
+
+// findCLA returns the index of the current version of cla.
+func findCLA(clas []dCLA, language string) int {
+	r := -1
+	for i := range clas {
+		if clas[i].Language == language && (r < 0 || clas[i].Version > clas[r].Version) {
+			r = i
+		}
+	}
+	return r
+}
+
+func (this *client) HasCLA(linkID, applyTo, language string) (bool, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return false, errNoDBRecord
+	}
+
+	return findCLA(*doc.clas(applyTo), language) >= 0, nil
+}
+
+func (this *client) AddCLA(linkID, applyTo string, cla *dbmodels.CLACreateOption) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	// the same content can't be added as a new version
+	clas := doc.clas(applyTo)
+	for i := range *clas {
+		if item := &(*clas)[i]; item.Language == cla.Language && item.CLAHash == cla.CLAHash {
+			return errNoDBRecord
+		}
+	}
+
+	*clas = append(*clas, toDocOfCLA(cla))
+	return nil
+}
+
+func (this *client) ListCLAVersions(linkID, applyTo, language string) ([]dbmodels.CLAVersion, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	var r []dbmodels.CLAVersion
+	for _, item := range *doc.clas(applyTo) {
+		if item.Language == language {
+			r = append(r, dbmodels.CLAVersion{
+				Version: item.Version,
+				CLAHash: item.CLAHash,
+				Policy:  item.Policy,
+			})
+		}
+	}
+
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Version < r[j].Version
+	})
+	return r, nil
+}
+
+func (this *client) DeleteCLA(linkID, applyTo, language string) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	clas := doc.clas(applyTo)
+	v := (*clas)[:0]
+	for _, item := range *clas {
+		if item.Language != language {
+			v = append(v, item)
+		}
+	}
+	*clas = v
+	return nil
+}
+
+func (this *client) GetCLAByType(orgRepo *dbmodels.OrgRepo, applyTo string) (string, []dbmodels.CLADetail, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getLink(filterOfLink(orgRepo))
+	if doc == nil {
+		return "", nil, errNoDBRecord
+	}
+
+	return doc.LinkID, toModelOfCLAs(*doc.clas(applyTo)), nil
+}
+
+func (this *client) GetAllCLA(linkID string) (*dbmodels.CLAOfLink, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	return &dbmodels.CLAOfLink{
+		IndividualCLAs: toModelOfCLAs(doc.IndividualCLAs),
+		CorpCLAs:       toModelOfCLAs(doc.CorpCLAs),
+	}, nil
+}
+
+func (this *client) GetCLAInfoToSign(linkID, claLang, applyTo string) (*dbmodels.CLAInfo, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	clas := *doc.clas(applyTo)
+	i := findCLA(clas, claLang)
+	if i < 0 {
+		return nil, nil
+	}
+
+	item := &clas[i]
+	return &dbmodels.CLAInfo{
+		CLALang:          item.Language,
+		CLAHash:          item.CLAHash,
+		OrgSignatureHash: item.OrgSignatureHash,
+		Fields:           copyFields(item.Fields),
+	}, nil
+}
+
+func (this *client) DownloadCorpCLAPDF(linkID, lang string, version int) ([]byte, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	for i := range doc.CorpCLAs {
+		if item := &doc.CorpCLAs[i]; item.Language == lang && item.Version == version {
+			return copyBytes(item.OrgSignature), nil
+		}
+	}
+	return nil, nil
+}
+
+func (this *client) UpdateCLADrift(linkID, applyTo, language string, version int, drift *dbmodels.CLADrift) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	clas := *doc.clas(applyTo)
+	for i := range clas {
+		if item := &clas[i]; item.Language == language && item.Version == version {
+			d := *drift
+			item.Drift = &d
+			return nil
+		}
+	}
+	return errNoDBRecord
+}
+
+func toDocOfCLA(cla *dbmodels.CLACreateOption) dCLA {
+	r := dCLA{
+		CLADetail:        cla.CLADetail,
+		OrgSignatureHash: cla.OrgSignatureHash,
+	}
+	r.Fields = copyFields(cla.Fields)
+	r.Drift = nil
+
+	if cla.OrgSignature != nil {
+		r.OrgSignature = copyBytes(*cla.OrgSignature)
+	}
+
+	return r
+}
+
+func toModelOfCLAs(data []dCLA) []dbmodels.CLADetail {
+	if data == nil {
+		return nil
+	}
+
+	r := make([]dbmodels.CLADetail, 0, len(data))
+	for i := range data {
+		item := data[i].CLADetail
+		item.Fields = copyFields(item.Fields)
+		if v := item.Drift; v != nil {
+			d := *v
+			item.Drift = &d
+		}
+
+		r = append(r, item)
+	}
+	return r
+}
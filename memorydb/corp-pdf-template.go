@@ -0,0 +1,71 @@
+package memorydb
+
+import (
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func copyCorpPDFTemplate(t *dbmodels.CorpPDFTemplate) dbmodels.CorpPDFTemplate {
+	r := *t
+	if t.Logo != nil {
+		r.Logo = copyBytes(t.Logo)
+	}
+	return r
+}
+
+func (this *client) SaveCorpPDFTemplate(linkID string, t *dbmodels.CorpPDFTemplate) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	v := copyCorpPDFTemplate(t)
+	for i := range doc.CorpPDFTemplates {
+		if doc.CorpPDFTemplates[i].Language == t.Language {
+			doc.CorpPDFTemplates[i] = v
+			return nil
+		}
+	}
+
+	doc.CorpPDFTemplates = append(doc.CorpPDFTemplates, v)
+	return nil
+}
+
+func (this *client) GetCorpPDFTemplate(linkID, language string) (*dbmodels.CorpPDFTemplate, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	for i := range doc.CorpPDFTemplates {
+		if item := &doc.CorpPDFTemplates[i]; item.Language == language {
+			v := copyCorpPDFTemplate(item)
+			return &v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (this *client) DeleteCorpPDFTemplate(linkID, language string) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	v := doc.CorpPDFTemplates[:0]
+	for _, item := range doc.CorpPDFTemplates {
+		if item.Language != language {
+			v = append(v, item)
+		}
+	}
+	doc.CorpPDFTemplates = v
+	return nil
+}
@@ -0,0 +1,36 @@
+package memorydb
+
+import (
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) GetCorpSigningPolicy(linkID string) (*dbmodels.CorpSigningPolicy, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	if doc.CorpSigningPolicy == nil {
+		return nil, nil
+	}
+
+	r := *doc.CorpSigningPolicy
+	return &r, nil
+}
+
+func (this *client) UpdateCorpSigningPolicy(linkID string, p *dbmodels.CorpSigningPolicy) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	v := *p
+	doc.CorpSigningPolicy = &v
+	return nil
+}
@@ -0,0 +1,149 @@
+package memorydb
+
+import (
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func findCorpManager(managers []dCorpManager, email string) int {
+	corpID := genCorpID(email)
+	for i := range managers {
+		if item := &managers[i]; item.CorpID == corpID && item.Email == email {
+			return i
+		}
+	}
+	return -1
+}
+
+func (this *client) AddCorpAdministrator(linkID string, opt *dbmodels.CorporationManagerCreateOption) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	corpID := genCorpID(opt.Email)
+	for i := range doc.Managers {
+		if item := &doc.Managers[i]; item.CorpID == corpID && item.Role == dbmodels.RoleAdmin {
+			return errNoDBRecord
+		}
+	}
+
+	m := dCorpManager{
+		CorpID:                         corpID,
+		CorporationManagerCreateOption: *opt,
+	}
+	m.Role = dbmodels.RoleAdmin
+
+	doc.Managers = append(doc.Managers, m)
+	return nil
+}
+
+func (this *client) CheckCorporationManagerExist(opt dbmodels.CorporationManagerCheckInfo) (map[string]dbmodels.CorporationManagerCheckResult, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	match := func(item *dCorpManager) bool {
+		if opt.Email != "" {
+			return item.CorpID == genCorpID(opt.Email) && item.Email == opt.Email
+		}
+		return item.CorpID == opt.EmailSuffix && item.ID == opt.ID
+	}
+
+	result := map[string]dbmodels.CorporationManagerCheckResult{}
+	for _, doc := range this.corpSignings {
+		if doc.LinkStatus != linkStatusReady {
+			continue
+		}
+
+		for i := range doc.Managers {
+			item := &doc.Managers[i]
+			if !match(item) {
+				continue
+			}
+
+			result[doc.LinkID] = dbmodels.CorporationManagerCheckResult{
+				Name:             item.Name,
+				Email:            item.Email,
+				Role:             item.Role,
+				Password:         item.Password,
+				InitialPWChanged: item.InitialPWChanged,
+				OrgInfo:          doc.OrgInfo,
+			}
+			break
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func (this *client) ResetCorporationManagerPassword(linkID, email string, opt dbmodels.CorporationManagerResetPassword) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	i := findCorpManager(doc.Managers, email)
+	if i < 0 || doc.Managers[i].Password != opt.OldPassword {
+		return errNoDBRecord
+	}
+
+	item := &doc.Managers[i]
+	item.Password = opt.NewPassword
+	item.InitialPWChanged = true
+	return nil
+}
+
+func (this *client) ListCorporationManager(linkID, email, role string) ([]dbmodels.CorporationManagerListResult, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	corpID := genCorpID(email)
+
+	var r []dbmodels.CorporationManagerListResult
+	for i := range doc.Managers {
+		item := &doc.Managers[i]
+		if item.CorpID != corpID || (role != "" && item.Role != role) {
+			continue
+		}
+
+		r = append(r, dbmodels.CorporationManagerListResult{
+			ID:    item.ID,
+			Name:  item.Name,
+			Email: item.Email,
+			Role:  item.Role,
+		})
+	}
+	return r, nil
+}
+
+func (this *client) GetCorporationManager(linkID, email string) (*dbmodels.CorporationManagerCheckResult, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	i := findCorpManager(doc.Managers, email)
+	if i < 0 {
+		return nil, nil
+	}
+
+	return &dbmodels.CorporationManagerCheckResult{
+		Password: doc.Managers[i].Password,
+	}, nil
+}
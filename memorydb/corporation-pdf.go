@@ -0,0 +1,67 @@
+package memorydb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func keyOfCorpSigningPDF(linkID, email string) string {
+	return fmt.Sprintf("%s/%s", linkID, genCorpID(email))
+}
+
+func (this *client) UploadCorporationSigningPDF(linkID, adminEmail string, pdf []byte) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.corpPDFs[keyOfCorpSigningPDF(linkID, adminEmail)] = copyBytes(pdf)
+	return nil
+}
+
+func (this *client) DownloadCorporationSigningPDF(linkID, email, path string) dbmodels.IDBError {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	pdf, ok := this.corpPDFs[keyOfCorpSigningPDF(linkID, email)]
+	if !ok {
+		return errNoDBRecord
+	}
+
+	if err := ioutil.WriteFile(path, pdf, 0644); err != nil {
+		return dbmodels.NewDBError(dbmodels.ErrSystemError, err)
+	}
+	return nil
+}
+
+func (this *client) IsCorporationSigningPDFUploaded(linkID, email string) (bool, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	_, ok := this.corpPDFs[keyOfCorpSigningPDF(linkID, email)]
+	return ok, nil
+}
+
+func (this *client) ListCorporationsWithPDFUploaded(linkID string) ([]string, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	prefix := linkID + "/"
+
+	var r []string
+	for k := range this.corpPDFs {
+		if strings.HasPrefix(k, prefix) {
+			r = append(r, strings.TrimPrefix(k, prefix))
+		}
+	}
+	return r, nil
+}
+
+func (this *client) DeleteCorporationSigningPDF(linkID, email string) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	delete(this.corpPDFs, keyOfCorpSigningPDF(linkID, email))
+	return nil
+}
@@ -0,0 +1,107 @@
+package memorydb
+
+import (
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (doc *cCorpSigning) addEvent(corpID string, event *dbmodels.CorpSigningEvent) {
+	if event == nil {
+		return
+	}
+
+	if doc.History == nil {
+		doc.History = map[string][]dbmodels.CorpSigningEvent{}
+	}
+	doc.History[corpID] = append(doc.History[corpID], *event)
+}
+
+func (this *client) DeleteCorpSigning(linkID, email string, event *dbmodels.CorpSigningEvent) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	i := findCorpSigning(doc.Signings, email)
+	if i < 0 {
+		return nil
+	}
+
+	doc.addEvent(doc.Signings[i].CorpID, event)
+	doc.Deleted = append(doc.Deleted, doc.Signings[i])
+	doc.Signings = append(doc.Signings[:i], doc.Signings[i+1:]...)
+	return nil
+}
+
+func (this *client) RestoreCorpSigning(linkID, email string, event *dbmodels.CorpSigningEvent) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	if findCorpSigning(doc.Signings, email) >= 0 {
+		return dbmodels.NewDBError(dbmodels.ErrRecordExists, fmt.Errorf("the corporation has signed again"))
+	}
+
+	// restore the latest deleted one
+	corpID := genCorpID(email)
+	for i := len(doc.Deleted) - 1; i >= 0; i-- {
+		if doc.Deleted[i].CorpID != corpID {
+			continue
+		}
+
+		doc.addEvent(corpID, event)
+		doc.Signings = append(doc.Signings, doc.Deleted[i])
+		doc.Deleted = append(doc.Deleted[:i], doc.Deleted[i+1:]...)
+		return nil
+	}
+
+	return errNoDBRecord
+}
+
+func (this *client) ListCorpSigningHistory(linkID, email string) ([]dbmodels.CorpSigningEvent, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	v := doc.History[genCorpID(email)]
+	if len(v) == 0 {
+		return nil, nil
+	}
+
+	r := make([]dbmodels.CorpSigningEvent, len(v))
+	copy(r, v)
+	return r, nil
+}
+
+func (this *client) ListDeletedCorpSignings(linkID string) ([]dbmodels.CorporationSigningBasicInfo, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	n := len(doc.Deleted)
+	if n == 0 {
+		return nil, nil
+	}
+
+	r := make([]dbmodels.CorporationSigningBasicInfo, 0, n)
+	for i := range doc.Deleted {
+		r = append(r, doc.Deleted[i].CorporationSigningBasicInfo)
+	}
+	return r, nil
+}
@@ -0,0 +1,197 @@
+package memorydb
+
+import (
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func findCorpSigning(signings []dCorpSigning, email string) int {
+	corpID := genCorpID(email)
+	for i := range signings {
+		if signings[i].CorpID == corpID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (this *client) SignCorpCLA(linkID string, info *dbmodels.CorpSigningCreateOpt) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil || findCorpSigning(doc.Signings, info.AdminEmail) >= 0 {
+		return errNoDBRecord
+	}
+
+	signing := dCorpSigning{
+		CorpID:               genCorpID(info.AdminEmail),
+		CorpSigningCreateOpt: *info,
+	}
+	signing.Info = copySigningInfo(info.Info)
+
+	doc.Signings = append(doc.Signings, signing)
+	return nil
+}
+
+func (this *client) ResignCorpCLA(linkID string, info *dbmodels.CorpSigningCreateOpt) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	i := findCorpSigning(doc.Signings, info.AdminEmail)
+	if i < 0 {
+		return errNoDBRecord
+	}
+
+	item := &doc.Signings[i]
+	if item.CLAHash == info.CLAHash || item.AdminEmail != info.AdminEmail {
+		return errNoDBRecord
+	}
+
+	item.CorpSigningCreateOpt = *info
+	item.Info = copySigningInfo(info.Info)
+	return nil
+}
+
+func (this *client) ListCorpSignings(linkID, language string) ([]dbmodels.CorporationSigningSummary, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	admins := map[string]bool{}
+	for i := range doc.Managers {
+		if item := &doc.Managers[i]; item.Role == dbmodels.RoleAdmin {
+			admins[item.Email] = true
+		}
+	}
+
+	var r []dbmodels.CorporationSigningSummary
+	for i := range doc.Signings {
+		item := &doc.Signings[i]
+		if language != "" && item.CLALanguage != language {
+			continue
+		}
+
+		r = append(r, dbmodels.CorporationSigningSummary{
+			CorporationSigningBasicInfo: item.CorporationSigningBasicInfo,
+			AdminAdded:                  admins[item.AdminEmail],
+		})
+	}
+
+	return r, nil
+}
+
+func (this *client) IsCorpSigned(linkID, email string) (bool, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return false, nil
+	}
+
+	return findCorpSigning(doc.Signings, email) >= 0, nil
+}
+
+func (this *client) GetCorpSigningBasicInfo(linkID, email string) (*dbmodels.CorporationSigningBasicInfo, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	i := findCorpSigning(doc.Signings, email)
+	if i < 0 {
+		return nil, nil
+	}
+
+	r := doc.Signings[i].CorporationSigningBasicInfo
+	return &r, nil
+}
+
+func (this *client) GetCorpSigningDetail(linkID, email string) ([]dbmodels.Field, *dbmodels.CorpSigningCreateOpt, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return nil, nil, errNoDBRecord
+	}
+
+	i := findCorpSigning(doc.Signings, email)
+	if i < 0 {
+		return nil, nil, errNoDBRecord
+	}
+	signing := &doc.Signings[i]
+
+	j := findCLAInfo(doc.CLAInfos, signing.CLALanguage, signing.CLAHash)
+	if j < 0 {
+		return nil, nil, nil
+	}
+
+	info := signing.CorpSigningCreateOpt
+	info.Info = copySigningInfo(signing.Info)
+
+	return copyFields(doc.CLAInfos[j].Fields), &info, nil
+}
+
+func (this *client) UpdateCorpSigningESigned(linkID, email string, esigned bool) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	i := findCorpSigning(doc.Signings, email)
+	if i < 0 {
+		return errNoDBRecord
+	}
+
+	doc.Signings[i].ESigned = esigned
+	return nil
+}
+
+func (this *client) GetCorpAgreement(agreementID string) (*dbmodels.CorpAgreement, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	toAgreement := func(doc *cCorpSigning, item *dCorpSigning, deleted bool) *dbmodels.CorpAgreement {
+		return &dbmodels.CorpAgreement{
+			LinkID:          doc.LinkID,
+			AgreementID:     item.AgreementID,
+			CLALanguage:     item.CLALanguage,
+			CLAHash:         item.CLAHash,
+			CorporationName: item.CorporationName,
+			Date:            item.Date,
+			Deleted:         deleted || doc.LinkStatus != linkStatusReady,
+		}
+	}
+
+	for _, doc := range this.corpSignings {
+		for i := range doc.Signings {
+			if item := &doc.Signings[i]; item.AgreementID == agreementID {
+				return toAgreement(doc, item, false), nil
+			}
+		}
+
+		for i := range doc.Deleted {
+			if item := &doc.Deleted[i]; item.AgreementID == agreementID {
+				return toAgreement(doc, item, true), nil
+			}
+		}
+	}
+
+	return nil, errNoDBRecord
+}
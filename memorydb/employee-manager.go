@@ -0,0 +1,57 @@
+package memorydb
+
+import (
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) AddEmployeeManager(linkID string, opt []dbmodels.CorporationManagerCreateOption) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	for i := range opt {
+		item := &opt[i]
+
+		doc.Managers = append(doc.Managers, dCorpManager{
+			CorpID:                         genCorpID(item.Email),
+			CorporationManagerCreateOption: *item,
+		})
+	}
+	return nil
+}
+
+func (this *client) DeleteEmployeeManager(linkID string, emails []string) ([]dbmodels.CorporationManagerCreateOption, dbmodels.IDBError) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getCorpSigningDoc(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	toDelete := map[string]bool{}
+	for _, item := range emails {
+		toDelete[item] = true
+	}
+	corpID := genCorpID(emails[0])
+
+	deleted := make([]dbmodels.CorporationManagerCreateOption, 0, len(emails))
+	ms := make([]dCorpManager, 0, len(doc.Managers))
+	for _, item := range doc.Managers {
+		if item.CorpID == corpID && toDelete[item.Email] {
+			deleted = append(deleted, dbmodels.CorporationManagerCreateOption{
+				Email: item.Email,
+				Name:  item.Name,
+			})
+		} else {
+			ms = append(ms, item)
+		}
+	}
+	doc.Managers = ms
+
+	return deleted, nil
+}
@@ -0,0 +1,239 @@
+package memorydb
+
+import (
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func findIndividualSigning(signings []dIndividualSigning, email string) int {
+	corpID := genCorpID(email)
+	for i := range signings {
+		if item := &signings[i]; item.CorpID == corpID && item.Email == email {
+			return i
+		}
+	}
+	return -1
+}
+
+func (this *client) SignIndividualCLA(linkID string, info *dbmodels.IndividualSigningInfo) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getIndividualSigningDoc(linkID)
+	if doc == nil || findIndividualSigning(doc.Signings, info.Email) >= 0 {
+		return errNoDBRecord
+	}
+
+	signing := dIndividualSigning{
+		CorpID:                genCorpID(info.Email),
+		IndividualSigningInfo: *info,
+	}
+	signing.Info = copySigningInfo(info.Info)
+
+	doc.Signings = append(doc.Signings, signing)
+	return nil
+}
+
+func (this *client) ResignIndividualCLA(linkID string, info *dbmodels.IndividualSigningInfo) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getIndividualSigningDoc(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	i := findIndividualSigning(doc.Signings, info.Email)
+	if i < 0 || doc.Signings[i].CLAHash == info.CLAHash {
+		return errNoDBRecord
+	}
+
+	// the enabled status is managed by the corporation for the employee
+	item := &doc.Signings[i]
+	enabled := item.Enabled
+	item.IndividualSigningInfo = *info
+	item.Info = copySigningInfo(info.Info)
+	item.Enabled = enabled
+	return nil
+}
+
+func (this *client) DeleteIndividualSigning(linkID, email string, tombstone *dbmodels.SigningTombstone) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getIndividualSigningDoc(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	i := findIndividualSigning(doc.Signings, email)
+	if i < 0 {
+		return nil
+	}
+
+	// only the latest tombstone of the individual is kept.
+	if j := findIndividualSigning(doc.Deleted, email); j >= 0 {
+		doc.Deleted = append(doc.Deleted[:j], doc.Deleted[j+1:]...)
+	}
+
+	item := doc.Signings[i]
+	item.Tombstone = *tombstone
+
+	doc.Deleted = append(doc.Deleted, item)
+	doc.Signings = append(doc.Signings[:i], doc.Signings[i+1:]...)
+	return nil
+}
+
+func (this *client) ListDeletedIndividualSignings(linkID, corpEmail string) ([]dbmodels.DeletedIndividualSigning, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getIndividualSigningDoc(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	corpID := ""
+	if corpEmail != "" {
+		corpID = genCorpID(corpEmail)
+	}
+
+	var r []dbmodels.DeletedIndividualSigning
+	for i := range doc.Deleted {
+		item := &doc.Deleted[i]
+		if corpID != "" && item.CorpID != corpID {
+			continue
+		}
+
+		r = append(r, dbmodels.DeletedIndividualSigning{
+			IndividualSigningBasicInfo: item.IndividualSigningBasicInfo,
+			SigningTombstone:           item.Tombstone,
+		})
+	}
+
+	return r, nil
+}
+
+func (this *client) RestoreIndividualSigning(linkID, email string) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getIndividualSigningDoc(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	i := findIndividualSigning(doc.Deleted, email)
+	if i < 0 {
+		return errNoDBRecord
+	}
+
+	if findIndividualSigning(doc.Signings, email) >= 0 {
+		return dbmodels.NewDBError(dbmodels.ErrRecordExists, fmt.Errorf("the individual has signed again"))
+	}
+
+	item := doc.Deleted[i]
+	item.Tombstone = dbmodels.SigningTombstone{}
+
+	doc.Signings = append(doc.Signings, item)
+	doc.Deleted = append(doc.Deleted[:i], doc.Deleted[i+1:]...)
+	return nil
+}
+
+func (this *client) PurgeDeletedIndividualSignings(deletedBefore int64) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for _, doc := range this.individualSignings {
+		kept := doc.Deleted[:0]
+		for i := range doc.Deleted {
+			if doc.Deleted[i].Tombstone.DeletedAt >= deletedBefore {
+				kept = append(kept, doc.Deleted[i])
+			}
+		}
+		doc.Deleted = kept
+	}
+
+	return nil
+}
+
+func (this *client) UpdateIndividualSigning(linkID, email string, enabled bool) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	doc := this.getIndividualSigningDoc(linkID)
+	if doc == nil {
+		return errNoDBRecord
+	}
+
+	i := findIndividualSigning(doc.Signings, email)
+	if i < 0 {
+		return errNoDBRecord
+	}
+
+	doc.Signings[i].Enabled = enabled
+	return nil
+}
+
+func (this *client) IsIndividualSigned(linkID, email string) (bool, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getIndividualSigningDoc(linkID)
+	if doc == nil {
+		return false, nil
+	}
+
+	i := findIndividualSigning(doc.Signings, email)
+	return i >= 0 && doc.Signings[i].Enabled, nil
+}
+
+func (this *client) ListIndividualSigning(linkID, corpEmail, claLang string) ([]dbmodels.IndividualSigningBasicInfo, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getIndividualSigningDoc(linkID)
+	if doc == nil {
+		return nil, nil
+	}
+
+	corpID := ""
+	if corpEmail != "" {
+		corpID = genCorpID(corpEmail)
+	}
+
+	r := make([]dbmodels.IndividualSigningBasicInfo, 0, len(doc.Signings))
+	for i := range doc.Signings {
+		item := &doc.Signings[i]
+		if corpID != "" && item.CorpID != corpID {
+			continue
+		}
+		if claLang != "" && item.CLALanguage != claLang {
+			continue
+		}
+
+		r = append(r, item.IndividualSigningBasicInfo)
+	}
+
+	return r, nil
+}
+
+func (this *client) GetIndividualSigningDetail(linkID, email string) (*dbmodels.IndividualSigningInfo, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getIndividualSigningDoc(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	i := findIndividualSigning(doc.Signings, email)
+	if i < 0 {
+		return nil, nil
+	}
+
+	r := doc.Signings[i].IndividualSigningInfo
+	r.Info = copySigningInfo(r.Info)
+	return &r, nil
+}
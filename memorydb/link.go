@@ -0,0 +1,155 @@
+package memorydb
+
+import (
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func filterOfLink(orgRepo *dbmodels.OrgRepo) func(*cLink) bool {
+	return func(doc *cLink) bool {
+		return doc.OrgInfo.OrgRepo == *orgRepo
+	}
+}
+
+func (this *client) GetLinkID(orgRepo *dbmodels.OrgRepo) (string, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getLink(filterOfLink(orgRepo))
+	if doc == nil {
+		return "", errNoDBRecord
+	}
+	return doc.LinkID, nil
+}
+
+func (this *client) HasLink(linkID string) (bool, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	for _, item := range this.links {
+		if item.LinkID == linkID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (this *client) CreateLink(info *dbmodels.LinkCreateOption) (string, dbmodels.IDBError) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.getLink(filterOfLink(&info.OrgRepo)) != nil {
+		return "", dbmodels.NewDBError(dbmodels.ErrRecordExists, fmt.Errorf("the doc exists"))
+	}
+
+	this.links = append(this.links, &cLink{
+		LinkID:     info.LinkID,
+		LinkStatus: linkStatusReady,
+		Submitter:  info.Submitter,
+		OrgInfo: dbmodels.OrgInfo{
+			OrgRepo:  info.OrgRepo,
+			OrgAlias: info.OrgAlias,
+			OrgEmail: info.OrgEmail.Email,
+		},
+		OrgEmail: dbmodels.OrgEmailCreateInfo{
+			Email:    info.OrgEmail.Email,
+			Platform: info.OrgEmail.Platform,
+			Token:    copyBytes(info.OrgEmail.Token),
+		},
+		IndividualCLAs: toDocOfCLAs(info.IndividualCLAs),
+		CorpCLAs:       toDocOfCLAs(info.CorpCLAs),
+	})
+
+	return info.LinkID, nil
+}
+
+func (this *client) Unlink(linkID string) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	found := false
+	for _, item := range this.links {
+		if item.LinkID == linkID {
+			item.LinkStatus = linkStatusDeleted
+			found = true
+		}
+	}
+	if !found {
+		return errNoDBRecord
+	}
+
+	for _, item := range this.corpSignings {
+		if item.LinkID == linkID {
+			item.LinkStatus = linkStatusDeleted
+		}
+	}
+
+	for _, item := range this.individualSignings {
+		if item.LinkID == linkID {
+			item.LinkStatus = linkStatusDeleted
+		}
+	}
+
+	return nil
+}
+
+func (this *client) GetOrgOfLink(linkID string) (*dbmodels.OrgInfo, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	r := doc.OrgInfo
+	return &r, nil
+}
+
+func (this *client) ListLinks(opt *dbmodels.LinkListOption) ([]dbmodels.LinkInfo, dbmodels.IDBError) {
+	orgs := map[string]bool{}
+	for _, item := range opt.Orgs {
+		orgs[item] = true
+	}
+
+	return this.getAllLinks(func(doc *cLink) bool {
+		return doc.OrgInfo.Platform == opt.Platform && orgs[doc.OrgInfo.OrgID]
+	})
+}
+
+func (this *client) GetAllLinks() ([]dbmodels.LinkInfo, dbmodels.IDBError) {
+	return this.getAllLinks(func(*cLink) bool { return true })
+}
+
+func (this *client) getAllLinks(filter func(*cLink) bool) ([]dbmodels.LinkInfo, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	var r []dbmodels.LinkInfo
+	for _, item := range this.links {
+		if item.LinkStatus != linkStatusReady || !filter(item) {
+			continue
+		}
+
+		r = append(r, dbmodels.LinkInfo{
+			LinkID:    item.LinkID,
+			OrgInfo:   item.OrgInfo,
+			Submitter: item.Submitter,
+		})
+	}
+
+	return r, nil
+}
+
+func toDocOfCLAs(v []dbmodels.CLACreateOption) []dCLA {
+	if len(v) == 0 {
+		return nil
+	}
+
+	r := make([]dCLA, 0, len(v))
+	for i := range v {
+		r = append(r, toDocOfCLA(&v[i]))
+	}
+	return r
+}
@@ -0,0 +1,110 @@
+package memorydb
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+var _ dbmodels.IModel = (*client)(nil)
+var _ dbmodels.IFile = (*client)(nil)
+
+var errNoDBRecord = dbmodels.NewDBError(dbmodels.ErrNoDBRecord, fmt.Errorf("no record"))
+
+// client keeps all the data in the process. It is used for development
+// and test when there is no mongodb, so it does not encrypt the data.
+type client struct {
+	lock sync.RWMutex
+
+	links              []*cLink
+	corpSignings       []*cCorpSigning
+	individualSignings []*cIndividualSigning
+	orgEmails          map[string]dbmodels.OrgEmailCreateInfo
+	vcs                []dbmodels.VerificationCode
+	corpPDFs           map[string][]byte
+	auditLogs          []dbmodels.AuditLog
+	resignCampaigns    []*cResignCampaign
+}
+
+func Initialize() *client {
+	return &client{
+		orgEmails: map[string]dbmodels.OrgEmailCreateInfo{},
+		corpPDFs:  map[string][]byte{},
+	}
+}
+
+func genCorpID(email string) string {
+	return util.EmailSuffix(email)
+}
+
+func (this *client) getLink(filter func(*cLink) bool) *cLink {
+	for _, item := range this.links {
+		if item.LinkStatus == linkStatusReady && filter(item) {
+			return item
+		}
+	}
+	return nil
+}
+
+func (this *client) getLinkByID(linkID string) *cLink {
+	return this.getLink(func(doc *cLink) bool {
+		return doc.LinkID == linkID
+	})
+}
+
+func (this *client) getCorpSigningDoc(linkID string) *cCorpSigning {
+	for _, item := range this.corpSignings {
+		if item.LinkID == linkID && item.LinkStatus == linkStatusReady {
+			return item
+		}
+	}
+	return nil
+}
+
+func (this *client) getIndividualSigningDoc(linkID string) *cIndividualSigning {
+	for _, item := range this.individualSignings {
+		if item.LinkID == linkID && item.LinkStatus == linkStatusReady {
+			return item
+		}
+	}
+	return nil
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	r := make([]byte, len(b))
+	copy(r, b)
+	return r
+}
+
+func copyFields(fs []dbmodels.Field) []dbmodels.Field {
+	if fs == nil {
+		return nil
+	}
+
+	r := make([]dbmodels.Field, len(fs))
+	copy(r, fs)
+	for i := range r {
+		if v := r[i].Options; v != nil {
+			r[i].Options = append([]string{}, v...)
+		}
+	}
+	return r
+}
+
+func copySigningInfo(info dbmodels.TypeSigningInfo) dbmodels.TypeSigningInfo {
+	if info == nil {
+		return nil
+	}
+
+	r := make(dbmodels.TypeSigningInfo, len(info))
+	for k, v := range info {
+		r[k] = v
+	}
+	return r
+}
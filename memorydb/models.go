@@ -0,0 +1,85 @@
+package memorydb
+
+import "github.com/opensourceways/app-cla-server/dbmodels"
+
+const (
+	// 'ready' means the doc is ready to record the signing data currently.
+	// 'deleted' means the signing data is invalid.
+	linkStatusReady   = "ready"
+	linkStatusDeleted = "deleted"
+)
+
+type cLink struct {
+	LinkID     string
+	LinkStatus string
+	Submitter  string
+
+	OrgInfo  dbmodels.OrgInfo
+	OrgEmail dbmodels.OrgEmailCreateInfo
+
+	IndividualCLAs []dCLA
+	CorpCLAs       []dCLA
+
+	CorpPDFTemplates []dbmodels.CorpPDFTemplate
+
+	CorpSigningPolicy *dbmodels.CorpSigningPolicy
+}
+
+type dCLA struct {
+	dbmodels.CLADetail
+
+	OrgSignature     []byte
+	OrgSignatureHash string
+}
+
+type cCorpSigning struct {
+	LinkID     string
+	LinkStatus string
+
+	OrgInfo dbmodels.OrgInfo
+
+	CLAInfos []dbmodels.CLAInfo
+	Signings []dCorpSigning
+	Managers []dCorpManager
+	Deleted  []dCorpSigning
+
+	// History is keyed by the corporation id.
+	History map[string][]dbmodels.CorpSigningEvent
+}
+
+type dCorpSigning struct {
+	CorpID string
+
+	dbmodels.CorpSigningCreateOpt
+}
+
+type dCorpManager struct {
+	CorpID           string
+	InitialPWChanged bool
+
+	dbmodels.CorporationManagerCreateOption
+}
+
+type cIndividualSigning struct {
+	LinkID     string
+	LinkStatus string
+
+	CLAInfos []dbmodels.CLAInfo
+	Signings []dIndividualSigning
+	Deleted  []dIndividualSigning
+}
+
+type dIndividualSigning struct {
+	CorpID string
+
+	dbmodels.IndividualSigningInfo
+
+	// Tombstone is only set for the deleted signing.
+	Tombstone dbmodels.SigningTombstone
+}
+
+type cResignCampaign struct {
+	LinkID string
+
+	dbmodels.ResignCampaign
+}
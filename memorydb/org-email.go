@@ -0,0 +1,41 @@
+package memorydb
+
+import (
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) CreateOrgEmail(opt dbmodels.OrgEmailCreateInfo) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	opt.Token = copyBytes(opt.Token)
+	this.orgEmails[opt.Email] = opt
+	return nil
+}
+
+func (this *client) GetOrgEmailInfo(email string) (*dbmodels.OrgEmailCreateInfo, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	v, ok := this.orgEmails[email]
+	if !ok {
+		return nil, errNoDBRecord
+	}
+
+	v.Token = copyBytes(v.Token)
+	return &v, nil
+}
+
+func (this *client) GetOrgEmailOfLink(linkID string) (*dbmodels.OrgEmailCreateInfo, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	doc := this.getLinkByID(linkID)
+	if doc == nil {
+		return nil, errNoDBRecord
+	}
+
+	v := doc.OrgEmail
+	v.Token = copyBytes(v.Token)
+	return &v, nil
+}
@@ -0,0 +1,102 @@
+package memorydb
+
+import (
+	"strconv"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) AddResignCampaign(linkID string, campaign *dbmodels.ResignCampaign) (string, dbmodels.IDBError) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.getIndividualSigningDoc(linkID) == nil {
+		return "", errNoDBRecord
+	}
+
+	doc := &cResignCampaign{LinkID: linkID, ResignCampaign: *campaign}
+	doc.ID = strconv.Itoa(len(this.resignCampaigns) + 1)
+	doc.Signers = make([]dbmodels.ResignCampaignSigner, len(campaign.Signers))
+	for i := range campaign.Signers {
+		doc.Signers[i] = campaign.Signers[i]
+		doc.Signers[i].Status = dbmodels.ResignStatusPending
+	}
+
+	this.resignCampaigns = append(this.resignCampaigns, doc)
+	return doc.ID, nil
+}
+
+func (this *client) ListResignCampaigns(linkID string) ([]dbmodels.ResignCampaign, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	var r []dbmodels.ResignCampaign
+	for _, item := range this.resignCampaigns {
+		if item.LinkID != linkID {
+			continue
+		}
+
+		v := item.ResignCampaign
+		v.Signers = make([]dbmodels.ResignCampaignSigner, len(item.Signers))
+		copy(v.Signers, item.Signers)
+		r = append(r, v)
+	}
+
+	return r, nil
+}
+
+// forEachPendingSigner calls f with the signer which is pending in the campaign of link.
+func (this *client) forEachPendingSigner(
+	linkID, email string, signerTypes []string,
+	f func(*cResignCampaign, *dbmodels.ResignCampaignSigner),
+) {
+	types := make(map[string]bool, len(signerTypes))
+	for _, t := range signerTypes {
+		types[t] = true
+	}
+
+	for _, item := range this.resignCampaigns {
+		if item.LinkID != linkID {
+			continue
+		}
+
+		for i := range item.Signers {
+			s := &item.Signers[i]
+			if s.Email == email && s.Status == dbmodels.ResignStatusPending && types[s.Type] {
+				f(item, s)
+			}
+		}
+	}
+}
+
+func (this *client) ResignInCampaigns(linkID, email string, signerTypes []string, resignedAt int64) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.forEachPendingSigner(
+		linkID, email, signerTypes,
+		func(_ *cResignCampaign, s *dbmodels.ResignCampaignSigner) {
+			s.Status = dbmodels.ResignStatusResigned
+			s.ResignedAt = resignedAt
+		},
+	)
+
+	return nil
+}
+
+func (this *client) HasOverdueResign(linkID, email string, signerTypes []string, now int64) (bool, dbmodels.IDBError) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	overdue := false
+	this.forEachPendingSigner(
+		linkID, email, signerTypes,
+		func(c *cResignCampaign, _ *dbmodels.ResignCampaignSigner) {
+			if c.Deadline < now {
+				overdue = true
+			}
+		},
+	)
+
+	return overdue, nil
+}
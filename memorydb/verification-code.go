@@ -0,0 +1,39 @@
+package memorydb
+
+import (
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func (this *client) CreateVerificationCode(opt dbmodels.VerificationCode) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	// delete the expired codes.
+	now := util.Now()
+	vcs := make([]dbmodels.VerificationCode, 0, len(this.vcs)+1)
+	for _, item := range this.vcs {
+		if item.Expiry >= now {
+			vcs = append(vcs, item)
+		}
+	}
+
+	this.vcs = append(vcs, opt)
+	return nil
+}
+
+func (this *client) GetVerificationCode(opt *dbmodels.VerificationCode) dbmodels.IDBError {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for i, item := range this.vcs {
+		if item.Email == opt.Email && item.Purpose == opt.Purpose && item.Code == opt.Code {
+			this.vcs = append(this.vcs[:i], this.vcs[i+1:]...)
+
+			opt.Expiry = item.Expiry
+			return nil
+		}
+	}
+
+	return errNoDBRecord
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+type AuditLog = dbmodels.AuditLog
+
+func AddAuditLog(log *AuditLog) IModelError {
+	log.Time = util.Now()
+
+	err := dbmodels.GetDB().AddAuditLog(log)
+	return parseDBError(err)
+}
+
+// ListAuditLogs lists the logs of link, and only the ones of
+// the corporation which corpEmail belongs to if it is not empty.
+func ListAuditLogs(linkID, corpEmail string) ([]AuditLog, IModelError) {
+	opt := dbmodels.AuditLogListOption{LinkID: linkID}
+	if corpEmail != "" {
+		opt.CorpID = util.EmailSuffix(corpEmail)
+	}
+
+	v, err := dbmodels.GetDB().ListAuditLogs(&opt)
+	if err == nil {
+		if v == nil {
+			v = []AuditLog{}
+		}
+		return v, nil
+	}
+
+	return nil, parseDBError(err)
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+// DriftedCLA is the cla whose url is found changed for the first time.
+type DriftedCLA struct {
+	ApplyTo    string
+	Language   string
+	Version    int
+	URL        string
+	DraftSaved bool
+}
+
+// CheckCLADrift downloads the url of the current version of each cla and compares it
+// with the cla text, then records the result. The changed content is saved as the draft
+// of new version if saveDraft is true. It returns the clas which are found changed for
+// the first time, so that the same change is not reported repeatedly.
+func CheckCLADrift(linkID string, saveDraft bool) ([]DriftedCLA, IModelError) {
+	clas, merr := GetAllCLA(linkID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	items := map[string][]dbmodels.CLADetail{
+		dbmodels.ApplyToIndividual:  CurrentCLAs(clas.IndividualCLAs),
+		dbmodels.ApplyToCorporation: CurrentCLAs(clas.CorpCLAs),
+	}
+
+	var r []DriftedCLA
+	for applyTo, v := range items {
+		for i := range v {
+			item := &v[i]
+			if item.URL == "" {
+				continue
+			}
+
+			drift := checkCLADrift(item, saveDraft)
+			err := dbmodels.GetDB().UpdateCLADrift(
+				linkID, applyTo, item.Language, item.Version, drift,
+			)
+			if err != nil {
+				return r, parseDBError(err)
+			}
+
+			if drift.Status == dbmodels.CLADriftChanged &&
+				(item.Drift == nil || item.Drift.URLHash != drift.URLHash) {
+
+				r = append(r, DriftedCLA{
+					ApplyTo:    applyTo,
+					Language:   item.Language,
+					Version:    item.Version,
+					URL:        item.URL,
+					DraftSaved: drift.Draft != "",
+				})
+			}
+		}
+	}
+	return r, nil
+}
+
+func checkCLADrift(cla *dbmodels.CLADetail, saveDraft bool) *dbmodels.CLADrift {
+	now := util.Now()
+
+	data, err := downloadCLA(cla.URL)
+	if err != nil || !isCLAText(*data) {
+		r := &dbmodels.CLADrift{
+			Status:    dbmodels.CLADriftFailed,
+			CheckedAt: now,
+		}
+		// keep the change found before, because the url may be unavailable temporarily.
+		if v := cla.Drift; v != nil {
+			r.URLHash = v.URLHash
+			r.Draft = v.Draft
+		}
+		return r
+	}
+
+	hash := util.Md5sumOfBytes(data)
+	if hash == cla.CLAHash {
+		return &dbmodels.CLADrift{
+			Status:    dbmodels.CLADriftUnchanged,
+			CheckedAt: now,
+		}
+	}
+
+	r := &dbmodels.CLADrift{
+		Status:    dbmodels.CLADriftChanged,
+		CheckedAt: now,
+		URLHash:   hash,
+	}
+	if saveDraft {
+		r.Draft = string(*data)
+	}
+	return r
+}
+
+// NewCLAFromDraft generates the cla of the next version from the draft saved when
+// the url of current version was found changed. The fields, url and the org
+// signature of the current version are kept. policy can be empty.
+func NewCLAFromDraft(linkID, applyTo, language, policy string) (*CLACreateOpt, IModelError) {
+	clas, merr := GetAllCLA(linkID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	v := clas.IndividualCLAs
+	if applyTo == dbmodels.ApplyToCorporation {
+		v = clas.CorpCLAs
+	}
+
+	var current *dbmodels.CLADetail
+	for _, item := range CurrentCLAs(v) {
+		if item.Language == language {
+			current = &item
+			break
+		}
+	}
+	if current == nil || current.Drift == nil || current.Drift.Draft == "" {
+		return nil, newModelError(ErrNoCLADraft, fmt.Errorf("no draft of cla"))
+	}
+
+	opt := &CLACreateOpt{CLAData: current.CLAData}
+	opt.Policy = policy
+	text := []byte(current.Drift.Draft)
+	opt.SetCLAContent(&text)
+
+	if applyTo == dbmodels.ApplyToCorporation {
+		pdf, merr := DownloadCorpCLAPDF(linkID, language, current.Version)
+		if merr != nil {
+			return nil, merr
+		}
+		if pdf != nil {
+			opt.SetOrgSignature(&pdf)
+		}
+	}
+
+	return opt, nil
+}
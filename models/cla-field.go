@@ -0,0 +1,198 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+// The error codes of the invalid field of signing info.
+const (
+	FieldErrMissing        = "missing"
+	FieldErrTooLong        = "too_long"
+	FieldErrUnmatchedRegex = "unmatched_regex"
+	FieldErrNotEmail       = "not_email"
+	FieldErrNotPhone       = "not_phone"
+	FieldErrNotDate        = "not_date"
+	FieldErrNotCountry     = "not_country"
+	FieldErrNotOption      = "not_option"
+	FieldErrNotNumber      = "not_number"
+)
+
+const fieldDateLayout = "2006-01-02"
+
+var (
+	fieldEmailRegex = regexp.MustCompile("^[a-zA-Z0-9_.+-]+@[a-zA-Z0-9-]+(\\.[a-zA-Z0-9-]+)*\\.[a-zA-Z]{2,63}$")
+	fieldPhoneRegex = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{4,20}$`)
+)
+
+// countryCodes are the ISO 3166-1 alpha-2 codes.
+var countryCodes = func() map[string]bool {
+	codes := "AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ " +
+		"BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY BZ " +
+		"CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ " +
+		"DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR " +
+		"GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY " +
+		"HK HM HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP " +
+		"KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY " +
+		"MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ " +
+		"NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY " +
+		"QA RE RO RS RU RW SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ " +
+		"TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UM US UY UZ " +
+		"VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW"
+
+	m := map[string]bool{}
+	for _, item := range strings.Fields(codes) {
+		m[item] = true
+	}
+	return m
+}()
+
+// InvalidFieldsError is the error of signing info. Fields is the error code of each invalid field.
+type InvalidFieldsError struct {
+	modelError
+
+	Fields map[string]string
+}
+
+func newInvalidFieldsError(fields map[string]string) IModelError {
+	ids := make([]string, 0, len(fields))
+	for k := range fields {
+		ids = append(ids, k)
+	}
+	sort.Strings(ids)
+
+	return InvalidFieldsError{
+		modelError: modelError{
+			code: ErrInvalidSigningInfo,
+			err:  fmt.Errorf("invalid fields: %s", strings.Join(ids, ", ")),
+		},
+		Fields: fields,
+	}
+}
+
+func validateCLAField(f *dbmodels.Field) error {
+	if f.Type == "" {
+		f.Type = dbmodels.FieldTypeText
+	}
+
+	switch f.Type {
+	case dbmodels.FieldTypeText, dbmodels.FieldTypeEmail, dbmodels.FieldTypePhone,
+		dbmodels.FieldTypeDate, dbmodels.FieldTypeCountry, dbmodels.FieldTypeNumber:
+		if len(f.Options) > 0 {
+			return fmt.Errorf("only the field of single_choice can have options")
+		}
+
+	case dbmodels.FieldTypeSingleChoice:
+		if len(f.Options) == 0 {
+			return fmt.Errorf("the field of single_choice has no options")
+		}
+
+	default:
+		return fmt.Errorf("unknown field type: %s", f.Type)
+	}
+
+	if f.MaxLength < 0 {
+		return fmt.Errorf("negative max length")
+	}
+
+	if f.Regex != "" {
+		if _, err := regexp.Compile(f.Regex); err != nil {
+			return fmt.Errorf("invalid regex, err: %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// ValidateSigningInfo checks the signing info against the fields of cla, and returns
+// the values of the fields only. It returns InvalidFieldsError if any field is invalid.
+// The field of unknown type, which was added before the types were defined, is taken as text.
+func ValidateSigningInfo(info dbmodels.TypeSigningInfo, fields []dbmodels.Field) (dbmodels.TypeSigningInfo, IModelError) {
+	r := dbmodels.TypeSigningInfo{}
+	invalid := map[string]string{}
+
+	for i := range fields {
+		item := &fields[i]
+
+		v := strings.TrimSpace(info[item.ID])
+		if v == "" {
+			if item.Required {
+				invalid[item.ID] = FieldErrMissing
+			}
+			continue
+		}
+
+		if code := checkFieldValue(item, v); code != "" {
+			invalid[item.ID] = code
+			continue
+		}
+
+		r[item.ID] = v
+	}
+
+	if len(invalid) > 0 {
+		return nil, newInvalidFieldsError(invalid)
+	}
+	return r, nil
+}
+
+func checkFieldValue(f *dbmodels.Field, v string) string {
+	if f.MaxLength > 0 && utf8.RuneCountInString(v) > f.MaxLength {
+		return FieldErrTooLong
+	}
+
+	switch f.Type {
+	case dbmodels.FieldTypeEmail:
+		if !fieldEmailRegex.MatchString(v) {
+			return FieldErrNotEmail
+		}
+
+	case dbmodels.FieldTypePhone:
+		if !fieldPhoneRegex.MatchString(v) {
+			return FieldErrNotPhone
+		}
+
+	case dbmodels.FieldTypeDate:
+		if _, err := time.Parse(fieldDateLayout, v); err != nil {
+			return FieldErrNotDate
+		}
+
+	case dbmodels.FieldTypeCountry:
+		if !countryCodes[strings.ToUpper(v)] {
+			return FieldErrNotCountry
+		}
+
+	case dbmodels.FieldTypeSingleChoice:
+		found := false
+		for _, item := range f.Options {
+			if item == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return FieldErrNotOption
+		}
+
+	case dbmodels.FieldTypeNumber:
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return FieldErrNotNumber
+		}
+	}
+
+	if f.Regex != "" {
+		// the regex has been checked when the cla was added.
+		if rg, err := regexp.Compile(f.Regex); err == nil && !rg.MatchString(v) {
+			return FieldErrUnmatchedRegex
+		}
+	}
+
+	return ""
+}
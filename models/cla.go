@@ -16,6 +16,8 @@ import (
 
 type CLAInfo = dbmodels.CLAInfo
 
+type CLAVersion = dbmodels.CLAVersion
+
 type CLAField = dbmodels.Field
 
 type CLACreateOpt struct {
@@ -33,12 +35,18 @@ func (this *CLACreateOpt) SetOrgSignature(data *[]byte) {
 	this.orgSignature = data
 }
 
-func (this *CLACreateOpt) toCLACreateOption() *dbmodels.CLACreateOption {
+// CLAHash returns the hash of cla content which identifies the version signed.
+func (this *CLACreateOpt) CLAHash() string {
+	return util.Md5sumOfBytes(this.content)
+}
+
+func (this *CLACreateOpt) toCLACreateOption(version int) *dbmodels.CLACreateOption {
 	return &dbmodels.CLACreateOption{
 		CLADetail: dbmodels.CLADetail{
 			CLAData: this.CLAData,
 			Text:    string(*this.content),
-			CLAHash: util.Md5sumOfBytes(this.content),
+			CLAHash: this.CLAHash(),
+			Version: version,
 		},
 		OrgSignature:     this.orgSignature,
 		OrgSignatureHash: util.Md5sumOfBytes(this.orgSignature),
@@ -63,8 +71,20 @@ func (this *CLACreateOpt) SaveCLAAtLocal(path string) error {
 	return ioutil.WriteFile(path, *this.content, 0644)
 }
 
+// AddCLA adds the cla as the next version of the language.
+// It must be called under the lock of link.
 func (this *CLACreateOpt) AddCLA(linkID, applyTo string) IModelError {
-	err := dbmodels.GetDB().AddCLA(linkID, applyTo, this.toCLACreateOption())
+	versions, merr := ListCLAVersions(linkID, applyTo, this.Language)
+	if merr != nil {
+		return merr
+	}
+
+	version := 1
+	if n := len(versions); n > 0 {
+		version = versions[n-1].Version + 1
+	}
+
+	err := dbmodels.GetDB().AddCLA(linkID, applyTo, this.toCLACreateOption(version))
 	if err == nil {
 		return nil
 	}
@@ -91,7 +111,7 @@ func (this *CLACreateOpt) AddCLAInfo(linkID, applyTo string) IModelError {
 func (this *CLACreateOpt) GenCLAInfo() *CLAInfo {
 	return &CLAInfo{
 		OrgSignatureHash: util.Md5sumOfBytes(this.orgSignature),
-		CLAHash:          util.Md5sumOfBytes(this.content),
+		CLAHash:          this.CLAHash(),
 		CLALang:          this.Language,
 		Fields:           this.Fields,
 	}
@@ -100,6 +120,14 @@ func (this *CLACreateOpt) GenCLAInfo() *CLAInfo {
 func (this *CLACreateOpt) Validate(applyTo string, langs map[string]bool) IModelError {
 	this.Language = strings.ToLower(this.Language)
 
+	switch this.Policy {
+	case "":
+		this.Policy = dbmodels.CLAPolicyKeep
+	case dbmodels.CLAPolicyKeep, dbmodels.CLAPolicyRenew:
+	default:
+		return newModelError(ErrUnknownCLAPolicy, fmt.Errorf("unknown cla policy"))
+	}
+
 	if applyTo == dbmodels.ApplyToCorporation && !langs[this.Language] {
 		return newModelError(ErrUnsupportedCLALang, fmt.Errorf("unsupported_cla_lang"))
 	}
@@ -116,13 +144,27 @@ func (this *CLACreateOpt) Validate(applyTo string, langs map[string]bool) IModel
 		if _, err := strconv.Atoi(this.Fields[i].ID); err != nil {
 			return newModelError(ErrCLAFieldID, fmt.Errorf("invalid field id"))
 		}
+
+		if err := validateCLAField(&this.Fields[i]); err != nil {
+			return newModelError(ErrInvalidCLAField, err)
+		}
+	}
+
+	if this.content == nil {
+		if this.URL == "" {
+			return newModelError(ErrMissgingCLA, fmt.Errorf("neither cla file nor url"))
+		}
+
+		text, err := downloadCLA(this.URL)
+		if err != nil {
+			return newModelError(ErrInvalidCLAURL, err)
+		}
+		this.content = text
 	}
 
-	text, err := downloadCLA(this.URL)
-	if err != nil {
-		return newModelError(ErrSystemError, err)
+	if !isCLAText(*this.content) {
+		return newModelError(ErrNotCLAText, fmt.Errorf("it is not the content of cla"))
 	}
-	this.content = text
 
 	if applyTo == dbmodels.ApplyToCorporation && this.orgSignature == nil {
 		return newModelError(ErrNoOrgSignature, fmt.Errorf("no signatrue"))
@@ -131,39 +173,50 @@ func (this *CLACreateOpt) Validate(applyTo string, langs map[string]bool) IModel
 	return nil
 }
 
+func isCLAText(data []byte) bool {
+	return strings.HasPrefix(http.DetectContentType(data), "text/plain")
+}
+
+func claDownloadOption() *util.DownloadOption {
+	cfg := &config.AppConfig.CLADownload
+
+	return &util.DownloadOption{
+		AllowedHosts: cfg.AllowedHosts,
+		MaxSize:      config.AppConfig.MaxSizeOfCLAText,
+		MaxRedirects: cfg.MaxRedirects,
+		Timeout:      time.Second * time.Duration(cfg.Timeout),
+	}
+}
+
 func downloadCLA(url string) (*[]byte, error) {
-	var resp *http.Response
+	var data []byte
+	var err error
 
+	opt := claDownloadOption()
 	for i := 0; i < 3; i++ {
-		v, err := http.Get(url)
-		if err == nil {
-			resp = v
+		if data, err = util.Download(url, opt); err == nil {
+			return &data, nil
+		}
+		// only the transient error, such as the one of network, is retried.
+		if util.IsPermanentDownloadError(err) {
 			break
 		}
 		time.Sleep(time.Second * time.Duration(1))
 	}
-	if resp == nil {
-		return nil, fmt.Errorf("can't download %s", url)
-	}
-
-	defer resp.Body.Close()
-
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	if strings.HasPrefix(http.DetectContentType(data), "text/plain") {
-		return &data, nil
-	}
 
-	return nil, fmt.Errorf("it is not the content of cla")
+	return nil, fmt.Errorf("can't download %s, err: %s", url, err.Error())
 }
 
+// GetCLAByType returns the current version of each language.
+// The drift is only visible to the owner of link, so it is removed.
 func GetCLAByType(orgRepo *dbmodels.OrgRepo, applyTo string) (string, []dbmodels.CLADetail, IModelError) {
 	linkID, v, err := dbmodels.GetDB().GetCLAByType(orgRepo, applyTo)
 	if err == nil {
-		return linkID, v, nil
+		r := CurrentCLAs(v)
+		for i := range r {
+			r[i].Drift = nil
+		}
+		return linkID, r, nil
 	}
 
 	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
@@ -184,6 +237,73 @@ func GetAllCLA(linkID string) (*dbmodels.CLAOfLink, IModelError) {
 	return v, parseDBError(err)
 }
 
+// CurrentCLAs returns the current version of each language.
+func CurrentCLAs(clas []dbmodels.CLADetail) []dbmodels.CLADetail {
+	if len(clas) == 0 {
+		return clas
+	}
+
+	m := map[string]int{}
+	r := make([]dbmodels.CLADetail, 0, len(clas))
+	for i := range clas {
+		item := &clas[i]
+
+		j, ok := m[item.Language]
+		if !ok {
+			m[item.Language] = len(r)
+			r = append(r, *item)
+		} else if item.Version > r[j].Version {
+			r[j] = *item
+		}
+	}
+	return r
+}
+
+// ListCLAVersions returns the versions of cla sorted by version.
+func ListCLAVersions(linkID, applyTo, language string) ([]CLAVersion, IModelError) {
+	v, err := dbmodels.GetDB().ListCLAVersions(linkID, applyTo, language)
+	if err == nil {
+		return v, nil
+	}
+
+	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return v, newModelError(ErrNoLink, err)
+	}
+	return v, parseDBError(err)
+}
+
+// validCLAHashes returns the hashes of the versions whose signings are valid.
+// They are the current version and the older ones back to the latest version
+// which requires the signings of older versions to be renewed.
+func validCLAHashes(versions []CLAVersion) map[string]bool {
+	r := map[string]bool{}
+	for i := len(versions) - 1; i >= 0; i-- {
+		r[versions[i].CLAHash] = true
+
+		if versions[i].Policy == dbmodels.CLAPolicyRenew {
+			break
+		}
+	}
+	return r
+}
+
+// isCLAVersionValid reports whether the signing of the version whose hash is claHash is valid.
+// The signing which was saved before versioning signed the first version.
+func isCLAVersionValid(linkID, applyTo, claLang, claHash string) (bool, IModelError) {
+	versions, merr := ListCLAVersions(linkID, applyTo, claLang)
+	if merr != nil {
+		return false, merr
+	}
+	if len(versions) == 0 {
+		return true, nil
+	}
+
+	if claHash == "" {
+		claHash = versions[0].CLAHash
+	}
+	return validCLAHashes(versions)[claHash], nil
+}
+
 func HasCLA(linkID, applyTo, language string) (bool, IModelError) {
 	v, err := dbmodels.GetDB().HasCLA(linkID, applyTo, language)
 	if err == nil {
@@ -245,7 +365,7 @@ func GetCLAInfoToSign(linkID, claLang, applyTo string) (*dbmodels.CLAInfo, IMode
 	return v, parseDBError(err)
 }
 
-func DownloadCorpCLAPDF(linkID, lang string) ([]byte, IModelError) {
-	v, err := dbmodels.GetDB().DownloadCorpCLAPDF(linkID, lang)
+func DownloadCorpCLAPDF(linkID, lang string, version int) ([]byte, IModelError) {
+	v, err := dbmodels.GetDB().DownloadCorpCLAPDF(linkID, lang, version)
 	return v, parseDBError(err)
 }
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const agreementIDLength = 24
+
+var reAgreementID = regexp.MustCompile(fmt.Sprintf("^[0-9A-Za-z]{%d}$", agreementIDLength))
+
+type CorpAgreement = dbmodels.CorpAgreement
+
+// genAgreementID generates the id of corporation signing which is hard to guess,
+// because anyone who knows it can see the corporation name of the signing.
+func genAgreementID() string {
+	return util.RandStr(agreementIDLength, "alphanum")
+}
+
+// GetCorpAgreement returns the public info of corporation signing, so that anyone
+// can verify the signing by the agreement id printed on the pdf.
+func GetCorpAgreement(agreementID string) (*CorpAgreement, IModelError) {
+	if !reAgreementID.MatchString(agreementID) {
+		return nil, newModelError(ErrNoAgreement, fmt.Errorf("invalid agreement id"))
+	}
+
+	v, err := dbmodels.GetDB().GetCorpAgreement(agreementID)
+	if err == nil {
+		return v, nil
+	}
+
+	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return nil, newModelError(ErrNoAgreement, err)
+	}
+	return nil, parseDBError(err)
+}
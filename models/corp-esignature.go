@@ -0,0 +1,65 @@
+package models
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/opensourceways/app-cla-server/config"
+)
+
+const (
+	ESignatureTyped = "typed"
+	ESignatureDrawn = "drawn"
+
+	maxLengthOfTypedESignature = 64
+)
+
+// CorpESignature is the signature of corporation which signs electronically.
+// It is the name typed by the signer, or the png image drawn by the signer
+// which is encoded in base64.
+type CorpESignature struct {
+	Type  string `json:"type"`
+	Text  string `json:"text,omitempty"`
+	Image string `json:"image,omitempty"`
+
+	image []byte
+}
+
+// ImageData returns the png image decoded by Validate.
+func (this *CorpESignature) ImageData() []byte {
+	return this.image
+}
+
+func (this *CorpESignature) Validate() IModelError {
+	switch this.Type {
+	case ESignatureTyped:
+		this.Text = strings.TrimSpace(this.Text)
+		if this.Text == "" || utf8.RuneCountInString(this.Text) > maxLengthOfTypedESignature {
+			return newModelError(ErrInvalidESignature, fmt.Errorf("invalid typed signature"))
+		}
+
+	case ESignatureDrawn:
+		data, err := base64.StdEncoding.DecodeString(this.Image)
+		if err != nil {
+			return newModelError(ErrInvalidESignature, fmt.Errorf("the image is not base64 encoded"))
+		}
+		if len(data) == 0 || len(data) > config.AppConfig.MaxSizeOfESignature {
+			return newModelError(ErrInvalidESignature, fmt.Errorf("invalid size of image"))
+		}
+
+		_, format, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil || format != "png" {
+			return newModelError(ErrInvalidESignature, fmt.Errorf("the image is not png"))
+		}
+		this.image = data
+
+	default:
+		return newModelError(ErrInvalidESignature, fmt.Errorf("unknown type of signature"))
+	}
+
+	return nil
+}
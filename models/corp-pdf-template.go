@@ -0,0 +1,106 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	// register the formats of logo
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+	"text/template"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+type CorpPDFTemplate = dbmodels.CorpPDFTemplate
+
+// CorpPDFTemplateCreateOpt is the templates of corporation signing pdf uploaded by the community.
+// The data of welcome and declaration templates is {Org, Email}. Logo is optional.
+type CorpPDFTemplateCreateOpt struct {
+	Welcome     []byte
+	Declaration []byte
+	Logo        []byte
+}
+
+// Validate checks the templates and returns the one to save. The templates
+// should be rendered to a sample pdf before being saved.
+func (this *CorpPDFTemplateCreateOpt) Validate(language string, langs map[string]bool) (*CorpPDFTemplate, IModelError) {
+	language = strings.ToLower(language)
+	if !langs[language] {
+		return nil, newModelError(ErrUnsupportedCLALang, fmt.Errorf("unsupported_cla_lang"))
+	}
+
+	items := map[string][]byte{
+		"welcome":     this.Welcome,
+		"declaration": this.Declaration,
+	}
+	for k, v := range items {
+		if len(v) == 0 || !isCLAText(v) {
+			return nil, newModelError(
+				ErrInvalidPDFTemplate, fmt.Errorf("the %s template is not text", k),
+			)
+		}
+
+		if _, err := template.New(k).Parse(string(v)); err != nil {
+			return nil, newModelError(
+				ErrInvalidPDFTemplate, fmt.Errorf("invalid %s template: %s", k, err.Error()),
+			)
+		}
+	}
+
+	r := &CorpPDFTemplate{
+		Language:    language,
+		Welcome:     string(this.Welcome),
+		Declaration: string(this.Declaration),
+	}
+
+	if len(this.Logo) > 0 {
+		_, format, err := image.DecodeConfig(bytes.NewReader(this.Logo))
+		if err != nil {
+			return nil, newModelError(ErrInvalidLogo, fmt.Errorf("the logo is not png or jpeg image"))
+		}
+
+		r.Logo = this.Logo
+		r.LogoType = format
+	}
+
+	return r, nil
+}
+
+func SaveCorpPDFTemplate(linkID string, t *CorpPDFTemplate) IModelError {
+	err := dbmodels.GetDB().SaveCorpPDFTemplate(linkID, t)
+	if err == nil {
+		return nil
+	}
+
+	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return newModelError(ErrNoLink, err)
+	}
+	return parseDBError(err)
+}
+
+// GetCorpPDFTemplate returns nil if the community has not customized the templates of language.
+func GetCorpPDFTemplate(linkID, language string) (*CorpPDFTemplate, IModelError) {
+	v, err := dbmodels.GetDB().GetCorpPDFTemplate(linkID, strings.ToLower(language))
+	if err == nil {
+		return v, nil
+	}
+
+	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return nil, newModelError(ErrNoLink, err)
+	}
+	return nil, parseDBError(err)
+}
+
+func DeleteCorpPDFTemplate(linkID, language string) IModelError {
+	err := dbmodels.GetDB().DeleteCorpPDFTemplate(linkID, strings.ToLower(language))
+	if err == nil {
+		return nil
+	}
+
+	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return newModelError(ErrNoLink, err)
+	}
+	return parseDBError(err)
+}
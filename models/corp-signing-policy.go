@@ -0,0 +1,42 @@
+package models
+
+import (
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+type CorpSigningPolicy = dbmodels.CorpSigningPolicy
+
+// defaultCorpSigningPolicy is the policy of the link which has not set it,
+// and the corporation signs as before by uploading the scanned pdf.
+var defaultCorpSigningPolicy = CorpSigningPolicy{
+	ESigning:           false,
+	ScannedPDFRequired: true,
+}
+
+func GetCorpSigningPolicy(linkID string) (*CorpSigningPolicy, IModelError) {
+	v, err := dbmodels.GetDB().GetCorpSigningPolicy(linkID)
+	if err == nil {
+		if v == nil {
+			r := defaultCorpSigningPolicy
+			v = &r
+		}
+		return v, nil
+	}
+
+	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return nil, newModelError(ErrNoLink, err)
+	}
+	return nil, parseDBError(err)
+}
+
+func UpdateCorpSigningPolicy(linkID string, p *CorpSigningPolicy) IModelError {
+	err := dbmodels.GetDB().UpdateCorpSigningPolicy(linkID, p)
+	if err == nil {
+		return nil
+	}
+
+	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return newModelError(ErrNoLink, err)
+	}
+	return parseDBError(err)
+}
@@ -18,9 +18,18 @@ type CorporationSigningCreateOption struct {
 	CorporationSigning
 
 	VerificationCode string `json:"verification_code"`
+
+	// ESignature is set when the corporation signs electronically.
+	ESignature *CorpESignature `json:"esignature,omitempty"`
 }
 
 func (this *CorporationSigningCreateOption) Validate(orgCLAID string) IModelError {
+	if this.ESignature != nil {
+		if err := this.ESignature.Validate(); err != nil {
+			return err
+		}
+	}
+
 	err := checkVerificationCode(this.AdminEmail, this.VerificationCode, orgCLAID)
 	if err != nil {
 		return err
@@ -30,8 +39,19 @@ func (this *CorporationSigningCreateOption) Validate(orgCLAID string) IModelErro
 
 func (this *CorporationSigningCreateOption) Create(orgCLAID string) IModelError {
 	this.Date = util.Date()
+	this.ESigned = this.ESignature != nil
+	this.AgreementID = genAgreementID()
+
+	db := dbmodels.GetDB()
 
-	err := dbmodels.GetDB().SignCorpCLA(orgCLAID, &this.CorporationSigning)
+	err := db.SignCorpCLA(orgCLAID, &this.CorporationSigning)
+	if err != nil && err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		// renew the signing of an older version of cla by the same admin,
+		// and the pdf of the older version is not the one of the new signing.
+		if err = db.ResignCorpCLA(orgCLAID, &this.CorporationSigning); err == nil {
+			err = db.DeleteCorporationSigningPDF(orgCLAID, this.AdminEmail)
+		}
+	}
 	if err == nil {
 		return nil
 	}
@@ -42,7 +62,27 @@ func (this *CorporationSigningCreateOption) Create(orgCLAID string) IModelError
 	return parseDBError(err)
 }
 
+// UploadCorporationSigningPDF saves the scanned pdf which replaces the one signed electronically.
 func UploadCorporationSigningPDF(linkID, email string, pdf []byte) IModelError {
+	db := dbmodels.GetDB()
+
+	if err := db.UploadCorporationSigningPDF(linkID, email, pdf); err != nil {
+		return parseDBError(err)
+	}
+
+	err := db.UpdateCorpSigningESigned(linkID, email, false)
+	if err == nil {
+		return nil
+	}
+
+	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return newModelError(ErrNoLinkOrUnsigned, err)
+	}
+	return parseDBError(err)
+}
+
+// SaveESignedCorpSigningPDF saves the pdf which is generated for the corporation signing electronically.
+func SaveESignedCorpSigningPDF(linkID, email string, pdf []byte) IModelError {
 	err := dbmodels.GetDB().UploadCorporationSigningPDF(linkID, email, pdf)
 	return parseDBError(err)
 }
@@ -122,8 +162,14 @@ func GetCorpSigningDetail(linkID, email string) ([]dbmodels.Field, *dbmodels.Cor
 	return f, s, parseDBError(err)
 }
 
-func DeleteCorpSigning(linkID, email string) IModelError {
-	err := dbmodels.GetDB().DeleteCorpSigning(linkID, email)
+type CorpSigningEvent = dbmodels.CorpSigningEvent
+
+func DeleteCorpSigning(linkID, email, operator string) IModelError {
+	err := dbmodels.GetDB().DeleteCorpSigning(linkID, email, &dbmodels.CorpSigningEvent{
+		Action:   dbmodels.CorpSigningDeleted,
+		Operator: operator,
+		Time:     util.Now(),
+	})
 	if err == nil {
 		return nil
 	}
@@ -134,6 +180,41 @@ func DeleteCorpSigning(linkID, email string) IModelError {
 	return parseDBError(err)
 }
 
+// RestoreCorpSigning restores the latest deleted signing of the corporation.
+func RestoreCorpSigning(linkID, email, operator string) IModelError {
+	err := dbmodels.GetDB().RestoreCorpSigning(linkID, email, &dbmodels.CorpSigningEvent{
+		Action:   dbmodels.CorpSigningRestored,
+		Operator: operator,
+		Time:     util.Now(),
+	})
+	if err == nil {
+		return nil
+	}
+
+	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return newModelError(ErrNoLinkOrNotDeleted, err)
+	}
+	if err.IsErrorOf(dbmodels.ErrRecordExists) {
+		return newModelError(ErrNoLinkOrResigned, err)
+	}
+	return parseDBError(err)
+}
+
+func ListCorpSigningHistory(linkID, email string) ([]CorpSigningEvent, IModelError) {
+	v, err := dbmodels.GetDB().ListCorpSigningHistory(linkID, email)
+	if err == nil {
+		if v == nil {
+			v = []CorpSigningEvent{}
+		}
+		return v, nil
+	}
+
+	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return v, newModelError(ErrNoLink, err)
+	}
+	return v, parseDBError(err)
+}
+
 func ListDeletedCorpSignings(linkID string) ([]dbmodels.CorporationSigningBasicInfo, IModelError) {
 	v, err := dbmodels.GetDB().ListDeletedCorpSignings(linkID)
 	if err == nil {
@@ -40,15 +40,3 @@ func (this *EmployeeSigningUdateInfo) Update(linkID, email string) IModelError {
 	}
 	return parseDBError(err)
 }
-
-func DeleteEmployeeSigning(linkID, email string) IModelError {
-	err := dbmodels.GetDB().DeleteIndividualSigning(linkID, email)
-	if err == nil {
-		return nil
-	}
-
-	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
-		return newModelError(ErrNoLink, err)
-	}
-	return parseDBError(err)
-}
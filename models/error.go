@@ -44,6 +44,22 @@ const (
 	ErrMissgingCLA             ModelErrCode = "missing_cla"
 	ErrNoLinkOrCLAExists       ModelErrCode = "no_link_or_cla_exists"
 	ErrNoLinkOrUnuploaed       ModelErrCode = "no_link_or_unuploaded"
+	ErrInvalidArchive          ModelErrCode = "invalid_archive"
+	ErrNoLinkOrNotDeleted      ModelErrCode = "no_link_or_not_deleted"
+	ErrUnknownCLAPolicy        ModelErrCode = "unknown_cla_policy"
+	ErrInvalidGracePeriod      ModelErrCode = "invalid_grace_period"
+	ErrInvalidSigningURL       ModelErrCode = "invalid_signing_url"
+	ErrNoSignerToResign        ModelErrCode = "no_signer_to_resign"
+	ErrInvalidCLAURL           ModelErrCode = "invalid_cla_url"
+	ErrNotCLAText              ModelErrCode = "not_cla_text"
+	ErrNoCLADraft              ModelErrCode = "no_cla_draft"
+	ErrInvalidCLAField         ModelErrCode = "invalid_cla_field"
+	ErrInvalidSigningInfo      ModelErrCode = "invalid_signing_info"
+	ErrInvalidPDFTemplate      ModelErrCode = "invalid_pdf_template"
+	ErrInvalidLogo             ModelErrCode = "invalid_logo"
+	ErrESigningDisabled        ModelErrCode = "esigning_disabled"
+	ErrInvalidESignature       ModelErrCode = "invalid_esignature"
+	ErrNoAgreement             ModelErrCode = "no_agreement"
 )
 
 type IModelError interface {
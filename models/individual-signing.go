@@ -30,9 +30,13 @@ func (this *IndividualSigning) Create(linkID string, enabled bool) IModelError {
 	this.Date = util.Date()
 	this.Enabled = enabled
 
-	err := dbmodels.GetDB().SignIndividualCLA(
-		linkID, (*dbmodels.IndividualSigningInfo)(this),
-	)
+	info := (*dbmodels.IndividualSigningInfo)(this)
+
+	err := dbmodels.GetDB().SignIndividualCLA(linkID, info)
+	if err != nil && err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		// renew the signing of an older version of cla
+		err = dbmodels.GetDB().ResignIndividualCLA(linkID, info)
+	}
 	if err == nil {
 		return nil
 	}
@@ -43,10 +47,101 @@ func (this *IndividualSigning) Create(linkID string, enabled bool) IModelError {
 	return parseDBError(err)
 }
 
-func IsIndividualSigned(linkID, email string) (bool, IModelError) {
-	b, err := dbmodels.GetDB().IsIndividualSigned(linkID, email)
+type IndividualSigningStatus struct {
+	Signed      bool `json:"signed"`
+	NeedsResign bool `json:"needs_resign"`
+}
+
+// GetIndividualSigningStatus reports that the individual needs to re-sign if the signing
+// must be renewed because of the policy of a newer version of cla, or the individual
+// has not re-signed after the grace period of any campaign expired.
+func GetIndividualSigningStatus(linkID, email string) (IndividualSigningStatus, IModelError) {
+	db := dbmodels.GetDB()
+	r := IndividualSigningStatus{}
+
+	b, err := db.IsIndividualSigned(linkID, email)
+	if err != nil || !b {
+		return r, parseDBError(err)
+	}
+
+	detail, err := db.GetIndividualSigningDetail(linkID, email)
+	if err != nil || detail == nil {
+		return r, parseDBError(err)
+	}
+
+	valid, merr := isCLAVersionValid(
+		linkID, dbmodels.ApplyToIndividual, detail.CLALanguage, detail.CLAHash,
+	)
+	if merr != nil {
+		return r, merr
+	}
+	if !valid {
+		r.NeedsResign = true
+		return r, nil
+	}
+
+	overdue, err := db.HasOverdueResign(linkID, email, signerTypes(false), util.Now())
+	if err != nil {
+		return r, parseDBError(err)
+	}
+
+	r.Signed = !overdue
+	r.NeedsResign = overdue
+	return r, nil
+}
+
+type DeletedIndividualSigning = dbmodels.DeletedIndividualSigning
+
+// DeleteIndividualSigning keeps a tombstone of the signing,
+// so that it can be restored until the tombstone is purged.
+func DeleteIndividualSigning(linkID, email, deletedBy, reason string) IModelError {
+	err := dbmodels.GetDB().DeleteIndividualSigning(linkID, email, &dbmodels.SigningTombstone{
+		DeletedBy: deletedBy,
+		Reason:    reason,
+		DeletedAt: util.Now(),
+	})
 	if err == nil {
-		return b, nil
+		return nil
+	}
+
+	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return newModelError(ErrNoLink, err)
 	}
-	return b, parseDBError(err)
+	return parseDBError(err)
+}
+
+func ListDeletedIndividualSignings(linkID, corpEmail string) ([]DeletedIndividualSigning, IModelError) {
+	v, err := dbmodels.GetDB().ListDeletedIndividualSignings(linkID, corpEmail)
+	if err == nil {
+		if v == nil {
+			v = []DeletedIndividualSigning{}
+		}
+		return v, nil
+	}
+
+	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return nil, newModelError(ErrNoLink, err)
+	}
+	return nil, parseDBError(err)
+}
+
+func RestoreIndividualSigning(linkID, email string) IModelError {
+	err := dbmodels.GetDB().RestoreIndividualSigning(linkID, email)
+	if err == nil {
+		return nil
+	}
+
+	if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return newModelError(ErrNoLinkOrNotDeleted, err)
+	}
+	if err.IsErrorOf(dbmodels.ErrRecordExists) {
+		return newModelError(ErrNoLinkOrResigned, err)
+	}
+	return parseDBError(err)
+}
+
+// PurgeDeletedIndividualSignings deletes the tombstones which are older than retention seconds.
+func PurgeDeletedIndividualSignings(retention int64) IModelError {
+	err := dbmodels.GetDB().PurgeDeletedIndividualSignings(util.Now() - retention)
+	return parseDBError(err)
 }
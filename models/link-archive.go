@@ -0,0 +1,668 @@
+package models
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+// linkArchiveVersion is the version of archive format. Increase it when
+// the format is changed incompatibly.
+const linkArchiveVersion = 1
+
+// LinkArchive includes all the data of a link. The emails, signing infos and tokens
+// are saved as plaintext, so the archive must be kept as securely as the db.
+// They will be encrypted by the keys of the destination when importing.
+type LinkArchive struct {
+	Version int `json:"version"`
+
+	dbmodels.LinkInfo
+	OrgEmail dbmodels.OrgEmailCreateInfo `json:"org_email_info"`
+
+	IndividualCLAs     []ArchivedCLA      `json:"individual_clas"`
+	CorpCLAs           []ArchivedCLA      `json:"corp_clas"`
+	IndividualCLAInfos []dbmodels.CLAInfo `json:"individual_cla_infos"`
+	CorpCLAInfos       []dbmodels.CLAInfo `json:"corp_cla_infos"`
+
+	CorpSignings        []dbmodels.CorpSigningCreateOpt        `json:"corp_signings"`
+	DeletedCorpSignings []dbmodels.CorporationSigningBasicInfo `json:"deleted_corp_signings"`
+	IndividualSignings  []dbmodels.IndividualSigningInfo       `json:"individual_signings"`
+	CorpManagers        []ArchivedCorpManager                  `json:"corp_managers"`
+	CorpPDFs            []ArchivedCorpPDF                      `json:"corp_pdfs"`
+}
+
+type ArchivedCLA struct {
+	dbmodels.CLADetail
+
+	OrgSignature []byte `json:"org_signature,omitempty"`
+}
+
+type ArchivedCorpManager struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Role             string `json:"role"`
+	Email            string `json:"email"`
+	Password         string `json:"password"`
+	InitialPWChanged bool   `json:"changed"`
+}
+
+type ArchivedCorpPDF struct {
+	CorpID string `json:"corp_id"`
+	PDF    []byte `json:"pdf"`
+}
+
+func archiveError(err dbmodels.IDBError) IModelError {
+	if err != nil && err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return newModelError(ErrNoLink, err)
+	}
+	return parseDBError(err)
+}
+
+// ExportLink reads all the data of the link from the db and the file storage.
+func ExportLink(linkID string) (*LinkArchive, IModelError) {
+	db := dbmodels.GetDB()
+
+	orgInfo, err := db.GetOrgOfLink(linkID)
+	if err != nil {
+		return nil, archiveError(err)
+	}
+
+	a := &LinkArchive{Version: linkArchiveVersion}
+
+	links, err := db.ListLinks(&dbmodels.LinkListOption{
+		Platform: orgInfo.Platform,
+		Orgs:     []string{orgInfo.OrgID},
+	})
+	if err != nil {
+		return nil, archiveError(err)
+	}
+	for i := range links {
+		if links[i].LinkID == linkID {
+			a.LinkInfo = links[i]
+		}
+	}
+	if a.LinkID == "" {
+		return nil, newModelError(ErrNoLink, fmt.Errorf("no link:%s", linkID))
+	}
+
+	orgEmail, err := db.GetOrgEmailOfLink(linkID)
+	if err != nil {
+		return nil, archiveError(err)
+	}
+	a.OrgEmail = *orgEmail
+
+	if merr := a.exportCLAs(db); merr != nil {
+		return nil, merr
+	}
+
+	if merr := a.exportSignings(db); merr != nil {
+		return nil, merr
+	}
+
+	if merr := a.exportCorpManagers(db); merr != nil {
+		return nil, merr
+	}
+
+	if merr := a.exportCLAInfos(db); merr != nil {
+		return nil, merr
+	}
+
+	if merr := a.exportCorpPDFs(db); merr != nil {
+		return nil, merr
+	}
+
+	return a, nil
+}
+
+func (this *LinkArchive) exportCLAs(db dbmodels.IDB) IModelError {
+	clas, err := db.GetAllCLA(this.LinkID)
+	if err != nil {
+		return archiveError(err)
+	}
+
+	for i := range clas.IndividualCLAs {
+		this.IndividualCLAs = append(this.IndividualCLAs, ArchivedCLA{CLADetail: clas.IndividualCLAs[i]})
+	}
+
+	for i := range clas.CorpCLAs {
+		item := &clas.CorpCLAs[i]
+
+		signature, err := db.DownloadCorpCLAPDF(this.LinkID, item.Language, item.Version)
+		if err != nil {
+			return archiveError(err)
+		}
+
+		this.CorpCLAs = append(this.CorpCLAs, ArchivedCLA{
+			CLADetail:    *item,
+			OrgSignature: signature,
+		})
+	}
+
+	return nil
+}
+
+func (this *LinkArchive) exportSignings(db dbmodels.IDB) IModelError {
+	linkID := this.LinkID
+
+	corps, err := db.ListCorpSignings(linkID, "")
+	if err != nil {
+		return archiveError(err)
+	}
+
+	for i := range corps {
+		bi := &corps[i].CorporationSigningBasicInfo
+
+		_, detail, err := db.GetCorpSigningDetail(linkID, bi.AdminEmail)
+		if err != nil {
+			return archiveError(err)
+		}
+		if detail == nil {
+			// the cla info of signing is missing, so only the basic info can be read.
+			detail = &dbmodels.CorpSigningCreateOpt{CorporationSigningBasicInfo: *bi}
+		}
+
+		this.CorpSignings = append(this.CorpSignings, *detail)
+	}
+
+	if this.DeletedCorpSignings, err = db.ListDeletedCorpSignings(linkID); err != nil {
+		return archiveError(err)
+	}
+
+	individuals, err := db.ListIndividualSigning(linkID, "", "")
+	if err != nil {
+		return archiveError(err)
+	}
+
+	for i := range individuals {
+		detail, err := db.GetIndividualSigningDetail(linkID, individuals[i].Email)
+		if err != nil {
+			return archiveError(err)
+		}
+		if detail != nil {
+			this.IndividualSignings = append(this.IndividualSignings, *detail)
+		}
+	}
+
+	return nil
+}
+
+func (this *LinkArchive) exportCorpManagers(db dbmodels.IDB) IModelError {
+	linkID := this.LinkID
+
+	for i := range this.CorpSignings {
+		ms, err := db.ListCorporationManager(linkID, this.CorpSignings[i].AdminEmail, "")
+		if err != nil {
+			return archiveError(err)
+		}
+
+		for j := range ms {
+			item := &ms[j]
+
+			v, err := db.CheckCorporationManagerExist(
+				dbmodels.CorporationManagerCheckInfo{Email: item.Email},
+			)
+			if err != nil {
+				return archiveError(err)
+			}
+
+			detail, ok := v[linkID]
+			if !ok {
+				continue
+			}
+
+			this.CorpManagers = append(this.CorpManagers, ArchivedCorpManager{
+				ID:               item.ID,
+				Name:             item.Name,
+				Role:             item.Role,
+				Email:            item.Email,
+				Password:         detail.Password,
+				InitialPWChanged: detail.InitialPWChanged,
+			})
+		}
+	}
+
+	return nil
+}
+
+// exportCLAInfos reads the cla infos of the versions which are signed,
+// because the cla info is only used to render the signings.
+func (this *LinkArchive) exportCLAInfos(db dbmodels.IDB) IModelError {
+	type version struct {
+		lang string
+		hash string
+	}
+
+	get := func(applyTo string, versions map[version]bool) ([]dbmodels.CLAInfo, IModelError) {
+		var r []dbmodels.CLAInfo
+
+		for v := range versions {
+			info, err := db.GetCLAInfoOfVersion(this.LinkID, v.lang, v.hash, applyTo)
+			if err != nil {
+				return nil, archiveError(err)
+			}
+
+			if info != nil {
+				info.CLALang = v.lang
+				r = append(r, *info)
+			}
+		}
+		return r, nil
+	}
+
+	versions := map[version]bool{}
+	for i := range this.IndividualSignings {
+		item := &this.IndividualSignings[i]
+		versions[version{item.CLALanguage, item.CLAHash}] = true
+	}
+	v, merr := get(dbmodels.ApplyToIndividual, versions)
+	if merr != nil {
+		return merr
+	}
+	this.IndividualCLAInfos = v
+
+	versions = map[version]bool{}
+	for i := range this.CorpSignings {
+		item := &this.CorpSignings[i]
+		versions[version{item.CLALanguage, item.CLAHash}] = true
+	}
+	v, merr = get(dbmodels.ApplyToCorporation, versions)
+	if merr != nil {
+		return merr
+	}
+	this.CorpCLAInfos = v
+
+	return nil
+}
+
+func (this *LinkArchive) exportCorpPDFs(db dbmodels.IDB) IModelError {
+	corps, err := db.ListCorporationsWithPDFUploaded(this.LinkID)
+	if err != nil {
+		return archiveError(err)
+	}
+	if len(corps) == 0 {
+		return nil
+	}
+
+	dir, err1 := ioutil.TempDir("", "link-archive")
+	if err1 != nil {
+		return newModelError(ErrSystemError, err1)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "pdf")
+	for _, corpID := range corps {
+		// the corp id is the suffix of email, so it can be used as the email directly.
+		if err := db.DownloadCorporationSigningPDF(this.LinkID, corpID, path); err != nil {
+			return archiveError(err)
+		}
+
+		pdf, err := ioutil.ReadFile(path)
+		if err != nil {
+			return newModelError(ErrSystemError, err)
+		}
+
+		this.CorpPDFs = append(this.CorpPDFs, ArchivedCorpPDF{CorpID: corpID, PDF: pdf})
+	}
+
+	return nil
+}
+
+// Import creates the link and saves all its data. Neither the link nor its id
+// must exist, because the data of a deleted link is kept with its id.
+func (this *LinkArchive) Import() IModelError {
+	if err := this.validate(); err != nil {
+		return newModelError(ErrInvalidArchive, err)
+	}
+
+	db := dbmodels.GetDB()
+
+	if _, err := db.GetLinkID(&this.OrgRepo); err == nil {
+		return newModelError(ErrLinkExists, fmt.Errorf("the link of %s exists", this.OrgRepoID()))
+	} else if !err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return parseDBError(err)
+	}
+
+	if b, err := db.HasLink(this.LinkID); err != nil {
+		return parseDBError(err)
+	} else if b {
+		return newModelError(ErrLinkExists, fmt.Errorf("the link id:%s is used", this.LinkID))
+	}
+
+	if merr := this.importLink(db); merr != nil {
+		return merr
+	}
+
+	if merr := this.importSignings(db); merr != nil {
+		return merr
+	}
+
+	if merr := this.importCorpManagers(db); merr != nil {
+		return merr
+	}
+
+	for i := range this.CorpPDFs {
+		item := &this.CorpPDFs[i]
+
+		if err := db.UploadCorporationSigningPDF(this.LinkID, item.CorpID, item.PDF); err != nil {
+			return parseDBError(err)
+		}
+	}
+
+	return nil
+}
+
+// validate checks the archive before anything is saved, because it may be
+// edited after being exported. The cla hashes must match the cla texts, and
+// each signing and manager must refer to the clas and signings of the archive.
+func (this *LinkArchive) validate() error {
+	if this.Version != linkArchiveVersion {
+		return fmt.Errorf("unsupported version of archive:%d", this.Version)
+	}
+
+	if this.LinkID == "" {
+		return fmt.Errorf("missing link id")
+	}
+
+	individualCLAs, err := archivedCLAVersions(this.IndividualCLAs, this.IndividualCLAInfos)
+	if err != nil {
+		return err
+	}
+
+	corpCLAs, err := archivedCLAVersions(this.CorpCLAs, this.CorpCLAInfos)
+	if err != nil {
+		return err
+	}
+
+	checkSigning := func(email, lang, hash string, versions map[string]bool) error {
+		if checkEmailFormat(email) != nil {
+			return fmt.Errorf("invalid email of signing:%s", email)
+		}
+		if !versions[lang+"/"+hash] {
+			return fmt.Errorf("the cla signed by %s is not in the archive", email)
+		}
+		return nil
+	}
+
+	corps := map[string]string{}
+	for i := range this.CorpSignings {
+		item := &this.CorpSignings[i]
+
+		if err := checkSigning(item.AdminEmail, item.CLALanguage, item.CLAHash, corpCLAs); err != nil {
+			return err
+		}
+
+		corpID := util.EmailSuffix(item.AdminEmail)
+		if _, ok := corps[corpID]; ok {
+			return fmt.Errorf("the corporation:%s signs more than once", corpID)
+		}
+		corps[corpID] = item.AdminEmail
+	}
+
+	// the pdf is kept after the signing is deleted.
+	pdfs := map[string]bool{}
+	for corpID := range corps {
+		pdfs[corpID] = true
+	}
+
+	for i := range this.DeletedCorpSignings {
+		item := &this.DeletedCorpSignings[i]
+
+		if err := checkSigning(item.AdminEmail, item.CLALanguage, item.CLAHash, corpCLAs); err != nil {
+			return err
+		}
+		pdfs[util.EmailSuffix(item.AdminEmail)] = true
+	}
+
+	for i := range this.IndividualSignings {
+		item := &this.IndividualSignings[i]
+
+		if err := checkSigning(item.Email, item.CLALanguage, item.CLAHash, individualCLAs); err != nil {
+			return err
+		}
+	}
+
+	for i := range this.CorpManagers {
+		item := &this.CorpManagers[i]
+
+		admin, ok := corps[util.EmailSuffix(item.Email)]
+		if !ok || checkEmailFormat(item.Email) != nil {
+			return fmt.Errorf("the corporation of manager:%s has not signed", item.Email)
+		}
+
+		switch item.Role {
+		case dbmodels.RoleAdmin:
+			if item.Email != admin {
+				return fmt.Errorf("the admin:%s is not the one who signed", item.Email)
+			}
+		case dbmodels.RoleManager:
+		default:
+			return fmt.Errorf("unknown role of manager:%s", item.Email)
+		}
+
+		// the password is saved as hash, so the plaintext one can't be used to log in.
+		if !isEncryptedPassword(item.Password) {
+			return fmt.Errorf("the password of manager:%s is not encrypted", item.Email)
+		}
+	}
+
+	for i := range this.CorpPDFs {
+		if !pdfs[this.CorpPDFs[i].CorpID] {
+			return fmt.Errorf("the corporation of pdf:%s has not signed", this.CorpPDFs[i].CorpID)
+		}
+	}
+
+	return nil
+}
+
+// archivedCLAVersions checks the hashes of cla texts and returns the versions
+// which can be signed, which are the clas and the cla infos of the older ones.
+func archivedCLAVersions(clas []ArchivedCLA, infos []dbmodels.CLAInfo) (map[string]bool, error) {
+	r := map[string]bool{}
+
+	for i := range clas {
+		item := &clas[i]
+
+		text := []byte(item.Text)
+		if h := util.Md5sumOfBytes(&text); h != item.CLAHash {
+			return nil, fmt.Errorf(
+				"the cla hash of %s version:%d mismatches the text", item.Language, item.Version,
+			)
+		}
+		r[item.Language+"/"+item.CLAHash] = true
+	}
+
+	for i := range infos {
+		r[infos[i].CLALang+"/"+infos[i].CLAHash] = true
+	}
+
+	return r, nil
+}
+
+func (this *LinkArchive) importLink(db dbmodels.IDB) IModelError {
+	// the token of org email saved in the link should be encrypted,
+	// and it can only be got by reading the org email.
+	orgEmail, err := db.GetOrgEmailInfo(this.OrgEmail.Email)
+	if err != nil {
+		if !err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+			return parseDBError(err)
+		}
+
+		if err := db.CreateOrgEmail(this.OrgEmail); err != nil {
+			return parseDBError(err)
+		}
+
+		if orgEmail, err = db.GetOrgEmailInfo(this.OrgEmail.Email); err != nil {
+			return parseDBError(err)
+		}
+	}
+
+	toCLAs := func(clas []ArchivedCLA) []dbmodels.CLACreateOption {
+		r := make([]dbmodels.CLACreateOption, 0, len(clas))
+		for i := range clas {
+			item := &clas[i]
+
+			opt := dbmodels.CLACreateOption{CLADetail: item.CLADetail}
+			if item.OrgSignature != nil {
+				opt.OrgSignature = &item.OrgSignature
+				opt.OrgSignatureHash = util.Md5sumOfBytes(&item.OrgSignature)
+			}
+			r = append(r, opt)
+		}
+		return r
+	}
+
+	opt := dbmodels.LinkCreateOption{
+		LinkID:         this.LinkID,
+		Submitter:      this.Submitter,
+		OrgRepo:        this.OrgRepo,
+		OrgAlias:       this.OrgAlias,
+		OrgEmail:       *orgEmail,
+		IndividualCLAs: toCLAs(this.IndividualCLAs),
+		CorpCLAs:       toCLAs(this.CorpCLAs),
+	}
+	if _, err := db.CreateLink(&opt); err != nil {
+		if err.IsErrorOf(dbmodels.ErrRecordExists) {
+			return newModelError(ErrLinkExists, err)
+		}
+		return parseDBError(err)
+	}
+
+	if err := db.InitializeIndividualSigning(this.LinkID, nil); err != nil {
+		return parseDBError(err)
+	}
+
+	if err := db.InitializeCorpSigning(this.LinkID, &this.OrgInfo, nil); err != nil {
+		return parseDBError(err)
+	}
+
+	for i := range this.IndividualCLAInfos {
+		if err := db.AddCLAInfo(this.LinkID, dbmodels.ApplyToIndividual, &this.IndividualCLAInfos[i]); err != nil {
+			return parseDBError(err)
+		}
+	}
+
+	for i := range this.CorpCLAInfos {
+		if err := db.AddCLAInfo(this.LinkID, dbmodels.ApplyToCorporation, &this.CorpCLAInfos[i]); err != nil {
+			return parseDBError(err)
+		}
+	}
+
+	return nil
+}
+
+func (this *LinkArchive) importSignings(db dbmodels.IDB) IModelError {
+	linkID := this.LinkID
+
+	// The deleted ones are imported first, because a corporation
+	// may sign again after its signing was deleted.
+	for i := range this.DeletedCorpSignings {
+		item := &dbmodels.CorpSigningCreateOpt{
+			CorporationSigningBasicInfo: this.DeletedCorpSignings[i],
+		}
+
+		if err := db.SignCorpCLA(linkID, item); err != nil {
+			return parseDBError(err)
+		}
+
+		if err := db.DeleteCorpSigning(linkID, item.AdminEmail, nil); err != nil {
+			return parseDBError(err)
+		}
+	}
+
+	for i := range this.CorpSignings {
+		if err := db.SignCorpCLA(linkID, &this.CorpSignings[i]); err != nil {
+			return parseDBError(err)
+		}
+	}
+
+	for i := range this.IndividualSignings {
+		if err := db.SignIndividualCLA(linkID, &this.IndividualSignings[i]); err != nil {
+			return parseDBError(err)
+		}
+	}
+
+	return nil
+}
+
+func (this *LinkArchive) importCorpManagers(db dbmodels.IDB) IModelError {
+	linkID := this.LinkID
+
+	var managers []dbmodels.CorporationManagerCreateOption
+	for i := range this.CorpManagers {
+		item := &this.CorpManagers[i]
+
+		opt := dbmodels.CorporationManagerCreateOption{
+			ID:       item.ID,
+			Name:     item.Name,
+			Role:     item.Role,
+			Email:    item.Email,
+			Password: item.Password,
+		}
+
+		if item.Role != dbmodels.RoleAdmin {
+			managers = append(managers, opt)
+			continue
+		}
+
+		if err := db.AddCorpAdministrator(linkID, &opt); err != nil {
+			return parseDBError(err)
+		}
+	}
+
+	if len(managers) > 0 {
+		if err := db.AddEmployeeManager(linkID, managers); err != nil {
+			return parseDBError(err)
+		}
+	}
+
+	// resetting the password with the same one marks that the initial password was changed.
+	for i := range this.CorpManagers {
+		item := &this.CorpManagers[i]
+		if !item.InitialPWChanged {
+			continue
+		}
+
+		err := db.ResetCorporationManagerPassword(
+			linkID, item.Email, dbmodels.CorporationManagerResetPassword{
+				OldPassword: item.Password, NewPassword: item.Password,
+			},
+		)
+		if err != nil {
+			return parseDBError(err)
+		}
+	}
+
+	return nil
+}
+
+// Write saves the archive as gzipped json.
+func (this *LinkArchive) Write(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+
+	if err := json.NewEncoder(zw).Encode(this); err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+func ReadLinkArchive(r io.Reader) (*LinkArchive, IModelError) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, newModelError(ErrInvalidArchive, err)
+	}
+	defer zr.Close()
+
+	a := new(LinkArchive)
+	if err := json.NewDecoder(zr).Decode(a); err != nil {
+		return nil, newModelError(ErrInvalidArchive, err)
+	}
+	return a, nil
+}
@@ -74,14 +74,14 @@ func (this LinkCreateOption) Create(linkID, submitter string) IModelError {
 	cla := this.IndividualCLA
 	if cla != nil {
 		info.IndividualCLAs = []dbmodels.CLACreateOption{
-			*cla.toCLACreateOption(),
+			*cla.toCLACreateOption(1),
 		}
 	}
 
 	cla = this.CorpCLA
 	if cla != nil {
 		info.CorpCLAs = []dbmodels.CLACreateOption{
-			*cla.toCLACreateOption(),
+			*cla.toCLACreateOption(1),
 		}
 	}
 
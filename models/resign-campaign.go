@@ -0,0 +1,235 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+// the max grace period of campaign in days
+const maxGracePeriod = 365
+
+type ResignCampaign = dbmodels.ResignCampaign
+
+type ResignCampaignCreateOpt struct {
+	// GracePeriod is the number of days within which the signers should sign the current version.
+	GracePeriod int    `json:"grace_period"`
+	SigningURL  string `json:"signing_url"`
+}
+
+func (this *ResignCampaignCreateOpt) Validate() IModelError {
+	if this.GracePeriod <= 0 || this.GracePeriod > maxGracePeriod {
+		return newModelError(
+			ErrInvalidGracePeriod,
+			fmt.Errorf("grace period should be between 1 and %d days", maxGracePeriod),
+		)
+	}
+
+	v, err := url.Parse(this.SigningURL)
+	if err != nil || (v.Scheme != "https" && v.Scheme != "http") || v.Host == "" {
+		return newModelError(ErrInvalidSigningURL, fmt.Errorf("invalid signing url"))
+	}
+
+	return nil
+}
+
+// Create picks out the signers who signed an older version of cla, and saves them
+// in the campaign as pending. It returns ErrNoSignerToResign if there is none.
+func (this *ResignCampaignCreateOpt) Create(linkID, createdBy string) (*ResignCampaign, IModelError) {
+	clas, merr := GetAllCLA(linkID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	corps, merr := ListCorpSignings(linkID, "")
+	if merr != nil {
+		return nil, merr
+	}
+
+	individuals, merr := ListIndividualSigning(linkID, "", "")
+	if merr != nil {
+		return nil, merr
+	}
+
+	var signers []dbmodels.ResignCampaignSigner
+
+	isCorpOutdated := signedOlderVersion(clas.CorpCLAs)
+	corpIDs := make(map[string]bool, len(corps))
+	for i := range corps {
+		item := &corps[i]
+
+		corpIDs[util.EmailSuffix(item.AdminEmail)] = true
+
+		if isCorpOutdated(item.CLALanguage, item.CLAHash) {
+			signers = append(signers, dbmodels.ResignCampaignSigner{
+				Email:       item.AdminEmail,
+				Name:        item.AdminName,
+				Type:        dbmodels.SignerCorporation,
+				CLALanguage: item.CLALanguage,
+			})
+		}
+	}
+
+	isIndividualOutdated := signedOlderVersion(clas.IndividualCLAs)
+	for i := range individuals {
+		item := &individuals[i]
+
+		if !isIndividualOutdated(item.CLALanguage, item.CLAHash) {
+			continue
+		}
+
+		t := dbmodels.SignerIndividual
+		if corpIDs[util.EmailSuffix(item.Email)] {
+			t = dbmodels.SignerEmployee
+		}
+
+		signers = append(signers, dbmodels.ResignCampaignSigner{
+			Email:       item.Email,
+			Name:        item.Name,
+			Type:        t,
+			CLALanguage: item.CLALanguage,
+		})
+	}
+
+	if len(signers) == 0 {
+		return nil, newModelError(ErrNoSignerToResign, fmt.Errorf("no signer to re-sign"))
+	}
+
+	now := util.Now()
+	campaign := ResignCampaign{
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		Deadline:  now + int64(this.GracePeriod)*24*3600,
+		Signers:   signers,
+	}
+
+	id, err := dbmodels.GetDB().AddResignCampaign(linkID, &campaign)
+	if err != nil {
+		if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+			return nil, newModelError(ErrNoLink, err)
+		}
+		return nil, parseDBError(err)
+	}
+
+	campaign.ID = id
+	for i := range campaign.Signers {
+		campaign.Signers[i].Status = dbmodels.ResignStatusPending
+	}
+	return &campaign, nil
+}
+
+// signedOlderVersion returns the function which reports whether the signing of
+// the version whose hash is claHash is older than the current version of language.
+// The signing which was saved before versioning signed the first version.
+func signedOlderVersion(clas []dbmodels.CLADetail) func(claLang, claHash string) bool {
+	current := map[string]*dbmodels.CLADetail{}
+	first := map[string]*dbmodels.CLADetail{}
+	for i := range clas {
+		item := &clas[i]
+
+		if v, ok := current[item.Language]; !ok || item.Version > v.Version {
+			current[item.Language] = item
+		}
+		if v, ok := first[item.Language]; !ok || item.Version < v.Version {
+			first[item.Language] = item
+		}
+	}
+
+	return func(claLang, claHash string) bool {
+		v, ok := current[claLang]
+		if !ok {
+			// there is nothing to re-sign if the cla of the language was deleted.
+			return false
+		}
+
+		if claHash == "" {
+			claHash = first[claLang].CLAHash
+		}
+		return claHash != v.CLAHash
+	}
+}
+
+func ListResignCampaigns(linkID string) ([]ResignCampaign, IModelError) {
+	v, err := dbmodels.GetDB().ListResignCampaigns(linkID)
+	if err == nil {
+		if v == nil {
+			v = []ResignCampaign{}
+		}
+		return v, nil
+	}
+
+	return nil, parseDBError(err)
+}
+
+// GetResignCampaign returns nil if there is no such campaign.
+func GetResignCampaign(linkID, campaignID string) (*ResignCampaign, IModelError) {
+	v, merr := ListResignCampaigns(linkID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	for i := range v {
+		if v[i].ID == campaignID {
+			return &v[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func signerTypes(corp bool) []string {
+	if corp {
+		return []string{dbmodels.SignerCorporation}
+	}
+	return []string{dbmodels.SignerIndividual, dbmodels.SignerEmployee}
+}
+
+// ResignInCampaigns sets the signer to be resigned in all the campaigns. It should be
+// called after the signer signs the current version of cla. corp means the signer is
+// the corporation whose admin email is email.
+func ResignInCampaigns(linkID, email string, corp bool) IModelError {
+	emails := []string{email}
+	if corp {
+		// the corporation may re-sign with another admin email.
+		v, merr := pendingCorpEmails(linkID, email)
+		if merr != nil {
+			return merr
+		}
+		emails = v
+	}
+
+	now := util.Now()
+	for _, item := range emails {
+		err := dbmodels.GetDB().ResignInCampaigns(linkID, item, signerTypes(corp), now)
+		if err != nil {
+			return parseDBError(err)
+		}
+	}
+	return nil
+}
+
+// pendingCorpEmails returns the admin emails of the corporation which
+// the email belongs to, with which it is pending in the campaigns.
+func pendingCorpEmails(linkID, email string) ([]string, IModelError) {
+	campaigns, merr := ListResignCampaigns(linkID)
+	if merr != nil {
+		return nil, merr
+	}
+
+	corpID := util.EmailSuffix(email)
+	m := map[string]bool{}
+	var r []string
+	for i := range campaigns {
+		for _, item := range campaigns[i].Signers {
+			if item.Type == dbmodels.SignerCorporation &&
+				item.Status == dbmodels.ResignStatusPending &&
+				util.EmailSuffix(item.Email) == corpID && !m[item.Email] {
+
+				m[item.Email] = true
+				r = append(r, item.Email)
+			}
+		}
+	}
+	return r, nil
+}
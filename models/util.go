@@ -43,3 +43,8 @@ func encryptPassword(pwd string) (string, IModelError) {
 func isSamePasswords(hashedPwd, plainPwd string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPwd), []byte(plainPwd)) == nil
 }
+
+func isEncryptedPassword(pwd string) bool {
+	_, err := bcrypt.Cost([]byte(pwd))
+	return err == nil
+}
@@ -0,0 +1,94 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) AddAuditLog(log *dbmodels.AuditLog) dbmodels.IDBError {
+	actor, err := this.encrypt.encryptStr(log.Actor)
+	if err != nil {
+		return err
+	}
+
+	target, err := this.encrypt.encryptStr(log.Target)
+	if err != nil {
+		return err
+	}
+
+	doc, err := structToMap(cAuditLog{
+		Actor:      actor,
+		RemoteAddr: log.RemoteAddr,
+		Action:     log.Action,
+		LinkID:     log.LinkID,
+		CorpID:     log.CorpID,
+		Target:     target,
+		Time:       log.Time,
+	})
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) error {
+		_, err := this.insertDoc(ctx, this.auditLogCollection, doc)
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return newSystemError(err)
+	}
+	return nil
+}
+
+func (this *client) ListAuditLogs(opt *dbmodels.AuditLogListOption) ([]dbmodels.AuditLog, dbmodels.IDBError) {
+	filter := bson.M{fieldLinkID: opt.LinkID}
+	if opt.CorpID != "" {
+		filter[fieldCorpID] = opt.CorpID
+	}
+
+	var docs []cAuditLog
+	f := func(ctx context.Context) error {
+		cursor, err := this.collection(this.auditLogCollection).Find(
+			ctx, filter, options.Find().SetSort(bson.M{fieldTime: 1}),
+		)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &docs)
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, newSystemError(err)
+	}
+
+	r := make([]dbmodels.AuditLog, 0, len(docs))
+	for i := range docs {
+		item := &docs[i]
+
+		actor, err := this.encrypt.decryptStr(item.Actor)
+		if err != nil {
+			return nil, err
+		}
+
+		target, err := this.encrypt.decryptStr(item.Target)
+		if err != nil {
+			return nil, err
+		}
+
+		r = append(r, dbmodels.AuditLog{
+			Actor:      actor,
+			RemoteAddr: item.RemoteAddr,
+			Action:     item.Action,
+			LinkID:     item.LinkID,
+			CorpID:     item.CorpID,
+			Target:     target,
+			Time:       item.Time,
+		})
+	}
+
+	return r, nil
+}
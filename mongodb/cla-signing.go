@@ -3,6 +3,8 @@ package mongodb
 import (
 	"context"
 
+	"go.mongodb.org/mongo-driver/bson"
+
 	"github.com/opensourceways/app-cla-server/dbmodels"
 )
 
@@ -62,6 +64,13 @@ func (this *client) collectionOfSigning(applyTo string) string {
 	return this.individualSigningCollection
 }
 
+func (this *client) docFilterOfSigningRecord(linkID, applyTo string) (string, bson.M) {
+	if applyTo == dbmodels.ApplyToCorporation {
+		return this.corpSigningRecordCollection, docFilterOfSigningRecords(linkID, false)
+	}
+	return this.individualSigningRecordCollection, docFilterOfSigningRecords(linkID, false)
+}
+
 func (this *client) DeleteCLAInfo(linkID, applyTo, claLang string) dbmodels.IDBError {
 	f := func(ctx context.Context) dbmodels.IDBError {
 		return this.pullArrayElem(
@@ -80,7 +89,9 @@ func (this *client) AddCLAInfo(linkID, applyTo string, info *dbmodels.CLAInfo) d
 	}
 
 	docFilter := docFilterOfSigning(linkID)
-	arrayFilterByElemMatch(fieldCLAInfos, false, elemFilterOfCLA(info.CLALang), docFilter)
+	arrayFilterByElemMatch(
+		fieldCLAInfos, false, elemFilterOfCLAInfo(info.CLALang, info.CLAHash), docFilter,
+	)
 
 	f := func(ctx context.Context) dbmodels.IDBError {
 		return this.pushArrayElem(
@@ -94,16 +105,56 @@ func (this *client) AddCLAInfo(linkID, applyTo string, info *dbmodels.CLAInfo) d
 func (this *client) GetCLAInfoSigned(linkID, claLang, applyTo string) (*dbmodels.CLAInfo, dbmodels.IDBError) {
 	elemFilter := elemFilterOfCLA(claLang)
 
-	docFilter := docFilterOfSigning(linkID)
-	arrayFilterByElemMatch(fieldSignings, true, elemFilter, docFilter)
+	records, recordFilter := this.docFilterOfSigningRecord(linkID, applyTo)
+	recordFilter[fieldLang] = claLang
 
 	var v []struct {
 		CLAInfos []DCLAInfo `bson:"cla_infos" json:"cla_infos"`
 	}
 
+	f := func(ctx context.Context) dbmodels.IDBError {
+		n, err := this.countDocs(ctx, records, recordFilter)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errNoDBRecord
+		}
+
+		err1 := this.getArrayElem(
+			ctx, this.collectionOfSigning(applyTo), fieldCLAInfos,
+			docFilterOfSigning(linkID), elemFilter, nil, &v,
+		)
+		if err1 != nil {
+			return newSystemError(err1)
+		}
+		return nil
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+
+	if len(v) == 0 {
+		return nil, errNoDBRecord
+	}
+
+	// the cla infos are appended in the order of versions
+	if n := len(v[0].CLAInfos); n > 0 {
+		return toModelOfCLAInfo(&v[0].CLAInfos[n-1]), nil
+	}
+	return nil, nil
+}
+
+func (this *client) GetCLAInfoOfVersion(linkID, claLang, claHash, applyTo string) (*dbmodels.CLAInfo, dbmodels.IDBError) {
+	var v []struct {
+		CLAInfos []DCLAInfo `bson:"cla_infos" json:"cla_infos"`
+	}
+
 	f := func(ctx context.Context) error {
 		return this.getArrayElem(
-			ctx, this.collectionOfSigning(applyTo), fieldCLAInfos, docFilter, elemFilter, nil, &v,
+			ctx, this.collectionOfSigning(applyTo), fieldCLAInfos,
+			docFilterOfSigning(linkID), elemFilterOfCLAInfo(claLang, claHash), nil, &v,
 		)
 	}
 
@@ -118,13 +169,16 @@ func (this *client) GetCLAInfoSigned(linkID, claLang, applyTo string) (*dbmodels
 	if len(v[0].CLAInfos) == 0 {
 		return nil, nil
 	}
+	return toModelOfCLAInfo(&v[0].CLAInfos[0]), nil
+}
 
-	doc := &(v[0].CLAInfos[0])
+func toModelOfCLAInfo(doc *DCLAInfo) *dbmodels.CLAInfo {
 	return &dbmodels.CLAInfo{
+		CLALang:          doc.Language,
 		CLAHash:          doc.CLAHash,
 		OrgSignatureHash: doc.OrgSignatureHash,
 		Fields:           toModelOfCLAFields(doc.Fields),
-	}, nil
+	}
 }
 
 func toDocOfCLAInfo(info *dbmodels.CLAInfo) *DCLAInfo {
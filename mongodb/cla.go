@@ -3,6 +3,7 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"go.mongodb.org/mongo-driver/bson"
 
@@ -27,6 +28,29 @@ func elemFilterOfCLA(language string) bson.M {
 	return bson.M{fieldLang: language}
 }
 
+func elemFilterOfCLAInfo(language, claHash string) bson.M {
+	filter := elemFilterOfCLA(language)
+	if claHash != "" {
+		filter[fieldCLAHash] = claHash
+	}
+	return filter
+}
+
+// latestCLA returns the current version of cla among the versions of the same language.
+func latestCLA(v []dCLA) *dCLA {
+	if len(v) == 0 {
+		return nil
+	}
+
+	r := &v[0]
+	for i := 1; i < len(v); i++ {
+		if v[i].Version > r.Version {
+			r = &v[i]
+		}
+	}
+	return r
+}
+
 func (this *client) HasCLA(linkID, applyTo, language string) (bool, dbmodels.IDBError) {
 	claField := fieldNameOfCLA(applyTo)
 
@@ -62,8 +86,11 @@ func (this *client) AddCLA(linkID, applyTo string, cla *dbmodels.CLACreateOption
 
 	claField := fieldNameOfCLA(applyTo)
 
+	// the same content can't be added as a new version
 	docFilter := docFilterOfCLA(linkID)
-	arrayFilterByElemMatch(claField, false, elemFilterOfCLA(cla.Language), docFilter)
+	arrayFilterByElemMatch(
+		claField, false, elemFilterOfCLAInfo(cla.Language, cla.CLAHash), docFilter,
+	)
 
 	f := func(ctx context.Context) dbmodels.IDBError {
 		return this.pushArrayElem(
@@ -74,6 +101,58 @@ func (this *client) AddCLA(linkID, applyTo string, cla *dbmodels.CLACreateOption
 	return withContext1(f)
 }
 
+func (this *client) ListCLAVersions(linkID, applyTo, language string) ([]dbmodels.CLAVersion, dbmodels.IDBError) {
+	claField := fieldNameOfCLA(applyTo)
+
+	fn := func(s string) string {
+		return fmt.Sprintf("%s.%s", claField, s)
+	}
+
+	var v []cLink
+	f := func(ctx context.Context) error {
+		return this.getArrayElem(
+			ctx, this.linkCollection, claField,
+			docFilterOfCLA(linkID), elemFilterOfCLA(language),
+			bson.M{
+				fn(fieldVersion): 1,
+				fn(fieldCLAHash): 1,
+				fn(fieldPolicy):  1,
+			}, &v,
+		)
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, newSystemError(err)
+	}
+
+	if len(v) == 0 {
+		return nil, errNoDBRecord
+	}
+
+	doc := v[0].CorpCLAs
+	if applyTo == dbmodels.ApplyToIndividual {
+		doc = v[0].IndividualCLAs
+	}
+	if len(doc) == 0 {
+		return nil, nil
+	}
+
+	r := make([]dbmodels.CLAVersion, 0, len(doc))
+	for i := range doc {
+		item := &doc[i]
+		r = append(r, dbmodels.CLAVersion{
+			Version: item.Version,
+			CLAHash: item.CLAHash,
+			Policy:  item.Policy,
+		})
+	}
+
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Version < r[j].Version
+	})
+	return r, nil
+}
+
 func (this *client) DeleteCLA(linkID, applyTo, language string) dbmodels.IDBError {
 	f := func(ctx context.Context) dbmodels.IDBError {
 		return this.pullArrayElem(
@@ -96,6 +175,7 @@ func (this *client) GetCLAByType(orgRepo *dbmodels.OrgRepo, applyTo string) (str
 		project = bson.M{
 			fieldOrgEmail:       0,
 			fieldIndividualCLAs: 0,
+			fieldCorpPDFTmpls:   0,
 			fmt.Sprintf("%s.%s", fieldCorpCLAs, fieldOrgSignature): 0,
 		}
 	}
@@ -156,6 +236,7 @@ func (this *client) GetCLAInfoToSign(linkID, claLang, applyTo string) (*dbmodels
 				fn(fieldFields):        1,
 				fn(fieldCLAHash):       1,
 				fn(fieldSignatureHash): 1,
+				fn(fieldVersion):       1,
 			}, &v,
 		)
 	}
@@ -175,11 +256,11 @@ func (this *client) GetCLAInfoToSign(linkID, claLang, applyTo string) (*dbmodels
 		doc = v[0].CorpCLAs
 	}
 
-	if len(doc) == 0 {
+	item := latestCLA(doc)
+	if item == nil {
 		return nil, nil
 	}
 
-	item := &(doc[0])
 	return &dbmodels.CLAInfo{
 		CLAHash:          item.CLAHash,
 		OrgSignatureHash: item.OrgSignatureHash,
@@ -187,13 +268,16 @@ func (this *client) GetCLAInfoToSign(linkID, claLang, applyTo string) (*dbmodels
 	}, nil
 }
 
-func (this *client) DownloadCorpCLAPDF(linkID, lang string) ([]byte, dbmodels.IDBError) {
+func (this *client) DownloadCorpCLAPDF(linkID, lang string, version int) ([]byte, dbmodels.IDBError) {
 	var v []cLink
 
+	elemFilter := elemFilterOfCLA(lang)
+	elemFilter[fieldVersion] = version
+
 	f := func(ctx context.Context) error {
 		return this.getArrayElem(
 			ctx, this.linkCollection, fieldCorpCLAs,
-			docFilterOfCLA(linkID), elemFilterOfCLA(lang),
+			docFilterOfCLA(linkID), elemFilter,
 			bson.M{fmt.Sprintf("%s.%s", fieldCorpCLAs, fieldOrgSignature): 1},
 			&v,
 		)
@@ -213,6 +297,27 @@ func (this *client) DownloadCorpCLAPDF(linkID, lang string) ([]byte, dbmodels.ID
 	return v[0].CorpCLAs[0].OrgSignature, nil
 }
 
+func (this *client) UpdateCLADrift(linkID, applyTo, language string, version int, drift *dbmodels.CLADrift) dbmodels.IDBError {
+	elemFilter := elemFilterOfCLA(language)
+	elemFilter[fieldVersion] = version
+
+	doc := dCLADrift{
+		Status:    drift.Status,
+		CheckedAt: drift.CheckedAt,
+		URLHash:   drift.URLHash,
+		Draft:     drift.Draft,
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.updateArrayElem(
+			ctx, this.linkCollection, fieldNameOfCLA(applyTo),
+			docFilterOfCLA(linkID), elemFilter, bson.M{fieldDrift: doc},
+		)
+	}
+
+	return withContext1(f)
+}
+
 func toModelOfCLAFields(fields []dField) []dbmodels.Field {
 	fs := make([]dbmodels.Field, 0, len(fields))
 	for i := range fields {
@@ -223,6 +328,9 @@ func toModelOfCLAFields(fields []dField) []dbmodels.Field {
 			Type:        v.Type,
 			Description: v.Description,
 			Required:    v.Required,
+			Options:     v.Options,
+			Regex:       v.Regex,
+			MaxLength:   v.MaxLength,
 		})
 	}
 	return fs
@@ -237,10 +345,21 @@ func toModelOfCLAs(data []dCLA) []dbmodels.CLADetail {
 		cla := dbmodels.CLADetail{
 			Text:    item.Text,
 			CLAHash: item.CLAHash,
+			Version: item.Version,
 		}
 
 		cla.URL = item.URL
 		cla.Language = item.Language
+		cla.Policy = item.Policy
+
+		if v := item.Drift; v != nil {
+			cla.Drift = &dbmodels.CLADrift{
+				Status:    v.Status,
+				CheckedAt: v.CheckedAt,
+				URLHash:   v.URLHash,
+				Draft:     v.Draft,
+			}
+		}
 
 		if len(item.Fields) > 0 {
 			cla.Fields = toModelOfCLAFields(item.Fields)
@@ -0,0 +1,89 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func elemFilterOfCorpPDFTemplate(language string) bson.M {
+	return bson.M{fieldLang: language}
+}
+
+func (this *client) SaveCorpPDFTemplate(linkID string, t *dbmodels.CorpPDFTemplate) dbmodels.IDBError {
+	body, err := structToMap(dCorpPDFTemplate{
+		Language:    t.Language,
+		Welcome:     t.Welcome,
+		Declaration: t.Declaration,
+		LogoType:    t.LogoType,
+	})
+	if err != nil {
+		return err
+	}
+	if t.Logo != nil {
+		body["logo"] = t.Logo
+	}
+
+	docFilter := docFilterOfCLA(linkID)
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.pullArrayElem(
+			ctx, this.linkCollection, fieldCorpPDFTmpls, docFilter,
+			elemFilterOfCorpPDFTemplate(t.Language),
+		)
+		if err != nil {
+			return err
+		}
+
+		return this.pushArrayElem(ctx, this.linkCollection, fieldCorpPDFTmpls, docFilter, body)
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) GetCorpPDFTemplate(linkID, language string) (*dbmodels.CorpPDFTemplate, dbmodels.IDBError) {
+	var v []cLink
+
+	f := func(ctx context.Context) error {
+		return this.getArrayElem(
+			ctx, this.linkCollection, fieldCorpPDFTmpls,
+			docFilterOfCLA(linkID), elemFilterOfCorpPDFTemplate(language),
+			bson.M{fieldCorpPDFTmpls: 1}, &v,
+		)
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, newSystemError(err)
+	}
+
+	if len(v) == 0 {
+		return nil, errNoDBRecord
+	}
+
+	items := v[0].CorpPDFTemplates
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	item := &items[0]
+	return &dbmodels.CorpPDFTemplate{
+		Language:    item.Language,
+		Welcome:     item.Welcome,
+		Declaration: item.Declaration,
+		Logo:        item.Logo,
+		LogoType:    item.LogoType,
+	}, nil
+}
+
+func (this *client) DeleteCorpPDFTemplate(linkID, language string) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.pullArrayElem(
+			ctx, this.linkCollection, fieldCorpPDFTmpls,
+			docFilterOfCLA(linkID), elemFilterOfCorpPDFTemplate(language),
+		)
+	}
+
+	return withContext1(f)
+}
@@ -0,0 +1,49 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) GetCorpSigningPolicy(linkID string) (*dbmodels.CorpSigningPolicy, dbmodels.IDBError) {
+	var v cLink
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.getDoc(
+			ctx, this.linkCollection, docFilterOfCLA(linkID),
+			bson.M{fieldSigningPolicy: 1}, &v,
+		)
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+
+	p := v.CorpSigningPolicy
+	if p == nil {
+		return nil, nil
+	}
+
+	return &dbmodels.CorpSigningPolicy{
+		ESigning:           p.ESigning,
+		ScannedPDFRequired: p.ScannedPDFRequired,
+	}, nil
+}
+
+func (this *client) UpdateCorpSigningPolicy(linkID string, p *dbmodels.CorpSigningPolicy) dbmodels.IDBError {
+	body := dCorpSigningPolicy{
+		ESigning:           p.ESigning,
+		ScannedPDFRequired: p.ScannedPDFRequired,
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.updateDoc(
+			ctx, this.linkCollection, docFilterOfCLA(linkID),
+			bson.M{fieldSigningPolicy: body},
+		)
+	}
+
+	return withContext1(f)
+}
@@ -2,7 +2,6 @@ package mongodb
 
 import (
 	"context"
-	"fmt"
 
 	"go.mongodb.org/mongo-driver/bson"
 
@@ -17,8 +16,12 @@ func (c *client) elemFilterOfCorpManager(email string) (bson.M, dbmodels.IDBErro
 	return c.elemFilterOfIndividualSigning(email)
 }
 
-func memberNameOfCorpManager(field string) string {
-	return fmt.Sprintf("%s.%s", fieldCorpManagers, field)
+func docFilterOfCorpManagers(linkID string, elemFilter bson.M) bson.M {
+	filter := docFilterOfCorpManager(linkID)
+	for k, v := range elemFilter {
+		filter[k] = v
+	}
+	return filter
 }
 
 func (this *client) AddCorpAdministrator(linkID string, opt *dbmodels.CorporationManagerCreateOption) dbmodels.IDBError {
@@ -28,31 +31,22 @@ func (this *client) AddCorpAdministrator(linkID string, opt *dbmodels.Corporatio
 	}
 
 	info := dCorpManager{
-		ID:       opt.ID,
-		Name:     opt.Name,
-		Email:    email,
-		Role:     dbmodels.RoleAdmin,
-		Password: opt.Password,
-		CorpID:   genCorpID(opt.Email),
+		ID:         opt.ID,
+		Name:       opt.Name,
+		Email:      email,
+		EmailIndex: this.emailIndex.of(opt.Email),
+		Role:       dbmodels.RoleAdmin,
+		Password:   opt.Password,
+		CorpID:     genCorpID(opt.Email),
 	}
 	body, err := structToMap(info)
 	if err != nil {
 		return err
 	}
 
-	docFilter := docFilterOfCorpManager(linkID)
-	arrayFilterByElemMatch(
-		fieldCorpManagers, false,
-		bson.M{
-			fieldCorpID: genCorpID(opt.Email),
-			fieldRole:   dbmodels.RoleAdmin,
-		},
-		docFilter,
-	)
-
 	f := func(ctx context.Context) dbmodels.IDBError {
-		return this.pushArrayElem(
-			ctx, this.corpSigningCollection, fieldCorpManagers, docFilter, body,
+		return this.insertSigningRecord(
+			ctx, this.corpSigningCollection, this.corpManagerCollection, linkID, body,
 		)
 	}
 
@@ -60,61 +54,83 @@ func (this *client) AddCorpAdministrator(linkID string, opt *dbmodels.Corporatio
 }
 
 func (this *client) CheckCorporationManagerExist(opt dbmodels.CorporationManagerCheckInfo) (map[string]dbmodels.CorporationManagerCheckResult, dbmodels.IDBError) {
-	docFilter := bson.M{
-		fieldLinkStatus:   linkStatusReady,
-		fieldCorpManagers: bson.M{"$type": "array"},
-	}
+	filter := bson.M{fieldLinkStatus: linkStatusReady}
 
-	var elemFilter bson.M
 	if opt.Email != "" {
 		v, err := this.elemFilterOfCorpManager(opt.Email)
 		if err != nil {
 			return nil, err
 		}
-		elemFilter = v
-	} else {
-		elemFilter = bson.M{
-			fieldCorpID: opt.EmailSuffix,
-			fieldID:     opt.ID,
+		for k, item := range v {
+			filter[k] = item
 		}
+	} else {
+		filter[fieldCorpID] = opt.EmailSuffix
+		filter[fieldID] = opt.ID
 	}
 
 	project := bson.M{
-		fieldLinkID:                            1,
-		fieldOrgIdentity:                       1,
-		fieldOrgEmail:                          1,
-		fieldOrgAlias:                          1,
-		memberNameOfCorpManager(fieldRole):     1,
-		memberNameOfCorpManager(fieldName):     1,
-		memberNameOfCorpManager(fieldEmail):    1,
-		memberNameOfCorpManager(fieldPassword): 1,
-		memberNameOfCorpManager(fieldChanged):  1,
+		fieldLinkID:   1,
+		fieldRole:     1,
+		fieldName:     1,
+		fieldEmail:    1,
+		fieldPassword: 1,
+		fieldChanged:  1,
 	}
 
-	var v []cCorpSigning
+	var ms []dCorpManager
+	var links []cCorpSigning
+
 	f := func(ctx context.Context) error {
-		return this.getArrayElem(
-			ctx, this.corpSigningCollection, fieldCorpManagers,
-			docFilter, elemFilter, project, &v,
+		err := this.getDocs(ctx, this.corpManagerCollection, filter, project, &ms)
+		if err != nil || len(ms) == 0 {
+			return err
+		}
+
+		linkIDs := make(bson.A, 0, len(ms))
+		for i := range ms {
+			linkIDs = append(linkIDs, ms[i].LinkID)
+		}
+
+		return this.getDocs(
+			ctx, this.corpSigningCollection,
+			bson.M{
+				fieldLinkID:     bson.M{"$in": linkIDs},
+				fieldLinkStatus: linkStatusReady,
+			},
+			bson.M{
+				fieldLinkID:      1,
+				fieldOrgIdentity: 1,
+				fieldOrgEmail:    1,
+				fieldOrgAlias:    1,
+			},
+			&links,
 		)
 	}
 
 	if err := withContext(f); err != nil {
 		return nil, newSystemError(err)
 	}
-	if len(v) == 0 {
+	if len(links) == 0 {
 		return nil, nil
 	}
 
+	docs := map[string]*cCorpSigning{}
+	for i := range links {
+		docs[links[i].LinkID] = &links[i]
+	}
+
 	result := map[string]dbmodels.CorporationManagerCheckResult{}
-	for i := range v {
-		doc := &v[i]
-		cm := doc.Managers
-		if len(cm) == 0 {
+	for i := range ms {
+		item := &ms[i]
+
+		doc, ok := docs[item.LinkID]
+		if !ok {
+			continue
+		}
+		if _, ok := result[item.LinkID]; ok {
 			continue
 		}
-
-		item := &cm[0]
 
 		email, err := this.encrypt.decryptStr(item.Email)
 		if err != nil {
@@ -156,13 +172,11 @@ func (this *client) ResetCorporationManagerPassword(linkID, email string, opt db
 	}
 	elemFilter[fieldPassword] = opt.OldPassword
 
-	docFilter := docFilterOfCorpManager(linkID)
-	arrayFilterByElemMatch(fieldCorpManagers, true, elemFilter, docFilter)
-
 	f := func(ctx context.Context) dbmodels.IDBError {
-		return this.updateArrayElem(
-			ctx, this.corpSigningCollection, fieldCorpManagers,
-			docFilter, elemFilter, updateCmd)
+		return this.updateDoc(
+			ctx, this.corpManagerCollection,
+			docFilterOfCorpManagers(linkID, elemFilter), updateCmd,
+		)
 	}
 
 	return withContext1(f)
@@ -175,31 +189,34 @@ func (this *client) ListCorporationManager(linkID, email, role string) ([]dbmode
 	}
 
 	project := bson.M{
-		memberNameOfCorpManager(fieldID):    1,
-		memberNameOfCorpManager(fieldName):  1,
-		memberNameOfCorpManager(fieldEmail): 1,
-		memberNameOfCorpManager(fieldRole):  1,
+		fieldID:    1,
+		fieldName:  1,
+		fieldEmail: 1,
+		fieldRole:  1,
 	}
 
-	var v []cCorpSigning
+	var ms []dCorpManager
 
-	f := func(ctx context.Context) error {
-		return this.getArrayElem(
-			ctx, this.corpSigningCollection, fieldCorpManagers,
-			docFilterOfCorpManager(linkID), elemFilter, project, &v,
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.getDocs(
+			ctx, this.corpManagerCollection,
+			docFilterOfCorpManagers(linkID, elemFilter), project, &ms,
 		)
-	}
+		if err != nil {
+			return newSystemError(err)
+		}
 
-	if err := withContext(f); err != nil {
-		return nil, newSystemError(err)
+		if len(ms) == 0 {
+			return this.checkLinkOfRecords(ctx, this.corpSigningCollection, linkID)
+		}
+		return nil
 	}
 
-	if len(v) == 0 {
-		return nil, errNoDBRecord
+	if err := withContext1(f); err != nil {
+		return nil, err
 	}
 
-	ms := v[0].Managers
-	if ms == nil {
+	if len(ms) == 0 {
 		return nil, nil
 	}
 
@@ -228,33 +245,31 @@ func (this *client) GetCorporationManager(linkID, email string) (*dbmodels.Corpo
 		return nil, err
 	}
 
-	project := bson.M{
-		memberNameOfCorpManager(fieldPassword): 1,
-	}
-
-	var v []cCorpSigning
+	var m dCorpManager
+	exists := true
 
-	f := func(ctx context.Context) error {
-		return this.getArrayElem(
-			ctx, this.corpSigningCollection, fieldCorpManagers,
-			docFilterOfCorpManager(linkID), elemFilter, project, &v,
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.getDoc(
+			ctx, this.corpManagerCollection,
+			docFilterOfCorpManagers(linkID, elemFilter),
+			bson.M{fieldPassword: 1}, &m,
 		)
-	}
+		if err == nil || !err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+			return err
+		}
 
-	if err := withContext(f); err != nil {
-		return nil, newSystemError(err)
+		exists = false
+		return this.checkLinkOfRecords(ctx, this.corpSigningCollection, linkID)
 	}
 
-	if len(v) == 0 {
-		return nil, errNoDBRecord
+	if err := withContext1(f); err != nil {
+		return nil, err
 	}
 
-	ms := v[0].Managers
-	if ms == nil {
+	if !exists {
 		return nil, nil
 	}
 
-	m := v[0].Managers[0]
 	return &dbmodels.CorporationManagerCheckResult{
 		Password: m.Password,
 	}, nil
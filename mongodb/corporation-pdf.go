@@ -1,83 +1,137 @@
 package mongodb
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io/ioutil"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/opensourceways/app-cla-server/dbmodels"
 )
 
-func docFilterOfCorpSigningPDF(linkID string, email string) bson.M {
-	return bson.M{
-		fieldLinkID: linkID,
-		fieldCorpID: genCorpID(email),
-	}
+var _ dbmodels.IFile = (*client)(nil)
+
+// The corporation signing pdfs are saved in the GridFS bucket whose name is corpPDFCollection.
+// Each pdf is a file named as 'link_id/corp_id' and is encrypted with a random nonce.
+func nameOfCorpSigningPDF(linkID, email string) string {
+	return fmt.Sprintf("%s/%s", linkID, genCorpID(email))
 }
 
-func (this *client) UploadCorporationSigningPDF(linkID string, adminEmail string, pdf *[]byte) dbmodels.IDBError {
-	docFilter := docFilterOfCorpSigningPDF(linkID, adminEmail)
+func (this *client) corpPDFBucket() (*gridfs.Bucket, error) {
+	return gridfs.NewBucket(
+		this.db, options.GridFSBucket().SetName(this.corpPDFCollection),
+	)
+}
 
-	doc := bson.M{fieldPDF: *pdf}
-	for k, v := range docFilter {
-		doc[k] = v
-	}
+func (this *client) corpPDFFilesCollection() string {
+	return this.corpPDFCollection + ".files"
+}
 
-	f := func(ctx context.Context) dbmodels.IDBError {
-		_, err := this.replaceDoc(ctx, this.corpPDFCollection, docFilter, doc)
-		return err
+func (this *client) UploadCorporationSigningPDF(linkID, adminEmail string, pdf []byte) dbmodels.IDBError {
+	data, err1 := this.encrypt.encryptBytes(pdf)
+	if err1 != nil {
+		return err1
 	}
 
-	return withContext1(f)
-}
+	bucket, err := this.corpPDFBucket()
+	if err != nil {
+		return newSystemError(err)
+	}
 
-func (this *client) DownloadCorporationSigningPDF(linkID string, email string) (*[]byte, dbmodels.IDBError) {
-	var v dCorpSigningPDF
+	name := nameOfCorpSigningPDF(linkID, adminEmail)
 
-	f := func(ctx context.Context) dbmodels.IDBError {
-		return this.getDoc(
-			ctx, this.corpPDFCollection,
-			docFilterOfCorpSigningPDF(linkID, email), bson.M{fieldPDF: 1}, &v,
+	// GridFS can't replace a file, so upload the new one and then remove the old ones.
+	var old []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	err = withContext(func(ctx context.Context) error {
+		return this.getDocs(
+			ctx, this.corpPDFFilesCollection(), bson.M{"filename": name}, bson.M{"_id": 1}, &old,
 		)
+	})
+	if err != nil {
+		return newSystemError(err)
 	}
 
-	if err := withContext1(f); err != nil {
-		return nil, err
+	opt := options.GridFSUpload().SetMetadata(dCorpSigningPDF{
+		LinkID: linkID,
+		CorpID: genCorpID(adminEmail),
+	})
+
+	bucket.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err = bucket.UploadFromStream(name, bytes.NewReader(data), opt); err != nil {
+		return newSystemError(err)
 	}
 
-	return &v.PDF, nil
+	for i := range old {
+		if err := bucket.Delete(old[i].ID); err != nil && err != gridfs.ErrFileNotFound {
+			return newSystemError(err)
+		}
+	}
+
+	return nil
 }
 
-func (this *client) IsCorpSigningPDFUploaded(linkID string, email string) (bool, dbmodels.IDBError) {
-	var v dCorpSigningPDF
+func (this *client) DownloadCorporationSigningPDF(linkID, email, path string) dbmodels.IDBError {
+	bucket, err := this.corpPDFBucket()
+	if err != nil {
+		return newSystemError(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	bucket.SetReadDeadline(time.Now().Add(10 * time.Second))
+	if _, err = bucket.DownloadToStreamByName(nameOfCorpSigningPDF(linkID, email), buf); err != nil {
+		if err == gridfs.ErrFileNotFound {
+			return errNoDBRecord
+		}
+		return newSystemError(err)
+	}
+
+	pdf, err1 := this.encrypt.decryptBytes(buf.Bytes())
+	if err1 != nil {
+		return err1
+	}
+
+	if err := ioutil.WriteFile(path, pdf, 0644); err != nil {
+		return newSystemError(err)
+	}
+	return nil
+}
 
-	f := func(ctx context.Context) dbmodels.IDBError {
-		return this.getDoc(
-			ctx, this.corpPDFCollection,
-			docFilterOfCorpSigningPDF(linkID, email), bson.M{"_id": 1}, &v,
+func (this *client) IsCorporationSigningPDFUploaded(linkID, email string) (bool, dbmodels.IDBError) {
+	var n int64
+	f := func(ctx context.Context) error {
+		v, err := this.collection(this.corpPDFFilesCollection()).CountDocuments(
+			ctx, bson.M{"filename": nameOfCorpSigningPDF(linkID, email)},
 		)
+		n = v
+		return err
 	}
 
-	if err := withContext1(f); err != nil {
-		if err.IsErrorOf(dbmodels.ErrNoDBRecord) {
-			return false, nil
-		}
-		return false, err
+	if err := withContext(f); err != nil {
+		return false, newSystemError(err)
 	}
 
-	return true, nil
+	return n > 0, nil
 }
 
-func (this *client) ListCorpsWithPDFUploaded(linkID string) ([]string, dbmodels.IDBError) {
+func (this *client) ListCorporationsWithPDFUploaded(linkID string) ([]string, dbmodels.IDBError) {
 	var v []struct {
-		CorpID string `bson:"corp_id"`
+		Metadata dCorpSigningPDF `bson:"metadata"`
 	}
 
 	f := func(ctx context.Context) error {
 		return this.getDocs(
-			ctx, this.corpPDFCollection,
-			bson.M{fieldLinkID: linkID},
-			bson.M{fieldCorpID: 1}, &v,
+			ctx, this.corpPDFFilesCollection(),
+			bson.M{"metadata." + fieldLinkID: linkID},
+			bson.M{"metadata": 1}, &v,
 		)
 	}
 
@@ -85,9 +139,41 @@ func (this *client) ListCorpsWithPDFUploaded(linkID string) ([]string, dbmodels.
 		return nil, newSystemError(err)
 	}
 
+	m := map[string]bool{}
 	result := make([]string, 0, len(v))
 	for i := range v {
-		result = append(result, v[i].CorpID)
+		if corpID := v[i].Metadata.CorpID; !m[corpID] {
+			m[corpID] = true
+			result = append(result, corpID)
+		}
 	}
 	return result, nil
 }
+
+func (this *client) DeleteCorporationSigningPDF(linkID, email string) dbmodels.IDBError {
+	bucket, err := this.corpPDFBucket()
+	if err != nil {
+		return newSystemError(err)
+	}
+
+	var v []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	err = withContext(func(ctx context.Context) error {
+		return this.getDocs(
+			ctx, this.corpPDFFilesCollection(),
+			bson.M{"filename": nameOfCorpSigningPDF(linkID, email)},
+			bson.M{"_id": 1}, &v,
+		)
+	})
+	if err != nil {
+		return newSystemError(err)
+	}
+
+	for i := range v {
+		if err := bucket.Delete(v[i].ID); err != nil && err != gridfs.ErrFileNotFound {
+			return newSystemError(err)
+		}
+	}
+	return nil
+}
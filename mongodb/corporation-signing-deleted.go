@@ -3,94 +3,171 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/opensourceways/app-cla-server/dbmodels"
 )
 
-func (this *client) getCorpSigning(linkID, email string) (*dCorpSigning, dbmodels.IDBError) {
-	var v []cCorpSigning
-
-	f := func(ctx context.Context) error {
-		return this.getArrayElem(
-			ctx, this.corpSigningCollection, fieldSignings,
-			docFilterOfSigning(linkID), elemFilterOfCorpSigning(email),
-			nil, &v,
-		)
+func toCorpSigningEventUpdate(update bson.M, event *dbmodels.CorpSigningEvent) (bson.M, dbmodels.IDBError) {
+	if event == nil {
+		return update, nil
 	}
 
-	if err := withContext(f); err != nil {
-		return nil, newSystemError(err)
+	doc, err := structToMap(dCorpSigningEvent{
+		Action:   event.Action,
+		Operator: event.Operator,
+		Time:     event.Time,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if len(v) == 0 {
-		return nil, errNoDBRecord
+	update["$push"] = bson.M{fieldHistory: doc}
+	return update, nil
+}
+
+func (this *client) DeleteCorpSigning(linkID, email string, event *dbmodels.CorpSigningEvent) dbmodels.IDBError {
+	update, err := toCorpSigningEventUpdate(bson.M{"$set": bson.M{fieldDeleted: true}}, event)
+	if err != nil {
+		return err
 	}
 
-	signings := v[0].Signings
-	if len(signings) == 0 {
-		return nil, nil
+	f := func(ctx context.Context) dbmodels.IDBError {
+		r, err := this.collection(this.corpSigningRecordCollection).UpdateOne(
+			ctx, docFilterOfCorpSigning(linkID, email), update,
+		)
+		if err != nil {
+			return newSystemError(err)
+		}
+		if r.MatchedCount > 0 {
+			return nil
+		}
+
+		// It is ok if the corporation has not signed.
+		return this.checkLinkOfRecords(ctx, this.corpSigningCollection, linkID)
 	}
 
-	return &signings[0], nil
+	return withContext1(f)
 }
 
-func (this *client) DeleteCorpSigning(linkID, email string) dbmodels.IDBError {
-	data, err := this.getCorpSigning(linkID, email)
+// RestoreCorpSigning restores the latest deleted signing of the corporation.
+// It returns ErrRecordExists if the corporation has signed again.
+func (this *client) RestoreCorpSigning(linkID, email string, event *dbmodels.CorpSigningEvent) dbmodels.IDBError {
+	update, err := toCorpSigningEventUpdate(bson.M{"$set": bson.M{fieldDeleted: false}}, event)
 	if err != nil {
 		return err
 	}
-	if data == nil {
-		return nil
-	}
 
-	doc, err := structToMap(data)
-	if err != nil {
-		return err
+	filter := docFilterOfSigningRecords(linkID, true)
+	for k, v := range elemFilterOfCorpSigning(email) {
+		filter[k] = v
 	}
-	doc[fieldInfo] = data.SigningInfo
 
 	f := func(ctx context.Context) dbmodels.IDBError {
-		return this.moveArrayElem(
-			ctx, this.corpSigningCollection, fieldSignings, fieldDeleted,
-			docFilterOfSigning(linkID), elemFilterOfCorpSigning(email), doc,
-		)
+		col := this.collection(this.corpSigningRecordCollection)
+
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		// The signings of a corporation are deleted in the order of being signed,
+		// so the latest deleted one is the one with the biggest id.
+		err := col.FindOne(
+			ctx, filter,
+			options.FindOne().SetSort(bson.M{"_id": -1}).SetProjection(bson.M{"_id": 1}),
+		).Decode(&doc)
+		if err != nil {
+			if isErrNoDocuments(err) {
+				return errNoDBRecord
+			}
+			return newSystemError(err)
+		}
+
+		r, err := col.UpdateOne(ctx, bson.M{"_id": doc.ID, fieldDeleted: true}, update)
+		if err != nil {
+			if isErrDuplicateKey(err) {
+				return newDBError(dbmodels.ErrRecordExists, err)
+			}
+			return newSystemError(err)
+		}
+
+		if r.MatchedCount == 0 {
+			return newDBError(dbmodels.ErrRecordExists, fmt.Errorf("it was restored concurrently"))
+		}
+		return nil
 	}
 
 	return withContext1(f)
 }
 
-func (this *client) ListDeletedCorpSignings(linkID string) ([]dbmodels.CorporationSigningBasicInfo, dbmodels.IDBError) {
-	key := func(k string) string {
-		return fmt.Sprintf("%s.%s", fieldDeleted, k)
+func (this *client) ListCorpSigningHistory(linkID, email string) ([]dbmodels.CorpSigningEvent, dbmodels.IDBError) {
+	filter := docFilterOfSigning(linkID)
+	for k, v := range elemFilterOfCorpSigning(email) {
+		filter[k] = v
 	}
 
-	project := bson.M{
-		key(fieldEmail): 1,
-		key(fieldName):  1,
-		key(fieldCorp):  1,
-		key(fieldDate):  1,
-		key(fieldLang):  1,
+	var docs []dCorpSigning
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.getDocs(
+			ctx, this.corpSigningRecordCollection, filter, bson.M{fieldHistory: 1}, &docs,
+		)
+		if err != nil {
+			return newSystemError(err)
+		}
+
+		if len(docs) == 0 {
+			return this.checkLinkOfRecords(ctx, this.corpSigningCollection, linkID)
+		}
+		return nil
 	}
 
-	var v []cCorpSigning
-	f := func(ctx context.Context) error {
-		return this.getArrayElem(
-			ctx, this.corpSigningCollection, fieldDeleted,
-			docFilterOfSigning(linkID), nil, project, &v,
-		)
+	if err := withContext1(f); err != nil {
+		return nil, err
 	}
 
-	if err := withContext(f); err != nil {
-		return nil, newSystemError(err)
+	var r []dbmodels.CorpSigningEvent
+	for i := range docs {
+		for _, item := range docs[i].History {
+			r = append(r, dbmodels.CorpSigningEvent{
+				Action:   item.Action,
+				Operator: item.Operator,
+				Time:     item.Time,
+			})
+		}
+	}
+
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].Time < r[j].Time
+	})
+
+	return r, nil
+}
+
+func (this *client) ListDeletedCorpSignings(linkID string) ([]dbmodels.CorporationSigningBasicInfo, dbmodels.IDBError) {
+	var deleted []dCorpSigning
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.getDocs(
+			ctx, this.corpSigningRecordCollection,
+			docFilterOfSigningRecords(linkID, true),
+			projectOfCorpSigning(), &deleted,
+		)
+		if err != nil {
+			return newSystemError(err)
+		}
+
+		if len(deleted) == 0 {
+			return this.checkLinkOfRecords(ctx, this.corpSigningCollection, linkID)
+		}
+		return nil
 	}
 
-	if len(v) == 0 {
-		return nil, errNoDBRecord
+	if err := withContext1(f); err != nil {
+		return nil, err
 	}
 
-	deleted := v[0].Deleted
 	n := len(deleted)
 	if n == 0 {
 		return nil, nil
@@ -2,7 +2,6 @@ package mongodb
 
 import (
 	"context"
-	"fmt"
 
 	"go.mongodb.org/mongo-driver/bson"
 
@@ -13,77 +12,132 @@ func elemFilterOfCorpSigning(email string) bson.M {
 	return filterOfCorpID(email)
 }
 
-func (c *client) SignCorpCLA(linkID string, info *dbmodels.CorpSigningCreateOpt) dbmodels.IDBError {
+func docFilterOfCorpSigning(linkID, email string) bson.M {
+	filter := docFilterOfSigningRecords(linkID, false)
+	for k, v := range elemFilterOfCorpSigning(email) {
+		filter[k] = v
+	}
+	return filter
+}
+
+func (c *client) toDocOfCorpSigning(info *dbmodels.CorpSigningCreateOpt) (bson.M, dbmodels.IDBError) {
 	email, err := c.encrypt.encryptStr(info.AdminEmail)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	si, err := c.encrypt.encryptSigningInfo(&info.Info)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	signing := dCorpSigning{
 		CLALanguage: info.CLALanguage,
+		CLAHash:     info.CLAHash,
 		CorpID:      genCorpID(info.AdminEmail),
 		CorpName:    info.CorporationName,
 		AdminEmail:  email,
+		EmailIndex:  c.emailIndex.of(info.AdminEmail),
 		AdminName:   info.AdminName,
 		Date:        info.Date,
+		ESigned:     info.ESigned,
+		AgreementID: info.AgreementID,
 	}
 	doc, err := structToMap(signing)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	doc[fieldInfo] = si
+	return doc, nil
+}
 
-	docFilter := docFilterOfSigning(linkID)
-	arrayFilterByElemMatch(fieldSignings, false, elemFilterOfCorpSigning(info.AdminEmail), docFilter)
+func (c *client) SignCorpCLA(linkID string, info *dbmodels.CorpSigningCreateOpt) dbmodels.IDBError {
+	doc, err := c.toDocOfCorpSigning(info)
+	if err != nil {
+		return err
+	}
+	doc[fieldDeleted] = false
 
 	f := func(ctx context.Context) dbmodels.IDBError {
-		return c.pushArrayElem(ctx, c.corpSigningCollection, fieldSignings, docFilter, doc)
+		return c.insertSigningRecord(
+			ctx, c.corpSigningCollection, c.corpSigningRecordCollection, linkID, doc,
+		)
 	}
 
 	return withContext1(f)
 }
 
-func (this *client) ListCorpSignings(linkID, language string) ([]dbmodels.CorporationSigningSummary, dbmodels.IDBError) {
-	elemFilter := map[string]bson.M{
-		fieldCorpManagers: {"role": dbmodels.RoleAdmin},
+// ResignCorpCLA updates the signing in place, so that its history is kept.
+// Only the admin who signed the older version can re-sign.
+func (c *client) ResignCorpCLA(linkID string, info *dbmodels.CorpSigningCreateOpt) dbmodels.IDBError {
+	doc, err := c.toDocOfCorpSigning(info)
+	if err != nil {
+		return err
 	}
+
+	filter := docFilterOfCorpSigning(linkID, info.AdminEmail)
+	filter[fieldCLAHash] = bson.M{"$ne": info.CLAHash}
+	filter[fieldEmailIndex] = c.emailIndex.of(info.AdminEmail)
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return c.updateDoc(ctx, c.corpSigningRecordCollection, filter, doc)
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) ListCorpSignings(linkID, language string) ([]dbmodels.CorporationSigningSummary, dbmodels.IDBError) {
+	filter := docFilterOfSigningRecords(linkID, false)
 	if language != "" {
-		elemFilter[fieldSignings] = bson.M{fieldLang: language}
+		filter[fieldLang] = language
 	}
 
-	project := projectOfCorpSigning()
-	project[memberNameOfCorpManager(fieldEmail)] = 1
+	managerFilter := docFilterOfCorpManager(linkID)
+	managerFilter[fieldRole] = dbmodels.RoleAdmin
 
-	var v []cCorpSigning
-	f := func(ctx context.Context) error {
-		return this.getMultiArrays(
-			ctx, this.corpSigningCollection, docFilterOfSigning(linkID),
-			elemFilter, project, &v,
+	var signings []dCorpSigning
+	var managers []dCorpManager
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.getDocs(
+			ctx, this.corpSigningRecordCollection, filter,
+			projectOfCorpSigning(), &signings,
 		)
-	}
+		if err != nil {
+			return newSystemError(err)
+		}
 
-	if err := withContext(f); err != nil {
-		return nil, newSystemError(err)
+		if len(signings) == 0 {
+			return this.checkLinkOfRecords(ctx, this.corpSigningCollection, linkID)
+		}
+
+		err = this.getDocs(
+			ctx, this.corpManagerCollection, managerFilter,
+			bson.M{fieldEmail: 1}, &managers,
+		)
+		if err != nil {
+			return newSystemError(err)
+		}
+		return nil
 	}
 
-	if len(v) == 0 {
-		return nil, errNoDBRecord
+	if err := withContext1(f); err != nil {
+		return nil, err
 	}
 
-	signings := v[0].Signings
 	n := len(signings)
 	if n == 0 {
 		return nil, nil
 	}
 
+	// compare the plaintexts, because the emails are encrypted with random nonces.
 	admins := map[string]bool{}
-	for _, item := range v[0].Managers {
-		admins[item.Email] = true
+	for _, item := range managers {
+		email, err := this.encrypt.decryptStr(item.Email)
+		if err != nil {
+			return nil, err
+		}
+		admins[email] = true
 	}
 
 	r := make([]dbmodels.CorporationSigningSummary, 0, n)
@@ -95,7 +149,7 @@ func (this *client) ListCorpSignings(linkID, language string) ([]dbmodels.Corpor
 
 		r = append(r, dbmodels.CorporationSigningSummary{
 			CorporationSigningBasicInfo: *bi,
-			AdminAdded:                  admins[signings[i].AdminEmail],
+			AdminAdded:                  admins[bi.AdminEmail],
 		})
 	}
 
@@ -105,14 +159,13 @@ func (this *client) ListCorpSignings(linkID, language string) ([]dbmodels.Corpor
 func (this *client) IsCorpSigned(linkID, email string) (bool, dbmodels.IDBError) {
 	signed := false
 	f := func(ctx context.Context) dbmodels.IDBError {
-		v, err := this.isArrayElemNotExists(
-			ctx, this.corpSigningCollection, fieldSignings,
-			docFilterOfSigning(linkID), elemFilterOfCorpSigning(email),
+		n, err := this.countDocs(
+			ctx, this.corpSigningRecordCollection, docFilterOfCorpSigning(linkID, email),
 		)
 		if err != nil {
-			return newSystemError(err)
+			return err
 		}
-		signed = !v
+		signed = n > 0
 		return nil
 	}
 
@@ -120,62 +173,55 @@ func (this *client) IsCorpSigned(linkID, email string) (bool, dbmodels.IDBError)
 	return signed, err
 }
 
-func (this *client) GetCorpSigningBasicInfo(linkID, email string) (*dbmodels.CorporationSigningBasicInfo, dbmodels.IDBError) {
-	var v []cCorpSigning
+// getCorpSigning returns nil if the corporation has not signed.
+func (this *client) getCorpSigning(linkID, email string, project bson.M) (*dCorpSigning, dbmodels.IDBError) {
+	var v dCorpSigning
 
-	f := func(ctx context.Context) error {
-		return this.getArrayElem(
-			ctx, this.corpSigningCollection, fieldSignings,
-			docFilterOfSigning(linkID), elemFilterOfCorpSigning(email),
-			projectOfCorpSigning(), &v,
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.getDoc(
+			ctx, this.corpSigningRecordCollection,
+			docFilterOfCorpSigning(linkID, email), project, &v,
 		)
 	}
 
-	if err := withContext(f); err != nil {
-		return nil, newSystemError(err)
+	err := withContext1(f)
+	if err == nil {
+		return &v, nil
 	}
 
-	if len(v) == 0 {
-		return nil, errNoDBRecord
+	if !err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		return nil, err
 	}
 
-	signings := v[0].Signings
-	if len(signings) == 0 {
-		return nil, nil
+	f = func(ctx context.Context) dbmodels.IDBError {
+		return this.checkLinkOfRecords(ctx, this.corpSigningCollection, linkID)
 	}
+	return nil, withContext1(f)
+}
 
-	return this.toDBModelCorporationSigningBasicInfo(&(signings[0]))
+func (this *client) GetCorpSigningBasicInfo(linkID, email string) (*dbmodels.CorporationSigningBasicInfo, dbmodels.IDBError) {
+	signing, err := this.getCorpSigning(linkID, email, projectOfCorpSigning())
+	if err != nil || signing == nil {
+		return nil, err
+	}
+
+	return this.toDBModelCorporationSigningBasicInfo(signing)
 }
 
 func (this *client) GetCorpSigningDetail(linkID, email string) ([]dbmodels.Field, *dbmodels.CorpSigningCreateOpt, dbmodels.IDBError) {
-	pipeline := bson.A{
-		bson.M{"$match": docFilterOfSigning(linkID)},
-		bson.M{"$project": bson.M{
-			fieldCLAInfos: 1,
-			fieldSignings: arrayElemFilter(fieldSignings, filterOfCorpID(email)),
-		}},
-		bson.M{"$unwind": "$" + fieldSignings},
-		bson.M{"$project": bson.M{
-			fieldSignings: 1,
-			fieldCLAInfos: arrayElemFilter(
-				fieldCLAInfos,
-				bson.M{fieldLang: fmt.Sprintf("$%s.%s", fieldSignings, fieldLang)},
-			),
-		}},
-	}
-
-	var v []struct {
-		CLAInfos []DCLAInfo   `bson:"cla_infos"`
-		Signings dCorpSigning `bson:"signings"`
+	signing, err := this.getCorpSigning(linkID, email, nil)
+	if err != nil || signing == nil {
+		return nil, nil, err
 	}
-	f := func(ctx context.Context) error {
-		col := this.collection(this.corpSigningCollection)
-		cursor, err := col.Aggregate(ctx, pipeline)
-		if err != nil {
-			return err
-		}
 
-		return cursor.All(ctx, &v)
+	var v []cCorpSigning
+	f := func(ctx context.Context) error {
+		return this.getArrayElem(
+			ctx, this.corpSigningCollection, fieldCLAInfos,
+			docFilterOfSigning(linkID),
+			elemFilterOfCLAInfo(signing.CLALanguage, signing.CLAHash),
+			bson.M{fieldCLAInfos: 1}, &v,
+		)
 	}
 
 	if err := withContext(f); err != nil {
@@ -186,11 +232,6 @@ func (this *client) GetCorpSigningDetail(linkID, email string) ([]dbmodels.Field
 		return nil, nil, errNoDBRecord
 	}
 
-	signing := &(v[0].Signings)
-	if signing.CLALanguage == "" {
-		return nil, nil, nil
-	}
-
 	clas := v[0].CLAInfos
 	if len(clas) == 0 {
 		return nil, nil, nil
@@ -221,19 +262,77 @@ func (c *client) toDBModelCorporationSigningBasicInfo(cs *dCorpSigning) (*dbmode
 
 	return &dbmodels.CorporationSigningBasicInfo{
 		CLALanguage:     cs.CLALanguage,
+		CLAHash:         cs.CLAHash,
 		AdminEmail:      email,
 		AdminName:       cs.AdminName,
 		CorporationName: cs.CorpName,
 		Date:            cs.Date,
+		ESigned:         cs.ESigned,
+		AgreementID:     cs.AgreementID,
 	}, nil
 }
 
 func projectOfCorpSigning() bson.M {
 	return bson.M{
-		memberNameOfSignings(fieldEmail): 1,
-		memberNameOfSignings(fieldName):  1,
-		memberNameOfSignings(fieldCorp):  1,
-		memberNameOfSignings(fieldDate):  1,
-		memberNameOfSignings(fieldLang):  1,
+		fieldEmail:       1,
+		fieldName:        1,
+		fieldCorp:        1,
+		fieldDate:        1,
+		fieldLang:        1,
+		fieldCLAHash:     1,
+		fieldESigned:     1,
+		fieldAgreementID: 1,
+	}
+}
+
+func (this *client) UpdateCorpSigningESigned(linkID, email string, esigned bool) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.updateDoc(
+			ctx, this.corpSigningRecordCollection,
+			docFilterOfCorpSigning(linkID, email), bson.M{fieldESigned: esigned},
+		)
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) GetCorpAgreement(agreementID string) (*dbmodels.CorpAgreement, dbmodels.IDBError) {
+	var v struct {
+		dCorpSigning `bson:",inline"`
+
+		LinkID     string `bson:"link_id"`
+		LinkStatus string `bson:"link_status"`
+		Deleted    bool   `bson:"deleted"`
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.getDoc(
+			ctx, this.corpSigningRecordCollection,
+			bson.M{fieldAgreementID: agreementID},
+			bson.M{
+				fieldLinkID:      1,
+				fieldLinkStatus:  1,
+				fieldDeleted:     1,
+				fieldCorp:        1,
+				fieldDate:        1,
+				fieldLang:        1,
+				fieldCLAHash:     1,
+				fieldAgreementID: 1,
+			}, &v,
+		)
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
 	}
+
+	return &dbmodels.CorpAgreement{
+		LinkID:          v.LinkID,
+		AgreementID:     v.AgreementID,
+		CLALanguage:     v.CLALanguage,
+		CLAHash:         v.CLAHash,
+		CorporationName: v.CorpName,
+		Date:            v.Date,
+		Deleted:         v.Deleted || v.LinkStatus != linkStatusReady,
+	}, nil
 }
@@ -0,0 +1,56 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func testCorpSigning(email, claHash string) *dbmodels.CorpSigningCreateOpt {
+	return &dbmodels.CorpSigningCreateOpt{
+		CorporationSigningBasicInfo: dbmodels.CorporationSigningBasicInfo{
+			CLALanguage:     "english",
+			CLAHash:         claHash,
+			AdminEmail:      email,
+			AdminName:       "admin",
+			CorporationName: "example",
+			Date:            "2021-01-01",
+			AgreementID:     claHash,
+		},
+		Info: dbmodels.TypeSigningInfo{},
+	}
+}
+
+func TestResignCorpCLAOnlyByAdmin(t *testing.T) {
+	cli, clean := newTestClient(t)
+	defer clean()
+
+	linkID := "link1"
+
+	org := &dbmodels.OrgInfo{OrgRepo: dbmodels.OrgRepo{Platform: "gitee", OrgID: "org"}}
+	if err := cli.InitializeCorpSigning(linkID, org, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cli.SignCorpCLA(linkID, testCorpSigning("admin@example.com", "v1")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the other one of the same corporation can't take over the signing.
+	err := cli.ResignCorpCLA(linkID, testCorpSigning("other@example.com", "v2"))
+	if err == nil || !err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+		t.Fatalf("expect the re-signing by the other one to fail, got %v", err)
+	}
+
+	if err := cli.ResignCorpCLA(linkID, testCorpSigning("admin@example.com", "v2")); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := cli.GetCorpSigningBasicInfo(linkID, "admin@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v == nil || v.CLAHash != "v2" || v.AdminEmail != "admin@example.com" {
+		t.Fatalf("unexpected signing after re-signing: %+v", v)
+	}
+}
@@ -226,3 +226,29 @@ func (this *client) updateDoc(ctx context.Context, collection string, filterOfDo
 	}
 	return nil
 }
+
+func (this *client) updateDocs(ctx context.Context, collection string, filterOfDoc, update bson.M) dbmodels.IDBError {
+	col := this.collection(collection)
+	if _, err := col.UpdateMany(ctx, filterOfDoc, bson.M{"$set": update}); err != nil {
+		return newSystemError(err)
+	}
+	return nil
+}
+
+func (this *client) deleteDocs(ctx context.Context, collection string, filterOfDoc bson.M) (int64, dbmodels.IDBError) {
+	col := this.collection(collection)
+	r, err := col.DeleteMany(ctx, filterOfDoc)
+	if err != nil {
+		return 0, newSystemError(err)
+	}
+	return r.DeletedCount, nil
+}
+
+func (this *client) countDocs(ctx context.Context, collection string, filterOfDoc bson.M) (int64, dbmodels.IDBError) {
+	col := this.collection(collection)
+	n, err := col.CountDocuments(ctx, filterOfDoc)
+	if err != nil {
+		return 0, newSystemError(err)
+	}
+	return n, nil
+}
@@ -19,12 +19,13 @@ func (this *client) AddEmployeeManager(linkID string, opt []dbmodels.Corporation
 		}
 
 		info := dCorpManager{
-			ID:       item.ID,
-			Name:     item.Name,
-			Email:    email,
-			Role:     item.Role,
-			Password: item.Password,
-			CorpID:   genCorpID(item.Email),
+			ID:         item.ID,
+			Name:       item.Name,
+			Email:      email,
+			EmailIndex: this.emailIndex.of(item.Email),
+			Role:       item.Role,
+			Password:   item.Password,
+			CorpID:     genCorpID(item.Email),
 		}
 
 		body, err := structToMap(info)
@@ -32,54 +33,66 @@ func (this *client) AddEmployeeManager(linkID string, opt []dbmodels.Corporation
 			return err
 		}
 
-		toAdd = append(toAdd, body)
+		toAdd = append(toAdd, toDocOfSigningRecord(linkID, body))
 	}
 
 	f := func(ctx context.Context) dbmodels.IDBError {
-		return this.pushArrayElems(
-			ctx, this.corpSigningCollection, fieldCorpManagers,
-			docFilterOfCorpManager(linkID), toAdd,
-		)
+		err := this.checkLinkOfRecords(ctx, this.corpSigningCollection, linkID)
+		if err != nil {
+			return err
+		}
+
+		if err := this.insertDocs(ctx, this.corpManagerCollection, toAdd); err != nil {
+			return newSystemError(err)
+		}
+		return nil
 	}
 
 	return withContext1(f)
 }
 
 func (this *client) DeleteEmployeeManager(linkID string, emails []string) ([]dbmodels.CorporationManagerCreateOption, dbmodels.IDBError) {
-	encryptedEmails := make([]string, 0, len(emails))
-	m := map[string]string{}
+	indexes := make([]string, 0, len(emails))
 	for _, item := range emails {
-		email, err := this.encrypt.encryptStr(item)
-		if err != nil {
-			return nil, err
-		}
-		encryptedEmails = append(encryptedEmails, email)
-		m[email] = item
+		indexes = append(indexes, this.emailIndex.of(item))
 	}
 
-	elemFilter := bson.M{
-		fieldCorpID: genCorpID(emails[0]),
-		fieldEmail:  bson.M{"$in": encryptedEmails},
-	}
+	filter := docFilterOfCorpManagers(linkID, bson.M{
+		fieldCorpID:     genCorpID(emails[0]),
+		fieldEmailIndex: bson.M{"$in": indexes},
+	})
 
-	var v cCorpSigning
+	var ms []dCorpManager
 	f := func(ctx context.Context) dbmodels.IDBError {
-		return this.pullAndReturnArrayElem(
-			ctx, this.corpSigningCollection, fieldCorpManagers,
-			docFilterOfCorpManager(linkID), elemFilter,
-			&v,
+		err := this.getDocs(
+			ctx, this.corpManagerCollection, filter,
+			bson.M{fieldEmail: 1, fieldName: 1}, &ms,
 		)
+		if err != nil {
+			return newSystemError(err)
+		}
+
+		if len(ms) == 0 {
+			return this.checkLinkOfRecords(ctx, this.corpSigningCollection, linkID)
+		}
+
+		_, err1 := this.deleteDocs(ctx, this.corpManagerCollection, filter)
+		return err1
 	}
 
 	if err := withContext1(f); err != nil {
 		return nil, err
 	}
 
-	ms := v.Managers
 	deleted := make([]dbmodels.CorporationManagerCreateOption, 0, len(ms))
 	for _, item := range ms {
+		email, err := this.encrypt.decryptStr(item.Email)
+		if err != nil {
+			return nil, err
+		}
+
 		deleted = append(deleted, dbmodels.CorporationManagerCreateOption{
-			Email: m[item.Email],
+			Email: email,
 			Name:  item.Name,
 		})
 	}
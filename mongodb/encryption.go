@@ -1,68 +1,164 @@
 package mongodb
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"strings"
 
+	"github.com/opensourceways/app-cla-server/config"
 	"github.com/opensourceways/app-cla-server/dbmodels"
 	"github.com/opensourceways/app-cla-server/util"
 )
 
-func newEncryption(key, nonce string) (encryption, error) {
-	e := encryption{}
+// The ciphertext is prefixed with the id of key which encrypts it, in the format of
+// 'id$hex' for string and '$id$bytes' for bytes. The ciphertext of the key whose id
+// is empty has no prefix, because it was saved before the keys could be rotated.
+const keyIDSeparator = "$"
+
+// newEncryption creates the encryption with the keys. It encrypts the data with
+// a random nonce each time, and it can decrypt the data encrypted with a fixed nonce too,
+// because the nonce is saved at the head of ciphertext.
+func newEncryption(keys []config.EncryptionKey, keyID string) (encryption, error) {
+	e := encryption{
+		keyID: keyID,
+		ses:   make(map[string]util.SymmetricEncryption, len(keys)),
+	}
 
-	se, err := util.NewSymmetricEncryption(key, nonce)
-	if err != nil {
-		return e, err
+	for i := range keys {
+		item := &keys[i]
+
+		se, err := util.NewSymmetricEncryption(item.Key, "")
+		if err != nil {
+			return e, err
+		}
+		e.ses[item.ID] = se
+	}
+
+	if _, ok := e.ses[keyID]; !ok {
+		return e, fmt.Errorf("no encryption key:%s", keyID)
 	}
 
-	e.se = se
 	return e, nil
 }
 
 type encryption struct {
-	se util.SymmetricEncryption
+	keyID string
+	ses   map[string]util.SymmetricEncryption
 }
 
-func (e encryption) encryptBytes(data []byte) ([]byte, dbmodels.IDBError) {
-	d, err := e.se.Encrypt(data)
+func (e encryption) encryptBytesWithKey(keyID string, data []byte) ([]byte, dbmodels.IDBError) {
+	d, err := e.ses[keyID].Encrypt(data)
 	if err != nil {
 		return nil, newSystemError(err)
 	}
 	return d, nil
 }
 
+// splitBytes returns the key id and the ciphertext without the prefix.
+func (e encryption) splitBytes(data []byte) (string, []byte) {
+	sep := []byte(keyIDSeparator)
+	if bytes.HasPrefix(data, sep) {
+		if i := bytes.Index(data[len(sep):], sep); i > 0 {
+			id := string(data[len(sep) : len(sep)+i])
+			if _, ok := e.ses[id]; ok {
+				return id, data[len(sep)+i+len(sep):]
+			}
+		}
+	}
+	return "", data
+}
+
+// splitStr returns the key id and the ciphertext without the prefix.
+func (e encryption) splitStr(data string) (string, string) {
+	if i := strings.Index(data, keyIDSeparator); i >= 0 {
+		return data[:i], data[i+len(keyIDSeparator):]
+	}
+	return "", data
+}
+
+func (e encryption) isEncryptedByCurrentKey(data []byte) bool {
+	id, _ := e.splitBytes(data)
+	return id == e.keyID
+}
+
+func (e encryption) isStrEncryptedByCurrentKey(data string) bool {
+	id, _ := e.splitStr(data)
+	return id == e.keyID
+}
+
+func (e encryption) encryptBytes(data []byte) ([]byte, dbmodels.IDBError) {
+	d, err := e.encryptBytesWithKey(e.keyID, data)
+	if err != nil || e.keyID == "" {
+		return d, err
+	}
+
+	prefix := keyIDSeparator + e.keyID + keyIDSeparator
+	return append([]byte(prefix), d...), nil
+}
+
 func (e encryption) decryptBytes(data []byte) ([]byte, dbmodels.IDBError) {
-	s, err := e.se.Decrypt(data)
+	id, d := e.splitBytes(data)
+
+	s, err := e.ses[id].Decrypt(d)
 	if err != nil {
 		return nil, newSystemError(err)
 	}
 	return s, nil
 }
 
-func (e encryption) encryptStr(data string) (string, dbmodels.IDBError) {
-	d, err := e.encryptBytes([]byte(data))
+func (e encryption) encryptStrWithKey(keyID, data string) (string, dbmodels.IDBError) {
+	d, err := e.encryptBytesWithKey(keyID, []byte(data))
 	if err != nil {
 		return "", err
 	}
 
-	return hex.EncodeToString(d), nil
+	if keyID == "" {
+		return hex.EncodeToString(d), nil
+	}
+	return keyID + keyIDSeparator + hex.EncodeToString(d), nil
+}
+
+func (e encryption) encryptStr(data string) (string, dbmodels.IDBError) {
+	return e.encryptStrWithKey(e.keyID, data)
 }
 
 func (e encryption) decryptStr(data string) (string, dbmodels.IDBError) {
-	b, err := hex.DecodeString(data)
+	id, v := e.splitStr(data)
+
+	se, ok := e.ses[id]
+	if !ok {
+		return "", newSystemError(fmt.Errorf("no encryption key:%s", id))
+	}
+
+	b, err := hex.DecodeString(v)
 	if err != nil {
 		return "", newSystemError(err)
 	}
 
-	s, err1 := e.decryptBytes(b)
-	if err1 != nil {
-		return "", err1
+	s, err := se.Decrypt(b)
+	if err != nil {
+		return "", newSystemError(err)
 	}
 
 	return string(s), nil
 }
 
+// blindIndex is the keyed hash of data which is encrypted with a random nonce,
+// so that the data can be looked up without saving it with a fixed nonce.
+type blindIndex struct {
+	key []byte
+}
+
+func (b blindIndex) of(data string) string {
+	h := hmac.New(sha256.New, b.key)
+	h.Write([]byte(data))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func (e encryption) encryptSigningInfo(data *dbmodels.TypeSigningInfo) ([]byte, dbmodels.IDBError) {
 	b, err := json.Marshal(*data)
 	if err != nil {
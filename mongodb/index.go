@@ -0,0 +1,267 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+// dIndex is the index which the client declares or the one which exists in the db.
+type dIndex struct {
+	Name    string `bson:"name"`
+	Keys    bson.D `bson:"key"`
+	Unique  bool   `bson:"unique"`
+	Partial bson.M `bson:"partialFilterExpression"`
+
+	// ExpireAfterSeconds is not nil only for the TTL index.
+	ExpireAfterSeconds *int64 `bson:"expireAfterSeconds"`
+}
+
+func keysOfIndex(fields ...string) bson.D {
+	keys := make(bson.D, 0, len(fields))
+	for _, f := range fields {
+		keys = append(keys, bson.E{Key: f, Value: 1})
+	}
+	return keys
+}
+
+func (index *dIndex) toIndexModel() mongo.IndexModel {
+	opt := options.Index().SetName(index.Name)
+	if index.Unique {
+		opt.SetUnique(true)
+	}
+	if len(index.Partial) > 0 {
+		opt.SetPartialFilterExpression(index.Partial)
+	}
+	if index.ExpireAfterSeconds != nil {
+		opt.SetExpireAfterSeconds(int32(*index.ExpireAfterSeconds))
+	}
+
+	return mongo.IndexModel{Keys: index.Keys, Options: opt}
+}
+
+func (index *dIndex) isSameAs(v *dIndex) bool {
+	if len(index.Keys) != len(v.Keys) {
+		return false
+	}
+	for i := range index.Keys {
+		a, b := &index.Keys[i], &v.Keys[i]
+		if a.Key != b.Key || fmt.Sprint(a.Value) != fmt.Sprint(b.Value) {
+			return false
+		}
+	}
+
+	if index.Unique != v.Unique {
+		return false
+	}
+
+	if (index.ExpireAfterSeconds == nil) != (v.ExpireAfterSeconds == nil) {
+		return false
+	}
+	if index.ExpireAfterSeconds != nil && *index.ExpireAfterSeconds != *v.ExpireAfterSeconds {
+		return false
+	}
+
+	return fmt.Sprint(normalizeDoc(index.Partial)) == fmt.Sprint(normalizeDoc(v.Partial))
+}
+
+// normalizeDoc converts the embedded docs to map, because the ones read from
+// db may be decoded as bson.D, and the keys of map are sorted when printing.
+func normalizeDoc(v interface{}) interface{} {
+	switch doc := v.(type) {
+	case bson.D:
+		m := make(map[string]interface{}, len(doc))
+		for _, e := range doc {
+			m[e.Key] = normalizeDoc(e.Value)
+		}
+		return m
+
+	case bson.M:
+		m := make(map[string]interface{}, len(doc))
+		for k, e := range doc {
+			m[k] = normalizeDoc(e)
+		}
+		return m
+	}
+
+	return v
+}
+
+// declaredIndexes returns the indexes of each collection that the queries rely on.
+// The buckets of GridFS are not included, because the driver creates their indexes.
+func (this *client) declaredIndexes() map[string][]dIndex {
+	ttl := int64(0)
+
+	// the records saved before the blind index of email was introduced have no email_index
+	// until they are migrated, so the unique indexes should skip them.
+	hasEmailIndex := bson.M{fieldEmailIndex: bson.M{"$exists": true}}
+
+	return map[string][]dIndex{
+		this.linkCollection: {
+			{Name: "link_id", Keys: keysOfIndex(fieldLinkID), Unique: true},
+			{
+				Name: "platform_org_repo",
+				Keys: keysOfIndex(fieldPlatform, fieldOrg, fieldRepo, fieldLinkStatus),
+			},
+		},
+		this.orgEmailCollection: {
+			{Name: "email", Keys: keysOfIndex(fieldEmail), Unique: true},
+		},
+		this.vcCollection: {
+			{Name: "email_purpose_code", Keys: keysOfIndex(fieldEmail, fieldPurpose, fieldCode)},
+			{Name: "expiry", Keys: keysOfIndex(fieldExpiry), ExpireAfterSeconds: &ttl},
+		},
+		this.corpSigningCollection: {
+			{Name: "link_id", Keys: keysOfIndex(fieldLinkID), Unique: true},
+		},
+		this.individualSigningCollection: {
+			{Name: "link_id", Keys: keysOfIndex(fieldLinkID), Unique: true},
+		},
+		this.corpSigningRecordCollection: {
+			{
+				Name:    "link_id_corp_id",
+				Keys:    keysOfIndex(fieldLinkID, fieldCorpID),
+				Unique:  true,
+				Partial: bson.M{fieldDeleted: false},
+			},
+			{Name: "link_id_deleted", Keys: keysOfIndex(fieldLinkID, fieldDeleted)},
+			{Name: "agreement_id", Keys: keysOfIndex(fieldAgreementID)},
+		},
+		this.corpManagerCollection: {
+			{
+				Name:    "link_id_email_index",
+				Keys:    keysOfIndex(fieldLinkID, fieldEmailIndex),
+				Unique:  true,
+				Partial: hasEmailIndex,
+			},
+			{
+				Name:    "link_id_corp_id_admin",
+				Keys:    keysOfIndex(fieldLinkID, fieldCorpID),
+				Unique:  true,
+				Partial: bson.M{fieldRole: dbmodels.RoleAdmin},
+			},
+			{Name: "email_index", Keys: keysOfIndex(fieldEmailIndex)},
+			{Name: "corp_id_id", Keys: keysOfIndex(fieldCorpID, fieldID)},
+		},
+		this.individualSigningRecordCollection: {
+			{
+				Name:   "link_id_email_index_undeleted",
+				Keys:   keysOfIndex(fieldLinkID, fieldEmailIndex),
+				Unique: true,
+				Partial: bson.M{
+					fieldEmailIndex: bson.M{"$exists": true},
+					fieldDeleted:    false,
+				},
+			},
+			{Name: "link_id_corp_id", Keys: keysOfIndex(fieldLinkID, fieldCorpID)},
+			{Name: "link_id_deleted", Keys: keysOfIndex(fieldLinkID, fieldDeleted)},
+			{Name: "tombstone_deleted_at", Keys: keysOfIndex(fieldTombstone + "." + fieldDeletedAt)},
+		},
+		this.auditLogCollection: {
+			{Name: "link_id_corp_id_time", Keys: keysOfIndex(fieldLinkID, fieldCorpID, fieldTime)},
+		},
+		this.resignCampaignCollection: {
+			{Name: "link_id_campaign_id", Keys: keysOfIndex(fieldLinkID, fieldCampaignID)},
+			{Name: "link_id_email_index_status", Keys: keysOfIndex(fieldLinkID, fieldEmailIndex, fieldStatus)},
+		},
+	}
+}
+
+func isErrIndexConflict(err error) bool {
+	// 85: IndexOptionsConflict, 86: IndexKeySpecsConflict, 11000: DuplicateKey
+	if v, ok := err.(mongo.CommandError); ok {
+		return v.Code == 85 || v.Code == 86 || v.Code == 11000
+	}
+	return isErrDuplicateKey(err)
+}
+
+func isErrIndexNotFound(err error) bool {
+	// 26: NamespaceNotFound, 27: IndexNotFound
+	if v, ok := err.(mongo.CommandError); ok {
+		return v.Code == 26 || v.Code == 27
+	}
+	return false
+}
+
+// ensureIndexes creates the declared indexes which do not exist. An index which
+// conflicts with the existing one or data is skipped, and CheckIndexes reports it.
+func (this *client) ensureIndexes() error {
+	for collection, indexes := range this.declaredIndexes() {
+		for i := range indexes {
+			m := indexes[i].toIndexModel()
+
+			f := func(ctx context.Context) error {
+				_, err := this.collection(collection).Indexes().CreateOne(ctx, m)
+				return err
+			}
+
+			if err := withContext(f); err != nil && !isErrIndexConflict(err) {
+				return fmt.Errorf(
+					"create index:%s of collection:%s failed, err:%s",
+					indexes[i].Name, collection, err.Error(),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+func (this *client) listIndexes(collection string) ([]dIndex, error) {
+	var v []dIndex
+
+	f := func(ctx context.Context) error {
+		cursor, err := this.collection(collection).Indexes().List(ctx)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &v)
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// CheckIndexes returns the drift between the declared indexes and the existing ones,
+// including the missing, the different and the undeclared indexes.
+func (this *client) CheckIndexes() ([]string, error) {
+	var r []string
+
+	for collection, declared := range this.declaredIndexes() {
+		existing, err := this.listIndexes(collection)
+		if err != nil {
+			return nil, err
+		}
+
+		m := map[string]*dIndex{}
+		for i := range existing {
+			m[existing[i].Name] = &existing[i]
+		}
+
+		for i := range declared {
+			item := &declared[i]
+
+			v, ok := m[item.Name]
+			if !ok {
+				r = append(r, fmt.Sprintf("collection:%s, index:%s is missing", collection, item.Name))
+			} else if !item.isSameAs(v) {
+				r = append(r, fmt.Sprintf("collection:%s, index:%s differs from the declared one", collection, item.Name))
+			}
+
+			delete(m, item.Name)
+		}
+
+		delete(m, "_id_")
+		for name := range m {
+			r = append(r, fmt.Sprintf("collection:%s, index:%s is not declared", collection, name))
+		}
+	}
+
+	return r, nil
+}
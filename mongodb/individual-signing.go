@@ -9,14 +9,9 @@ import (
 )
 
 func (c *client) elemFilterOfIndividualSigning(email string) (bson.M, dbmodels.IDBError) {
-	encryptedEmail, err := c.encrypt.encryptStr(email)
-	if err != nil {
-		return nil, err
-	}
-
 	return bson.M{
-		fieldCorpID: genCorpID(email),
-		fieldEmail:  encryptedEmail,
+		fieldCorpID:     genCorpID(email),
+		fieldEmailIndex: c.emailIndex.of(email),
 	}, nil
 }
 
@@ -27,75 +22,281 @@ func docFilterOfSigning(linkID string) bson.M {
 	}
 }
 
-func (this *client) SignIndividualCLA(linkID string, info *dbmodels.IndividualSigningInfo) dbmodels.IDBError {
+func (this *client) docFilterOfIndividualSigning(linkID, email string) (bson.M, dbmodels.IDBError) {
+	return this.docFilterOfIndividualSigningRecord(linkID, email, false)
+}
+
+func (this *client) docFilterOfIndividualSigningRecord(linkID, email string, deleted bool) (bson.M, dbmodels.IDBError) {
+	elemFilter, err := this.elemFilterOfIndividualSigning(email)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := docFilterOfSigningRecords(linkID, deleted)
+	for k, v := range elemFilter {
+		filter[k] = v
+	}
+	return filter, nil
+}
+
+func (this *client) toDocOfIndividualSigning(info *dbmodels.IndividualSigningInfo) (bson.M, dbmodels.IDBError) {
 	email, err := this.encrypt.encryptStr(info.Email)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	si, err := this.encrypt.encryptSigningInfo(&info.Info)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	signing := dIndividualSigning{
 		CLALanguage: info.CLALanguage,
+		CLAHash:     info.CLAHash,
 		CorpID:      genCorpID(info.Email),
 		ID:          info.ID,
 		Name:        info.Name,
 		Email:       email,
+		EmailIndex:  this.emailIndex.of(info.Email),
 		Date:        info.Date,
-		Enabled:     info.Enabled,
 	}
 	doc, err := structToMap(signing)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	doc[fieldInfo] = si
+	return doc, nil
+}
 
-	elemFilter := bson.M{
-		fieldCorpID: genCorpID(info.Email),
-		fieldEmail:  email,
+func (this *client) SignIndividualCLA(linkID string, info *dbmodels.IndividualSigningInfo) dbmodels.IDBError {
+	doc, err := this.toDocOfIndividualSigning(info)
+	if err != nil {
+		return err
 	}
-	docFilter := docFilterOfSigning(linkID)
-	arrayFilterByElemMatch(fieldSignings, false, elemFilter, docFilter)
+	doc[fieldEnabled] = info.Enabled
+	doc[fieldDeleted] = false
 
 	f := func(ctx context.Context) dbmodels.IDBError {
-		return this.pushArrayElem(ctx, this.individualSigningCollection, fieldSignings, docFilter, doc)
+		return this.insertSigningRecord(
+			ctx, this.individualSigningCollection,
+			this.individualSigningRecordCollection, linkID, doc,
+		)
 	}
 
 	return withContext1(f)
 }
 
-func (this *client) DeleteIndividualSigning(linkID, email string) dbmodels.IDBError {
-	elemFilter, err := this.elemFilterOfIndividualSigning(email)
+// toDocOfIndividualResigning is the update of re-signing. It excludes the enabled
+// status of signing which is saved as false by toDocOfIndividualSigning.
+func (this *client) toDocOfIndividualResigning(info *dbmodels.IndividualSigningInfo) (bson.M, dbmodels.IDBError) {
+	doc, err := this.toDocOfIndividualSigning(info)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(doc, fieldEnabled)
+	return doc, nil
+}
+
+// ResignIndividualCLA keeps the enabled status of signing,
+// because it is managed by the corporation for the employee.
+func (this *client) ResignIndividualCLA(linkID string, info *dbmodels.IndividualSigningInfo) dbmodels.IDBError {
+	doc, err := this.toDocOfIndividualResigning(info)
+	if err != nil {
+		return err
+	}
+
+	filter, err := this.docFilterOfIndividualSigning(linkID, info.Email)
+	if err != nil {
+		return err
+	}
+	filter[fieldCLAHash] = bson.M{"$ne": info.CLAHash}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.updateDoc(ctx, this.individualSigningRecordCollection, filter, doc)
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) DeleteIndividualSigning(linkID, email string, tombstone *dbmodels.SigningTombstone) dbmodels.IDBError {
+	filter, err := this.docFilterOfIndividualSigning(linkID, email)
+	if err != nil {
+		return err
+	}
+
+	deletedFilter, err := this.docFilterOfIndividualSigningRecord(linkID, email, true)
+	if err != nil {
+		return err
+	}
+
+	deletedBy, err := this.encrypt.encryptStr(tombstone.DeletedBy)
+	if err != nil {
+		return err
+	}
+
+	doc, err := structToMap(dSigningTombstone{
+		DeletedBy: deletedBy,
+		Reason:    tombstone.Reason,
+		DeletedAt: tombstone.DeletedAt,
+	})
 	if err != nil {
 		return err
 	}
 
 	f := func(ctx context.Context) dbmodels.IDBError {
-		return this.pullArrayElem(
-			ctx, this.individualSigningCollection, fieldSignings,
-			docFilterOfSigning(linkID), elemFilter,
+		n, err := this.countDocs(ctx, this.individualSigningRecordCollection, filter)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			// It is ok if the individual has not signed.
+			return this.checkLinkOfRecords(ctx, this.individualSigningCollection, linkID)
+		}
+
+		// only the latest tombstone of the individual is kept.
+		if _, err := this.deleteDocs(ctx, this.individualSigningRecordCollection, deletedFilter); err != nil {
+			return err
+		}
+
+		err = this.updateDoc(
+			ctx, this.individualSigningRecordCollection, filter,
+			bson.M{fieldDeleted: true, fieldTombstone: doc},
 		)
+		if err != nil && err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+			// It was deleted concurrently.
+			return nil
+		}
+		return err
 	}
 
 	return withContext1(f)
 }
 
-func (this *client) UpdateIndividualSigning(linkID, email string, enabled bool) dbmodels.IDBError {
-	elemFilter, err := this.elemFilterOfIndividualSigning(email)
+func (this *client) ListDeletedIndividualSignings(linkID, corpEmail string) ([]dbmodels.DeletedIndividualSigning, dbmodels.IDBError) {
+	filter := docFilterOfSigningRecords(linkID, true)
+	if corpEmail != "" {
+		filter[fieldCorpID] = genCorpID(corpEmail)
+	}
+
+	project := bson.M{
+		fieldID:        1,
+		fieldEmail:     1,
+		fieldName:      1,
+		fieldEnabled:   1,
+		fieldDate:      1,
+		fieldTombstone: 1,
+	}
+
+	var docs []dIndividualSigning
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.getDocs(
+			ctx, this.individualSigningRecordCollection, filter, project, &docs,
+		)
+		if err != nil {
+			return newSystemError(err)
+		}
+
+		if len(docs) == 0 {
+			return this.checkLinkOfRecords(ctx, this.individualSigningCollection, linkID)
+		}
+		return nil
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	r := make([]dbmodels.DeletedIndividualSigning, 0, len(docs))
+	for i := range docs {
+		item := &docs[i]
+
+		email, err := this.encrypt.decryptStr(item.Email)
+		if err != nil {
+			return nil, err
+		}
+
+		deletedBy, err := this.encrypt.decryptStr(item.Tombstone.DeletedBy)
+		if err != nil {
+			return nil, err
+		}
+
+		r = append(r, dbmodels.DeletedIndividualSigning{
+			IndividualSigningBasicInfo: dbmodels.IndividualSigningBasicInfo{
+				ID:      item.ID,
+				Email:   email,
+				Name:    item.Name,
+				Enabled: item.Enabled,
+				Date:    item.Date,
+			},
+			SigningTombstone: dbmodels.SigningTombstone{
+				DeletedBy: deletedBy,
+				Reason:    item.Tombstone.Reason,
+				DeletedAt: item.Tombstone.DeletedAt,
+			},
+		})
+	}
+
+	return r, nil
+}
+
+func (this *client) RestoreIndividualSigning(linkID, email string) dbmodels.IDBError {
+	filter, err := this.docFilterOfIndividualSigningRecord(linkID, email, true)
 	if err != nil {
 		return err
 	}
 
-	docFilter := docFilterOfSigning(linkID)
-	arrayFilterByElemMatch(fieldSignings, true, elemFilter, docFilter)
+	f := func(ctx context.Context) dbmodels.IDBError {
+		r, err := this.collection(this.individualSigningRecordCollection).UpdateOne(
+			ctx, filter,
+			bson.M{
+				"$set":   bson.M{fieldDeleted: false},
+				"$unset": bson.M{fieldTombstone: ""},
+			},
+		)
+		if err != nil {
+			// The individual has signed again after the signing was deleted.
+			if isErrDuplicateKey(err) {
+				return newDBError(dbmodels.ErrRecordExists, err)
+			}
+			return newSystemError(err)
+		}
+
+		if r.MatchedCount == 0 {
+			return errNoDBRecord
+		}
+		return nil
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) PurgeDeletedIndividualSignings(deletedBefore int64) dbmodels.IDBError {
+	filter := bson.M{fieldDeleted: true}
+	filter[fieldTombstone+"."+fieldDeletedAt] = bson.M{"$lt": deletedBefore}
 
 	f := func(ctx context.Context) dbmodels.IDBError {
-		return this.updateArrayElem(
-			ctx, this.individualSigningCollection, fieldSignings, docFilter,
-			elemFilter, bson.M{fieldEnabled: enabled},
+		_, err := this.deleteDocs(ctx, this.individualSigningRecordCollection, filter)
+		return err
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) UpdateIndividualSigning(linkID, email string, enabled bool) dbmodels.IDBError {
+	filter, err := this.docFilterOfIndividualSigning(linkID, email)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.updateDoc(
+			ctx, this.individualSigningRecordCollection, filter,
+			bson.M{fieldEnabled: enabled},
 		)
 	}
 
@@ -103,23 +304,19 @@ func (this *client) UpdateIndividualSigning(linkID, email string, enabled bool)
 }
 
 func (this *client) IsIndividualSigned(linkID, email string) (bool, dbmodels.IDBError) {
-	elemFilter, err := this.elemFilterOfIndividualSigning(email)
+	filter, err := this.docFilterOfIndividualSigning(linkID, email)
 	if err != nil {
 		return false, err
 	}
-	elemFilter[fieldEnabled] = true
-
-	docFilter := docFilterOfSigning(linkID)
+	filter[fieldEnabled] = true
 
 	signed := false
 	f := func(ctx context.Context) dbmodels.IDBError {
-		v, err := this.isArrayElemNotExists(
-			ctx, this.individualSigningCollection, fieldSignings, docFilter, elemFilter,
-		)
+		n, err := this.countDocs(ctx, this.individualSigningRecordCollection, filter)
 		if err != nil {
-			return newSystemError(err)
+			return err
 		}
-		signed = !v
+		signed = n > 0
 		return nil
 	}
 
@@ -128,40 +325,39 @@ func (this *client) IsIndividualSigned(linkID, email string) (bool, dbmodels.IDB
 }
 
 func (this *client) ListIndividualSigning(linkID, corpEmail, claLang string) ([]dbmodels.IndividualSigningBasicInfo, dbmodels.IDBError) {
-	docFilter := docFilterOfSigning(linkID)
-
-	arrayFilter := bson.M{}
+	filter := docFilterOfSigningRecords(linkID, false)
 	if corpEmail != "" {
-		arrayFilter[fieldCorpID] = genCorpID(corpEmail)
+		filter[fieldCorpID] = genCorpID(corpEmail)
 	}
 	if claLang != "" {
-		arrayFilter[fieldLang] = claLang
+		filter[fieldLang] = claLang
 	}
 
 	project := bson.M{
-		memberNameOfSignings(fieldID):      1,
-		memberNameOfSignings(fieldEmail):   1,
-		memberNameOfSignings(fieldName):    1,
-		memberNameOfSignings(fieldEnabled): 1,
-		memberNameOfSignings(fieldDate):    1,
+		fieldID:      1,
+		fieldEmail:   1,
+		fieldName:    1,
+		fieldEnabled: 1,
+		fieldDate:    1,
+		fieldLang:    1,
+		fieldCLAHash: 1,
 	}
 
-	var v []cIndividualSigning
+	var docs []dIndividualSigning
 	f := func(ctx context.Context) error {
-		return this.getArrayElem(
-			ctx, this.individualSigningCollection, fieldSignings,
-			docFilter, arrayFilter, project, &v)
+		return this.getDocs(
+			ctx, this.individualSigningRecordCollection, filter, project, &docs,
+		)
 	}
 
 	if err := withContext(f); err != nil {
 		return nil, newSystemError(err)
 	}
 
-	if len(v) == 0 {
+	if len(docs) == 0 {
 		return nil, nil
 	}
 
-	docs := v[0].Signings
 	r := make([]dbmodels.IndividualSigningBasicInfo, 0, len(docs))
 	for i := range docs {
 		item := &docs[i]
@@ -172,13 +368,61 @@ func (this *client) ListIndividualSigning(linkID, corpEmail, claLang string) ([]
 		}
 
 		r = append(r, dbmodels.IndividualSigningBasicInfo{
-			ID:      item.ID,
-			Email:   email,
-			Name:    item.Name,
-			Enabled: item.Enabled,
-			Date:    item.Date,
+			ID:          item.ID,
+			Email:       email,
+			Name:        item.Name,
+			Enabled:     item.Enabled,
+			Date:        item.Date,
+			CLALanguage: item.CLALanguage,
+			CLAHash:     item.CLAHash,
 		})
 	}
 
 	return r, nil
 }
+
+func (this *client) GetIndividualSigningDetail(linkID, email string) (*dbmodels.IndividualSigningInfo, dbmodels.IDBError) {
+	filter, err := this.docFilterOfIndividualSigning(linkID, email)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc dIndividualSigning
+	exists := true
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.getDoc(ctx, this.individualSigningRecordCollection, filter, nil, &doc)
+		if err == nil || !err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+			return err
+		}
+
+		exists = false
+		return this.checkLinkOfRecords(ctx, this.individualSigningCollection, linkID)
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, nil
+	}
+
+	si, err := this.encrypt.decryptSigningInfo(doc.SigningInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dbmodels.IndividualSigningInfo{
+		IndividualSigningBasicInfo: dbmodels.IndividualSigningBasicInfo{
+			ID:          doc.ID,
+			Email:       email,
+			Name:        doc.Name,
+			Date:        doc.Date,
+			Enabled:     doc.Enabled,
+			CLALanguage: doc.CLALanguage,
+			CLAHash:     doc.CLAHash,
+		},
+		Info: *si,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package mongodb
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/opensourceways/app-cla-server/config"
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+// testMongodbConn is the env of the mongodb to run the tests which need a db.
+// These tests are skipped if it is unset.
+const testMongodbConn = "MONGODB_TEST_CONN"
+
+func newTestEncryption(t *testing.T) encryption {
+	e, err := newEncryption(
+		[]config.EncryptionKey{{ID: "", Key: "key-can-be--16-24-32-bytes-long!"}}, "",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+// newTestClient connects to a new db, and the returned func drops it.
+func newTestClient(t *testing.T) (*client, func()) {
+	conn := os.Getenv(testMongodbConn)
+	if conn == "" {
+		t.Skipf("%s is not set", testMongodbConn)
+	}
+
+	cfg := config.MongodbConfig{
+		MongodbConn:                 conn,
+		DBName:                      fmt.Sprintf("cla_test_%d", time.Now().UnixNano()),
+		LinkCollection:              "links",
+		OrgEmailCollection:          "org_emails",
+		CorpPDFCollection:           "corp_pdfs",
+		VCCollection:                "verification_codes",
+		CorpSigningCollection:       "corp_signings",
+		IndividualSigningCollection: "individual_signings",
+		EmailIndexKey:               "email-index-key-which-is-32-bytes-long",
+
+		CorpSigningRecordCollection:       "corp_signing_records",
+		CorpManagerCollection:             "corp_managers",
+		IndividualSigningRecordCollection: "individual_signing_records",
+		SchemaVersionCollection:           "schema_version",
+		AuditLogCollection:                "audit_logs",
+		ResignCampaignCollection:          "resign_campaigns",
+	}
+
+	cli, err := Initialize(&cfg, []config.EncryptionKey{{ID: "", Key: "key-can-be--16-24-32-bytes-long!"}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cli, func() {
+		withContext(cli.db.Drop)
+		cli.Close()
+	}
+}
+
+func testEmployeeSigning(claHash string, enabled bool) *dbmodels.IndividualSigningInfo {
+	return &dbmodels.IndividualSigningInfo{
+		IndividualSigningBasicInfo: dbmodels.IndividualSigningBasicInfo{
+			ID:          "alice",
+			Email:       "alice@example.com",
+			Name:        "alice",
+			Date:        "2021-01-01",
+			Enabled:     enabled,
+			CLALanguage: "english",
+			CLAHash:     claHash,
+		},
+		Info: dbmodels.TypeSigningInfo{},
+	}
+}
+
+func TestToDocOfIndividualResigningExcludesEnabled(t *testing.T) {
+	cli := &client{
+		encrypt:    newTestEncryption(t),
+		emailIndex: blindIndex{key: []byte("email-index-key")},
+	}
+
+	doc, err := cli.toDocOfIndividualResigning(testEmployeeSigning("v2", false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := doc[fieldEnabled]; ok {
+		t.Fatalf("the update of re-signing should not include %s", fieldEnabled)
+	}
+	if doc[fieldCLAHash] != "v2" {
+		t.Fatalf("the update of re-signing should include the new cla hash, got %v", doc[fieldCLAHash])
+	}
+}
+
+func TestResignKeepsEmployeeEnabled(t *testing.T) {
+	cli, clean := newTestClient(t)
+	defer clean()
+
+	linkID := "link1"
+
+	if err := cli.InitializeIndividualSigning(linkID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cli.SignIndividualCLA(linkID, testEmployeeSigning("v1", true)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the employee re-signs without the enabled status which is managed by the corporation.
+	if err := cli.ResignIndividualCLA(linkID, testEmployeeSigning("v2", false)); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := cli.GetIndividualSigningDetail(linkID, "alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v == nil {
+		t.Fatal("the signing is missing after re-signing")
+	}
+	if v.CLAHash != "v2" {
+		t.Fatalf("expect cla hash v2, got %s", v.CLAHash)
+	}
+	if !v.Enabled {
+		t.Fatal("re-signing disabled the employee signing")
+	}
+}
@@ -0,0 +1,357 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// rotationBatchSize is the number of docs loaded at a time while rotating the key.
+const rotationBatchSize = 200
+
+// cEncryptedDoc includes all the encrypted fields of the docs.
+type cEncryptedDoc struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Email     string             `bson:"email"`
+	Info      []byte             `bson:"info"`
+	Token     []byte             `bson:"token"`
+	Actor     string             `bson:"actor"`
+	Target    string             `bson:"target"`
+	Tombstone struct {
+		DeletedBy string `bson:"deleted_by"`
+	} `bson:"tombstone"`
+	OrgEmail struct {
+		Token []byte `bson:"token"`
+	} `bson:"org_email"`
+}
+
+// cCorpPDFFile is the doc of the file of corporation signing pdf in GridFS.
+type cCorpPDFFile struct {
+	ID       primitive.ObjectID `bson:"_id"`
+	Name     string             `bson:"filename"`
+	Metadata dCorpSigningPDF    `bson:"metadata"`
+}
+
+// RotateKey re-encrypts all the data which is not encrypted by the current key.
+// It can run while the servers are working, because they can decrypt the data
+// encrypted by any key in the config. It returns the number of docs and files rewritten,
+// and the names of pdf files which are skipped because they are uploaded concurrently.
+func (this *client) RotateKey() (int, []string, error) {
+	total := 0
+
+	rotations := []struct {
+		collection string
+		project    bson.M
+		reEncrypt  func(*cEncryptedDoc) (bson.M, bson.M, error)
+	}{
+		{this.corpSigningRecordCollection, bson.M{fieldEmail: 1, fieldInfo: 1}, this.reEncryptSigning},
+		{
+			this.individualSigningRecordCollection,
+			bson.M{fieldEmail: 1, fieldInfo: 1, fieldTombstone + "." + fieldDeletedBy: 1},
+			this.reEncryptSigning,
+		},
+		{this.corpManagerCollection, bson.M{fieldEmail: 1}, this.reEncryptSigning},
+		{this.orgEmailCollection, bson.M{fieldToken: 1}, this.reEncryptOrgEmail},
+		{this.linkCollection, bson.M{fieldOrgEmail + "." + fieldToken: 1}, this.reEncryptLink},
+		{this.auditLogCollection, bson.M{fieldActor: 1, fieldTarget: 1}, this.reEncryptAuditLog},
+		{this.resignCampaignCollection, bson.M{fieldEmail: 1}, this.reEncryptSigning},
+	}
+
+	for _, item := range rotations {
+		n, err := this.reEncryptDocs(item.collection, item.project, item.reEncrypt)
+		total += n
+		if err != nil {
+			return total, nil, err
+		}
+	}
+
+	n, skipped, err := this.reEncryptCorpPDFs()
+	return total + n, skipped, err
+}
+
+// getDocsInBatch loads the docs whose id is greater than after in the order of id,
+// so that a collection is iterated in batches and each of them has its own timeout.
+func (this *client) getDocsInBatch(collection string, after primitive.ObjectID, project bson.M, result interface{}) error {
+	filter := bson.M{}
+	if !after.IsZero() {
+		filter["_id"] = bson.M{"$gt": after}
+	}
+
+	opt := options.Find().SetProjection(project).SetSort(bson.M{"_id": 1}).SetLimit(rotationBatchSize)
+
+	return withContext(func(ctx context.Context) error {
+		cursor, err := this.collection(collection).Find(ctx, filter, opt)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, result)
+	})
+}
+
+func (this *client) reEncryptDocs(
+	collection string, project bson.M,
+	reEncrypt func(*cEncryptedDoc) (bson.M, bson.M, error),
+) (int, error) {
+	n := 0
+	var last primitive.ObjectID
+
+	for {
+		var docs []cEncryptedDoc
+		if err := this.getDocsInBatch(collection, last, project, &docs); err != nil {
+			return n, err
+		}
+
+		for i := range docs {
+			doc := &docs[i]
+
+			filter, update, err := reEncrypt(doc)
+			if err != nil {
+				return n, err
+			}
+			if len(update) == 0 {
+				continue
+			}
+
+			// The filter includes the old values, so that the data changed
+			// by the servers concurrently will not be overwritten.
+			filter["_id"] = doc.ID
+
+			f := func(ctx context.Context) error {
+				_, err := this.collection(collection).UpdateOne(ctx, filter, bson.M{"$set": update})
+				return err
+			}
+			if err := withContext(f); err != nil {
+				return n, err
+			}
+			n++
+		}
+
+		if len(docs) < rotationBatchSize {
+			return n, nil
+		}
+		last = docs[len(docs)-1].ID
+	}
+}
+
+func (this *client) reEncryptSigning(doc *cEncryptedDoc) (bson.M, bson.M, error) {
+	filter := bson.M{}
+	update := bson.M{}
+
+	if doc.Email != "" && !this.encrypt.isStrEncryptedByCurrentKey(doc.Email) {
+		v, err := this.encrypt.decryptStr(doc.Email)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if v, err = this.encrypt.encryptStr(v); err != nil {
+			return nil, nil, err
+		}
+
+		filter[fieldEmail] = doc.Email
+		update[fieldEmail] = v
+	}
+
+	if len(doc.Info) > 0 && !this.encrypt.isEncryptedByCurrentKey(doc.Info) {
+		v, err := this.reEncryptBytes(doc.Info)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		filter[fieldInfo] = doc.Info
+		update[fieldInfo] = v
+	}
+
+	if v := doc.Tombstone.DeletedBy; v != "" && !this.encrypt.isStrEncryptedByCurrentKey(v) {
+		s, err := this.reEncryptStr(v)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key := fieldTombstone + "." + fieldDeletedBy
+		filter[key] = v
+		update[key] = s
+	}
+
+	return filter, update, nil
+}
+
+func (this *client) reEncryptOrgEmail(doc *cEncryptedDoc) (bson.M, bson.M, error) {
+	if len(doc.Token) == 0 || this.encrypt.isEncryptedByCurrentKey(doc.Token) {
+		return nil, nil, nil
+	}
+
+	v, err := this.reEncryptBytes(doc.Token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bson.M{fieldToken: doc.Token}, bson.M{fieldToken: v}, nil
+}
+
+func (this *client) reEncryptLink(doc *cEncryptedDoc) (bson.M, bson.M, error) {
+	token := doc.OrgEmail.Token
+	if len(token) == 0 || this.encrypt.isEncryptedByCurrentKey(token) {
+		return nil, nil, nil
+	}
+
+	v, err := this.reEncryptBytes(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := fieldOrgEmail + "." + fieldToken
+	return bson.M{key: token}, bson.M{key: v}, nil
+}
+
+func (this *client) reEncryptAuditLog(doc *cEncryptedDoc) (bson.M, bson.M, error) {
+	filter := bson.M{}
+	update := bson.M{}
+
+	for field, v := range map[string]string{fieldActor: doc.Actor, fieldTarget: doc.Target} {
+		if this.encrypt.isStrEncryptedByCurrentKey(v) {
+			continue
+		}
+
+		s, err := this.reEncryptStr(v)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		filter[field] = v
+		update[field] = s
+	}
+
+	return filter, update, nil
+}
+
+func (this *client) reEncryptStr(data string) (string, error) {
+	v, err := this.encrypt.decryptStr(data)
+	if err != nil {
+		return "", err
+	}
+
+	v, err = this.encrypt.encryptStr(v)
+	if err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+func (this *client) reEncryptBytes(data []byte) ([]byte, error) {
+	v, err := this.encrypt.decryptBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err = this.encrypt.encryptBytes(v)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (this *client) reEncryptCorpPDFs() (int, []string, error) {
+	bucket, err := this.corpPDFBucket()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	n := 0
+	var skipped []string
+	var last primitive.ObjectID
+
+	for {
+		var files []cCorpPDFFile
+		err := this.getDocsInBatch(
+			this.corpPDFFilesCollection(), last, bson.M{"filename": 1, "metadata": 1}, &files,
+		)
+		if err != nil {
+			return n, skipped, err
+		}
+
+		for i := range files {
+			item := &files[i]
+
+			buf := new(bytes.Buffer)
+			bucket.SetReadDeadline(time.Now().Add(10 * time.Second))
+			if _, err := bucket.DownloadToStream(item.ID, buf); err != nil {
+				if err == gridfs.ErrFileNotFound {
+					// it was replaced by the servers.
+					continue
+				}
+				return n, skipped, err
+			}
+
+			if this.encrypt.isEncryptedByCurrentKey(buf.Bytes()) {
+				continue
+			}
+
+			data, err := this.reEncryptBytes(buf.Bytes())
+			if err != nil {
+				return n, skipped, err
+			}
+
+			done, err := this.replaceCorpPDF(bucket, item, data)
+			if err != nil {
+				return n, skipped, err
+			}
+			if done {
+				n++
+			} else {
+				skipped = append(skipped, item.Name)
+			}
+		}
+
+		if len(files) < rotationBatchSize {
+			return n, skipped, nil
+		}
+		last = files[len(files)-1].ID
+	}
+}
+
+// replaceCorpPDF uploads the pdf re-encrypted and deletes the file read by its id.
+// Unlike UploadCorporationSigningPDF, it doesn't delete the other files of the same
+// name, which are uploaded by the servers concurrently. In that case, the file
+// uploaded by it is deleted instead and false is returned, so that the newer pdf
+// will not be replaced by the old one.
+func (this *client) replaceCorpPDF(bucket *gridfs.Bucket, file *cCorpPDFFile, data []byte) (bool, error) {
+	opt := options.GridFSUpload().SetMetadata(file.Metadata)
+
+	bucket.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	id, err := bucket.UploadFromStream(file.Name, bytes.NewReader(data), opt)
+	if err != nil {
+		return false, err
+	}
+
+	// it has been deleted if a new pdf was uploaded by the servers.
+	if err := bucket.Delete(file.ID); err != nil && err != gridfs.ErrFileNotFound {
+		return false, err
+	}
+
+	var others []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	err = withContext(func(ctx context.Context) error {
+		return this.getDocs(
+			ctx, this.corpPDFFilesCollection(),
+			bson.M{"filename": file.Name, "_id": bson.M{"$ne": id}},
+			bson.M{"_id": 1}, &others,
+		)
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(others) == 0 {
+		return true, nil
+	}
+
+	if err := bucket.Delete(id); err != nil && err != gridfs.ErrFileNotFound {
+		return false, err
+	}
+	return false, nil
+}
@@ -0,0 +1,157 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/opensourceways/app-cla-server/config"
+)
+
+// useNewTestKey makes the client encrypt with a new key and decrypt with both.
+func useNewTestKey(t *testing.T, cli *client) {
+	e, err := newEncryption(
+		[]config.EncryptionKey{
+			{ID: "", Key: "key-can-be--16-24-32-bytes-long!"},
+			{ID: "k2", Key: "another-key-of-16-24-32-bytes!!!"},
+		},
+		"k2",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli.encrypt = e
+}
+
+func readTestCorpPDF(t *testing.T, cli *client, linkID, email string) string {
+	dir, err := ioutil.TempDir("", "key-rotation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "corp.pdf")
+	if err := cli.DownloadCorporationSigningPDF(linkID, email, path); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(v)
+}
+
+func TestRotateKey(t *testing.T) {
+	cli, clean := newTestClient(t)
+	defer clean()
+
+	// more than a batch of docs
+	n := rotationBatchSize + 10
+	docs := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		actor, err := cli.encrypt.encryptStr(fmt.Sprintf("actor%d@example.com", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		target, err := cli.encrypt.encryptStr(fmt.Sprintf("target%d@example.com", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, bson.M{fieldActor: actor, fieldTarget: target})
+	}
+	err := withContext(func(ctx context.Context) error {
+		return cli.insertDocs(ctx, cli.auditLogCollection, docs)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linkID, email := "link1", "admin@example.com"
+	if err := cli.UploadCorporationSigningPDF(linkID, email, []byte("pdf")); err != nil {
+		t.Fatal(err)
+	}
+
+	useNewTestKey(t, cli)
+
+	total, skipped, err := cli.RotateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != n+1 || len(skipped) != 0 {
+		t.Fatalf("expect %d rewritten and none skipped, got %d and %v", n+1, total, skipped)
+	}
+
+	var logs []cEncryptedDoc
+	err = withContext(func(ctx context.Context) error {
+		return cli.getDocs(ctx, cli.auditLogCollection, bson.M{}, bson.M{fieldActor: 1, fieldTarget: 1}, &logs)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range logs {
+		if !cli.encrypt.isStrEncryptedByCurrentKey(logs[i].Actor) ||
+			!cli.encrypt.isStrEncryptedByCurrentKey(logs[i].Target) {
+			t.Fatalf("the audit log %s is not re-encrypted", logs[i].ID.Hex())
+		}
+	}
+
+	if v := readTestCorpPDF(t, cli, linkID, email); v != "pdf" {
+		t.Fatalf("expect the re-encrypted pdf, got %s", v)
+	}
+}
+
+func TestReplaceCorpPDFKeepsConcurrentUpload(t *testing.T) {
+	cli, clean := newTestClient(t)
+	defer clean()
+
+	linkID, email := "link1", "admin@example.com"
+	if err := cli.UploadCorporationSigningPDF(linkID, email, []byte("old")); err != nil {
+		t.Fatal(err)
+	}
+
+	var files []cCorpPDFFile
+	err := cli.getDocsInBatch(
+		cli.corpPDFFilesCollection(), primitive.ObjectID{}, bson.M{"filename": 1, "metadata": 1}, &files,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expect 1 file, got %d", len(files))
+	}
+
+	// the servers upload a new pdf while the old one is being re-encrypted.
+	if err := cli.UploadCorporationSigningPDF(linkID, email, []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+
+	useNewTestKey(t, cli)
+
+	bucket, err := cli.corpPDFBucket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err1 := cli.encrypt.encryptBytes([]byte("old"))
+	if err1 != nil {
+		t.Fatal(err1)
+	}
+
+	done, err := cli.replaceCorpPDF(bucket, &files[0], data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done {
+		t.Fatal("the pdf uploaded concurrently should not be replaced")
+	}
+
+	if v := readTestCorpPDF(t, cli, linkID, email); v != "new" {
+		t.Fatalf("expect the pdf uploaded concurrently, got %s", v)
+	}
+}
@@ -33,6 +33,20 @@ func (this *client) GetLinkID(orgRepo *dbmodels.OrgRepo) (string, dbmodels.IDBEr
 	return v.LinkID, nil
 }
 
+func (this *client) HasLink(linkID string) (bool, dbmodels.IDBError) {
+	var n int64
+	f := func(ctx context.Context) dbmodels.IDBError {
+		v, err := this.countDocs(ctx, this.linkCollection, bson.M{fieldLinkID: linkID})
+		n = v
+		return err
+	}
+
+	if err := withContext1(f); err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
 func (this *client) CreateLink(info *dbmodels.LinkCreateOption) (string, dbmodels.IDBError) {
 	doc, err := toDocOfLink(info)
 	if err != nil {
@@ -69,6 +83,10 @@ func (this *client) Unlink(linkID string) dbmodels.IDBError {
 
 		this.updateDoc(ctx, this.corpSigningCollection, docFilter, status)
 		this.updateDoc(ctx, this.individualSigningCollection, docFilter, status)
+
+		this.updateDocs(ctx, this.corpSigningRecordCollection, docFilter, status)
+		this.updateDocs(ctx, this.corpManagerCollection, docFilter, status)
+		this.updateDocs(ctx, this.individualSigningRecordCollection, docFilter, status)
 		return nil
 	}
 
@@ -205,8 +223,10 @@ func toDocOfLink(info *dbmodels.LinkCreateOption) (bson.M, dbmodels.IDBError) {
 
 func toDocOfCLA(cla *dbmodels.CLACreateOption) (bson.M, dbmodels.IDBError) {
 	info := &dCLA{
-		URL:  cla.URL,
-		Text: cla.Text,
+		URL:     cla.URL,
+		Text:    cla.Text,
+		Version: cla.Version,
+		Policy:  cla.Policy,
 		DCLAInfo: DCLAInfo{
 			Fields:           toDocOfCLAField(cla.Fields),
 			Language:         cla.Language,
@@ -240,6 +260,9 @@ func toDocOfCLAField(fs []dbmodels.Field) []dField {
 			Type:        item.Type,
 			Description: item.Description,
 			Required:    item.Required,
+			Options:     item.Options,
+			Regex:       item.Regex,
+			MaxLength:   item.MaxLength,
 		})
 	}
 	return fields
@@ -0,0 +1,185 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+// The version of data layout is saved in the doc whose id is schemaVersionDocID.
+// The db which has no such doc is on the version of 0.
+const schemaVersionDocID = "schema_version"
+
+type cSchemaVersion struct {
+	Version   int   `bson:"version" json:"version"`
+	UpdatedAt int64 `bson:"updated_at" json:"updated_at"`
+}
+
+// migration upgrades the data layout from version-1 to version.
+type migration struct {
+	version int
+	desc    string
+	migrate func(*client) error
+}
+
+// migrations must be sorted by version which increases by 1 each time.
+// Append a new one when the data layout is changed, and never change the applied ones.
+var migrations = []migration{
+	{
+		version: 1,
+		desc:    "move the signings and corporation managers to the record collections",
+		migrate: func(c *client) error {
+			_, err := c.MigrateSignings()
+			return err
+		},
+	},
+	{
+		version: 2,
+		desc:    "delete the verification codes whose expiry is not saved as date",
+		migrate: func(c *client) error {
+			f := func(ctx context.Context) error {
+				_, err := c.deleteDocs(
+					ctx, c.vcCollection,
+					bson.M{fieldExpiry: bson.M{"$not": bson.M{"$type": "date"}}},
+				)
+				if err != nil {
+					return err
+				}
+				return nil
+			}
+			return withContext(f)
+		},
+	},
+	{
+		version: 3,
+		desc:    "encrypt the emails with random nonces and add their blind indexes",
+		migrate: func(c *client) error {
+			return c.AddEmailIndexes()
+		},
+	},
+	{
+		version: 4,
+		desc:    "soft delete the individual signings",
+		migrate: func(c *client) error {
+			return c.enableSoftDeletingIndividualSignings()
+		},
+	},
+	{
+		version: 5,
+		desc:    "version the clas and record the cla hash in the signings",
+		migrate: func(c *client) error {
+			if err := c.versionCLAs(); err != nil {
+				return err
+			}
+			return c.addCLAHashToSignings()
+		},
+	},
+}
+
+func latestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+func (m *migration) String() string {
+	return fmt.Sprintf("%d: %s", m.version, m.desc)
+}
+
+func (this *client) getSchemaVersion() (int, error) {
+	var v cSchemaVersion
+	f := func(ctx context.Context) error {
+		err := this.getDoc(
+			ctx, this.schemaVersionCollection, bson.M{"_id": schemaVersionDocID}, nil, &v,
+		)
+		if err != nil && err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+			return nil
+		}
+		return err
+	}
+
+	if err := withContext(f); err != nil {
+		return 0, err
+	}
+	return v.Version, nil
+}
+
+func (this *client) setSchemaVersion(version int) error {
+	upsert := true
+
+	f := func(ctx context.Context) error {
+		_, err := this.collection(this.schemaVersionCollection).UpdateOne(
+			ctx, bson.M{"_id": schemaVersionDocID},
+			bson.M{
+				"$max": bson.M{"version": version},
+				"$set": bson.M{"updated_at": util.Now()},
+			},
+			&options.UpdateOptions{Upsert: &upsert},
+		)
+		return err
+	}
+
+	return withContext(f)
+}
+
+func (this *client) pendingMigrations() ([]migration, error) {
+	v, err := this.getSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	if latest := latestSchemaVersion(); v > latest {
+		return nil, fmt.Errorf(
+			"the version of db:%d is newer than the one:%d this server supports", v, latest,
+		)
+	}
+
+	return migrations[v:], nil
+}
+
+// CheckSchemaVersion returns error if the db is not on the data layout this server works on.
+func (this *client) CheckSchemaVersion() error {
+	ms, err := this.pendingMigrations()
+	if err != nil {
+		return err
+	}
+
+	if len(ms) > 0 {
+		return fmt.Errorf(
+			"the db is older than this server, run the migrations from version:%d first",
+			ms[0].version,
+		)
+	}
+	return nil
+}
+
+// Migrate applies the pending migrations in order and returns them.
+// It only returns the pending migrations without applying them if dryRun is true.
+func (this *client) Migrate(dryRun bool) ([]string, error) {
+	ms, err := this.pendingMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]string, 0, len(ms))
+	for i := range ms {
+		item := &ms[i]
+
+		if !dryRun {
+			if err := item.migrate(this); err != nil {
+				return r, fmt.Errorf("migration %s failed, err:%s", item.String(), err.Error())
+			}
+
+			if err := this.setSchemaVersion(item.version); err != nil {
+				return r, err
+			}
+		}
+
+		r = append(r, item.String())
+	}
+
+	return r, nil
+}
@@ -1,7 +1,5 @@
 package mongodb
 
-import "fmt"
-
 const (
 	fieldLinkID         = "link_id"
 	fieldLinkStatus     = "link_status"
@@ -26,6 +24,7 @@ const (
 	fieldCLAHash        = "cla_hash"
 	fieldSignatureHash  = "signature_hash"
 	fieldEmail          = "email"
+	fieldEmailIndex     = "email_index"
 	fieldPurpose        = "purpose"
 	fieldCode           = "code"
 	fieldExpiry         = "expiry"
@@ -33,11 +32,30 @@ const (
 	fieldRole           = "role"
 	fieldName           = "name"
 	fieldID             = "id"
-	fieldPDF            = "pdf"
 	fieldDate           = "date"
 	fieldCorp           = "corp"
 	fieldEnabled        = "enabled"
 	fieldInfo           = "info"
+	fieldActor          = "actor"
+	fieldTarget         = "target"
+	fieldTime           = "time"
+	fieldTombstone      = "tombstone"
+	fieldDeletedBy      = "deleted_by"
+	fieldDeletedAt      = "deleted_at"
+	fieldHistory        = "history"
+	fieldVersion        = "version"
+	fieldPolicy         = "policy"
+	fieldCampaignID     = "campaign_id"
+	fieldCreatedAt      = "created_at"
+	fieldDeadline       = "deadline"
+	fieldType           = "type"
+	fieldStatus         = "status"
+	fieldResignedAt     = "resigned_at"
+	fieldDrift          = "drift"
+	fieldCorpPDFTmpls   = "corp_pdf_templates"
+	fieldESigned        = "esigned"
+	fieldSigningPolicy  = "corp_signing_policy"
+	fieldAgreementID    = "agreement_id"
 
 	// 'ready' means the doc is ready to record the signing data currently.
 	// 'deleted' means the signing data is invalid.
@@ -45,10 +63,10 @@ const (
 	linkStatusDeleted = "deleted"
 )
 
+// dCorpSigningPDF is the metadata of GridFS file which saves the corporation signing pdf.
 type dCorpSigningPDF struct {
 	LinkID string `bson:"link_id" json:"link_id" required:"true"`
 	CorpID string `bson:"corp_id" json:"corp_id" required:"true"`
-	PDF    []byte `bson:"pdf" json:"pdf,omitempty"`
 }
 
 type cVerificationCode struct {
@@ -62,21 +80,35 @@ type cIndividualSigning struct {
 	LinkID     string `bson:"link_id" json:"link_id" required:"true"`
 	LinkStatus string `bson:"link_status" json:"link_status" required:"true"`
 
-	CLAInfos []DCLAInfo           `bson:"cla_infos" json:"cla_infos,omitempty"`
-	Signings []dIndividualSigning `bson:"signings" json:"-"`
+	CLAInfos []DCLAInfo `bson:"cla_infos" json:"cla_infos,omitempty"`
 }
 
+// dIndividualSigning is saved as a single doc in individualSigningRecordCollection
+// together with the fields of link_id, link_status and deleted.
 type dIndividualSigning struct {
 	CLALanguage string `bson:"lang" json:"lang" required:"true"`
+	CLAHash     string `bson:"cla_hash" json:"cla_hash"`
 	CorpID      string `bson:"corp_id" json:"corp_id" required:"true"`
 
-	ID      string `bson:"id" json:"id" required:"true"`
-	Name    string `bson:"name" json:"name" required:"true"`
-	Email   string `bson:"email" json:"email" required:"true"`
-	Date    string `bson:"date" json:"date" required:"true"`
-	Enabled bool   `bson:"enabled" json:"enabled"`
+	ID         string `bson:"id" json:"id" required:"true"`
+	Name       string `bson:"name" json:"name" required:"true"`
+	Email      string `bson:"email" json:"email" required:"true"`
+	EmailIndex string `bson:"email_index" json:"email_index" required:"true"`
+	Date       string `bson:"date" json:"date" required:"true"`
+	Enabled    bool   `bson:"enabled" json:"enabled"`
 
 	SigningInfo []byte `bson:"info" json:"-"`
+
+	// Tombstone is only set when the signing is deleted.
+	Tombstone dSigningTombstone `bson:"tombstone" json:"-"`
+}
+
+// dSigningTombstone records the deletion of signing. DeletedBy is encrypted,
+// because it may be an email.
+type dSigningTombstone struct {
+	DeletedBy string `bson:"deleted_by" json:"deleted_by"`
+	Reason    string `bson:"reason" json:"reason"`
+	DeletedAt int64  `bson:"deleted_at" json:"deleted_at"`
 }
 
 type cCorpSigning struct {
@@ -87,34 +119,83 @@ type cCorpSigning struct {
 	OrgEmail    string `bson:"org_email" json:"org_email" required:"true"`
 	OrgAlias    string `bson:"org_alias" json:"org_alias" required:"true"`
 
-	CLAInfos []DCLAInfo     `bson:"cla_infos" json:"cla_infos,omitempty"`
-	Signings []dCorpSigning `bson:"signings" json:"-"`
-	Managers []dCorpManager `bson:"corp_managers" json:"-"`
-	Deleted  []dCorpSigning `bson:"deleted" json:"-"`
+	CLAInfos []DCLAInfo `bson:"cla_infos" json:"cla_infos,omitempty"`
 }
 
+// dCorpSigning is saved as a single doc in corpSigningRecordCollection
+// together with the fields of link_id, link_status and deleted.
 type dCorpSigning struct {
 	CLALanguage string `bson:"lang" json:"lang" required:"true"`
+	CLAHash     string `bson:"cla_hash" json:"cla_hash"`
 	CorpID      string `bson:"corp_id" json:"corp_id" required:"true"`
 	CorpName    string `bson:"corp" json:"corp" required:"true"`
 
 	AdminEmail string `bson:"email" json:"email" required:"true"`
+	EmailIndex string `bson:"email_index" json:"email_index" required:"true"`
 	AdminName  string `bson:"name" json:"name" required:"true"`
 	Date       string `bson:"date" json:"date" required:"true"`
+	ESigned    bool   `bson:"esigned" json:"esigned"`
+
+	AgreementID string `bson:"agreement_id" json:"agreement_id"`
 
 	SigningInfo []byte `bson:"info" json:"-"`
+
+	// History is the deletions and restorations of the signing.
+	History []dCorpSigningEvent `bson:"history" json:"-"`
+}
+
+// dCorpSigningEvent is not encrypted, because the operator is
+// the community manager who is identified by the id on code platform.
+type dCorpSigningEvent struct {
+	Action   string `bson:"action" json:"action" required:"true"`
+	Operator string `bson:"operator" json:"operator"`
+	Time     int64  `bson:"time" json:"time" required:"true"`
 }
 
+// dCorpManager is saved as a single doc in corpManagerCollection
+// together with the fields of link_id and link_status.
 type dCorpManager struct {
+	LinkID string `bson:"link_id" json:"-"`
+
 	ID               string `bson:"id" json:"id" required:"true"`
 	Name             string `bson:"name" json:"name" required:"true"`
 	Role             string `bson:"role" json:"role" required:"true"`
 	Email            string `bson:"email"  json:"email" required:"true"`
+	EmailIndex       string `bson:"email_index" json:"email_index" required:"true"`
 	CorpID           string `bson:"corp_id" json:"corp_id" required:"true"`
 	Password         string `bson:"password" json:"password" required:"true"`
 	InitialPWChanged bool   `bson:"changed" json:"changed"`
 }
 
+// cAuditLog is only appended. The actor and target are encrypted,
+// because they may be emails.
+type cAuditLog struct {
+	Actor      string `bson:"actor" json:"actor" required:"true"`
+	RemoteAddr string `bson:"remote_addr" json:"remote_addr"`
+	Action     string `bson:"action" json:"action" required:"true"`
+	LinkID     string `bson:"link_id" json:"link_id" required:"true"`
+	CorpID     string `bson:"corp_id" json:"corp_id"`
+	Target     string `bson:"target" json:"target"`
+	Time       int64  `bson:"time" json:"time" required:"true"`
+}
+
+// cResignCampaignSigner is a signer of campaign saved as a single doc
+// together with the fields of campaign. The email is encrypted.
+type cResignCampaignSigner struct {
+	CampaignID  string `bson:"campaign_id" json:"campaign_id" required:"true"`
+	LinkID      string `bson:"link_id" json:"link_id" required:"true"`
+	CreatedBy   string `bson:"created_by" json:"created_by"`
+	CreatedAt   int64  `bson:"created_at" json:"created_at" required:"true"`
+	Deadline    int64  `bson:"deadline" json:"deadline" required:"true"`
+	Email       string `bson:"email" json:"email" required:"true"`
+	EmailIndex  string `bson:"email_index" json:"email_index" required:"true"`
+	Name        string `bson:"name" json:"name"`
+	Type        string `bson:"type" json:"type" required:"true"`
+	CLALanguage string `bson:"lang" json:"lang"`
+	Status      string `bson:"status" json:"status" required:"true"`
+	ResignedAt  int64  `bson:"resigned_at" json:"resigned_at"`
+}
+
 type cOrgEmail struct {
 	Email    string `bson:"email" json:"email" required:"true"`
 	Platform string `bson:"platform" json:"platform" required:"true"`
@@ -142,24 +223,55 @@ type cLink struct {
 
 	IndividualCLAs []dCLA `bson:"individual_clas" json:"-"`
 	CorpCLAs       []dCLA `bson:"corp_clas" json:"-"`
+
+	CorpPDFTemplates []dCorpPDFTemplate `bson:"corp_pdf_templates" json:"-"`
+
+	CorpSigningPolicy *dCorpSigningPolicy `bson:"corp_signing_policy" json:"-"`
+}
+
+type dCorpSigningPolicy struct {
+	ESigning           bool `bson:"esigning" json:"esigning"`
+	ScannedPDFRequired bool `bson:"scanned_pdf_required" json:"scanned_pdf_required"`
 }
 
+type dCorpPDFTemplate struct {
+	Language    string `bson:"lang" json:"lang" required:"true"`
+	Welcome     string `bson:"welcome" json:"welcome" required:"true"`
+	Declaration string `bson:"declaration" json:"declaration" required:"true"`
+	Logo        []byte `bson:"logo" json:"-"`
+	LogoType    string `bson:"logo_type" json:"logo_type,omitempty"`
+}
+
+// dCLA is a version of cla. There may be several versions of the same language,
+// and the cla added before versioning is on the version of 0.
 type dCLA struct {
-	URL          string `bson:"url" json:"url" required:"true"`
+	URL          string `bson:"url" json:"url"`
 	Text         string `bson:"text" json:"text" required:"true"`
 	OrgSignature []byte `bson:"org_signature" json:"-"`
+	Version      int    `bson:"version" json:"version"`
+	Policy       string `bson:"policy" json:"policy"`
+
+	// Drift is set by the check on url after the cla is added.
+	Drift *dCLADrift `bson:"drift,omitempty" json:"-"`
 
 	DCLAInfo `bson:",inline"`
 }
 
+type dCLADrift struct {
+	Status    string `bson:"status"`
+	CheckedAt int64  `bson:"checked_at"`
+	URLHash   string `bson:"url_hash"`
+	Draft     string `bson:"draft"`
+}
+
 type dField struct {
 	ID          string `bson:"id" json:"id" required:"true"`
 	Title       string `bson:"title" json:"title" required:"true"`
 	Type        string `bson:"type" json:"type" required:"true"`
 	Description string `bson:"desc" json:"desc,omitempty"`
 	Required    bool   `bson:"required" json:"required"`
-}
 
-func memberNameOfSignings(key string) string {
-	return fmt.Sprintf("%s.%s", fieldSignings, key)
+	Options   []string `bson:"options" json:"options,omitempty"`
+	Regex     string   `bson:"regex" json:"regex,omitempty"`
+	MaxLength int      `bson:"max_length" json:"max_length,omitempty"`
 }
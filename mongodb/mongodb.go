@@ -20,15 +20,25 @@ type client struct {
 	db      *mongo.Database
 	encrypt encryption
 
+	// emailIndex is the blind index of email which is used to look up the encrypted email.
+	emailIndex blindIndex
+
 	vcCollection                string
 	orgEmailCollection          string
 	corpPDFCollection           string
 	linkCollection              string
 	corpSigningCollection       string
 	individualSigningCollection string
+
+	corpSigningRecordCollection       string
+	corpManagerCollection             string
+	individualSigningRecordCollection string
+	schemaVersionCollection           string
+	auditLogCollection                string
+	resignCampaignCollection          string
 }
 
-func Initialize(cfg *config.MongodbConfig, encryptionKey, nonce string) (*client, error) {
+func Initialize(cfg *config.MongodbConfig, keys []config.EncryptionKey, keyID string) (*client, error) {
 	c, err := mongo.NewClient(options.Client().ApplyURI(cfg.MongodbConn))
 	if err != nil {
 		return nil, err
@@ -46,7 +56,7 @@ func Initialize(cfg *config.MongodbConfig, encryptionKey, nonce string) (*client
 		return nil, err
 	}
 
-	e, err := newEncryption(encryptionKey, nonce)
+	e, err := newEncryption(keys, keyID)
 	if err != nil {
 		return nil, err
 	}
@@ -56,13 +66,27 @@ func Initialize(cfg *config.MongodbConfig, encryptionKey, nonce string) (*client
 		db:      c.Database(cfg.DBName),
 		encrypt: e,
 
+		emailIndex: blindIndex{key: []byte(cfg.EmailIndexKey)},
+
 		vcCollection:                cfg.VCCollection,
 		orgEmailCollection:          cfg.OrgEmailCollection,
 		corpPDFCollection:           cfg.CorpPDFCollection,
 		linkCollection:              cfg.LinkCollection,
 		corpSigningCollection:       cfg.CorpSigningCollection,
 		individualSigningCollection: cfg.IndividualSigningCollection,
+
+		corpSigningRecordCollection:       cfg.CorpSigningRecordCollection,
+		corpManagerCollection:             cfg.CorpManagerCollection,
+		individualSigningRecordCollection: cfg.IndividualSigningRecordCollection,
+		schemaVersionCollection:           cfg.SchemaVersionCollection,
+		auditLogCollection:                cfg.AuditLogCollection,
+		resignCampaignCollection:          cfg.ResignCampaignCollection,
 	}
+
+	if err := cli.ensureIndexes(); err != nil {
+		return nil, err
+	}
+
 	return cli, nil
 }
 
@@ -0,0 +1,156 @@
+package mongodb
+
+import (
+	"context"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) AddResignCampaign(linkID string, campaign *dbmodels.ResignCampaign) (string, dbmodels.IDBError) {
+	campaignID := primitive.NewObjectID().Hex()
+
+	docs := make(bson.A, 0, len(campaign.Signers))
+	for i := range campaign.Signers {
+		item := &campaign.Signers[i]
+
+		email, err := this.encrypt.encryptStr(item.Email)
+		if err != nil {
+			return "", err
+		}
+
+		doc, err := structToMap(cResignCampaignSigner{
+			CampaignID:  campaignID,
+			LinkID:      linkID,
+			CreatedBy:   campaign.CreatedBy,
+			CreatedAt:   campaign.CreatedAt,
+			Deadline:    campaign.Deadline,
+			Email:       email,
+			EmailIndex:  this.emailIndex.of(item.Email),
+			Name:        item.Name,
+			Type:        item.Type,
+			CLALanguage: item.CLALanguage,
+			Status:      dbmodels.ResignStatusPending,
+		})
+		if err != nil {
+			return "", err
+		}
+		docs = append(docs, doc)
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		if err := this.checkLinkOfRecords(ctx, this.individualSigningCollection, linkID); err != nil {
+			return err
+		}
+		if len(docs) == 0 {
+			return nil
+		}
+
+		if _, err := this.collection(this.resignCampaignCollection).InsertMany(ctx, docs); err != nil {
+			return newSystemError(err)
+		}
+		return nil
+	}
+
+	if err := withContext1(f); err != nil {
+		return "", err
+	}
+	return campaignID, nil
+}
+
+func (this *client) ListResignCampaigns(linkID string) ([]dbmodels.ResignCampaign, dbmodels.IDBError) {
+	var docs []cResignCampaignSigner
+	f := func(ctx context.Context) error {
+		return this.getDocs(
+			ctx, this.resignCampaignCollection, bson.M{fieldLinkID: linkID}, nil, &docs,
+		)
+	}
+
+	if err := withContext(f); err != nil {
+		return nil, newSystemError(err)
+	}
+
+	var r []dbmodels.ResignCampaign
+	m := map[string]int{}
+	for i := range docs {
+		item := &docs[i]
+
+		email, err := this.encrypt.decryptStr(item.Email)
+		if err != nil {
+			return nil, err
+		}
+
+		j, ok := m[item.CampaignID]
+		if !ok {
+			j = len(r)
+			m[item.CampaignID] = j
+			r = append(r, dbmodels.ResignCampaign{
+				ID:        item.CampaignID,
+				CreatedBy: item.CreatedBy,
+				CreatedAt: item.CreatedAt,
+				Deadline:  item.Deadline,
+			})
+		}
+
+		r[j].Signers = append(r[j].Signers, dbmodels.ResignCampaignSigner{
+			Email:       email,
+			Name:        item.Name,
+			Type:        item.Type,
+			CLALanguage: item.CLALanguage,
+			Status:      item.Status,
+			ResignedAt:  item.ResignedAt,
+		})
+	}
+
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].CreatedAt < r[j].CreatedAt
+	})
+
+	return r, nil
+}
+
+func (this *client) docFilterOfPendingSigner(linkID, email string, signerTypes []string) bson.M {
+	return bson.M{
+		fieldLinkID:     linkID,
+		fieldEmailIndex: this.emailIndex.of(email),
+		fieldStatus:     dbmodels.ResignStatusPending,
+		fieldType:       bson.M{"$in": signerTypes},
+	}
+}
+
+func (this *client) ResignInCampaigns(linkID, email string, signerTypes []string, resignedAt int64) dbmodels.IDBError {
+	filter := this.docFilterOfPendingSigner(linkID, email, signerTypes)
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.updateDocs(
+			ctx, this.resignCampaignCollection, filter,
+			bson.M{
+				fieldStatus:     dbmodels.ResignStatusResigned,
+				fieldResignedAt: resignedAt,
+			},
+		)
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) HasOverdueResign(linkID, email string, signerTypes []string, now int64) (bool, dbmodels.IDBError) {
+	filter := this.docFilterOfPendingSigner(linkID, email, signerTypes)
+	filter[fieldDeadline] = bson.M{"$lt": now}
+
+	overdue := false
+	f := func(ctx context.Context) dbmodels.IDBError {
+		n, err := this.countDocs(ctx, this.resignCampaignCollection, filter)
+		if err != nil {
+			return err
+		}
+		overdue = n > 0
+		return nil
+	}
+
+	err := withContext1(f)
+	return overdue, err
+}
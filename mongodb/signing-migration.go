@@ -0,0 +1,340 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// cSigningArrays is the old layout which saves all the signings and managers
+// of a link in the arrays of the doc of link.
+type cSigningArrays struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	LinkID     string             `bson:"link_id"`
+	LinkStatus string             `bson:"link_status"`
+
+	Signings []bson.Raw `bson:"signings"`
+	Managers []bson.Raw `bson:"corp_managers"`
+	Deleted  []bson.Raw `bson:"deleted"`
+}
+
+// MigrateSignings moves the signings and managers saved in the old layout to the
+// record collections. It can run while the server is working and can be rerun safely.
+// A record which has been written by the server will not be overwritten, and each
+// array element is pulled from the doc of link only after it is moved.
+// It returns the number of the links migrated.
+func (this *client) MigrateSignings() (int, error) {
+	total := 0
+
+	for _, collection := range []string{this.corpSigningCollection, this.individualSigningCollection} {
+		var docs []cSigningArrays
+
+		f := func(ctx context.Context) error {
+			return this.getDocs(
+				ctx, collection,
+				bson.M{"$or": bson.A{
+					bson.M{fieldSignings + ".0": bson.M{"$exists": true}},
+					bson.M{fieldCorpManagers + ".0": bson.M{"$exists": true}},
+					bson.M{fieldDeleted + ".0": bson.M{"$exists": true}},
+				}},
+				bson.M{
+					fieldLinkID:       1,
+					fieldLinkStatus:   1,
+					fieldSignings:     1,
+					fieldCorpManagers: 1,
+					fieldDeleted:      1,
+				},
+				&docs,
+			)
+		}
+		if err := withContext(f); err != nil {
+			return total, err
+		}
+
+		for i := range docs {
+			if err := this.migrateSigningsOfLink(collection, &docs[i]); err != nil {
+				return total, err
+			}
+			total++
+		}
+	}
+
+	return total, nil
+}
+
+func (this *client) migrateSigningsOfLink(collection string, doc *cSigningArrays) error {
+	isCorp := collection == this.corpSigningCollection
+
+	signingRecords := this.individualSigningRecordCollection
+	signingKeys := []string{fieldEmail}
+	if isCorp {
+		signingRecords = this.corpSigningRecordCollection
+		signingKeys = []string{fieldCorpID}
+	}
+
+	items := []struct {
+		records string
+		keys    []string
+		values  []bson.Raw
+		extra   bson.M
+	}{
+		{signingRecords, signingKeys, doc.Signings, nil},
+		{this.corpManagerCollection, []string{fieldEmail}, doc.Managers, nil},
+		{
+			this.corpSigningRecordCollection,
+			[]string{fieldCorpID, fieldEmail, fieldDate},
+			doc.Deleted, bson.M{fieldDeleted: true},
+		},
+	}
+	if isCorp {
+		items[0].extra = bson.M{fieldDeleted: false}
+	}
+
+	for _, item := range items {
+		for _, raw := range item.values {
+			f := func(ctx context.Context) error {
+				return this.upsertSigningRecord(ctx, item.records, doc, raw, item.keys, item.extra)
+			}
+			if err := withContext(f); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Pull the exact elements moved, so that the ones pushed concurrently
+	// by the server of old version will be kept and moved next time.
+	f := func(ctx context.Context) error {
+		_, err := this.collection(collection).UpdateOne(
+			ctx, bson.M{"_id": doc.ID},
+			bson.M{"$pullAll": bson.M{
+				fieldSignings:     toBsonArray(doc.Signings),
+				fieldCorpManagers: toBsonArray(doc.Managers),
+				fieldDeleted:      toBsonArray(doc.Deleted),
+			}},
+		)
+		return err
+	}
+
+	return withContext(f)
+}
+
+func (this *client) upsertSigningRecord(ctx context.Context, collection string, link *cSigningArrays, raw bson.Raw, keys []string, extra bson.M) error {
+	var record bson.M
+	if err := bson.Unmarshal(raw, &record); err != nil {
+		return err
+	}
+
+	record[fieldLinkID] = link.LinkID
+	record[fieldLinkStatus] = link.LinkStatus
+	for k, v := range extra {
+		record[k] = v
+	}
+
+	filter := bson.M{fieldLinkID: link.LinkID}
+	for _, k := range keys {
+		filter[k] = record[k]
+	}
+	for k, v := range extra {
+		filter[k] = v
+	}
+
+	upsert := true
+	_, err := this.collection(collection).UpdateOne(
+		ctx, filter, bson.M{"$setOnInsert": record},
+		&options.UpdateOptions{Upsert: &upsert},
+	)
+	if err != nil && isErrDuplicateKey(err) {
+		// The record has been written by the server.
+		return nil
+	}
+	return err
+}
+
+func toBsonArray(v []bson.Raw) bson.A {
+	r := make(bson.A, 0, len(v))
+	for i := range v {
+		r = append(r, v[i])
+	}
+	return r
+}
+
+// encryptEmailOfRecord re-encrypts the email which was encrypted with a fixed nonce
+// and sets the blind index of it.
+func (this *client) encryptEmailOfRecord(record bson.M) error {
+	email, ok := record[fieldEmail].(string)
+	if !ok || email == "" {
+		return nil
+	}
+
+	v, err := this.encrypt.decryptStr(email)
+	if err != nil {
+		return err
+	}
+
+	if email, err = this.encrypt.encryptStr(v); err != nil {
+		return err
+	}
+
+	record[fieldEmail] = email
+	record[fieldEmailIndex] = this.emailIndex.of(v)
+	return nil
+}
+
+// AddEmailIndexes encrypts the emails of records with random nonces and sets
+// their blind indexes, if the records were saved before the blind index was introduced,
+// such as the ones moved by MigrateSignings.
+func (this *client) AddEmailIndexes() error {
+	collections := []string{
+		this.corpSigningRecordCollection,
+		this.corpManagerCollection,
+		this.individualSigningRecordCollection,
+	}
+
+	for _, collection := range collections {
+		var docs []struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Email string             `bson:"email"`
+		}
+
+		f := func(ctx context.Context) error {
+			return this.getDocs(
+				ctx, collection,
+				bson.M{fieldEmailIndex: bson.M{"$exists": false}},
+				bson.M{fieldEmail: 1}, &docs,
+			)
+		}
+		if err := withContext(f); err != nil {
+			return err
+		}
+
+		for i := range docs {
+			item := &docs[i]
+
+			record := bson.M{fieldEmail: item.Email}
+			if err := this.encryptEmailOfRecord(record); err != nil {
+				return err
+			}
+
+			f := func(ctx context.Context) error {
+				_, err := this.collection(collection).UpdateOne(
+					ctx,
+					bson.M{"_id": item.ID, fieldEmail: item.Email},
+					bson.M{"$set": record},
+				)
+				return err
+			}
+			if err := withContext(f); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// enableSoftDeletingIndividualSignings marks the individual signings as undeleted, and
+// replaces the unique index of email with the one which skips the deleted signings,
+// so that the individual can sign again after the signing is deleted.
+func (this *client) enableSoftDeletingIndividualSignings() error {
+	f := func(ctx context.Context) error {
+		col := this.collection(this.individualSigningRecordCollection)
+
+		_, err := col.UpdateMany(
+			ctx, bson.M{fieldDeleted: bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{fieldDeleted: false}},
+		)
+		if err != nil {
+			return err
+		}
+
+		if _, err := col.Indexes().DropOne(ctx, "link_id_email_index"); err != nil && !isErrIndexNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	if err := withContext(f); err != nil {
+		return err
+	}
+
+	return this.ensureIndexes()
+}
+
+// versionCLAs sets the version of 0 to the clas which were added before versioning.
+func (this *client) versionCLAs() error {
+	f := func(ctx context.Context) error {
+		col := this.collection(this.linkCollection)
+
+		for _, array := range []string{fieldCorpCLAs, fieldIndividualCLAs} {
+			_, err := col.UpdateMany(
+				ctx, bson.M{array: bson.M{"$exists": true}},
+				bson.M{"$set": bson.M{fmt.Sprintf("%s.$[i].%s", array, fieldVersion): 0}},
+				&options.UpdateOptions{
+					ArrayFilters: &options.ArrayFilters{
+						Filters: bson.A{
+							bson.M{"i." + fieldVersion: bson.M{"$exists": false}},
+						},
+					},
+				},
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return withContext(f)
+}
+
+// addCLAHashToSignings sets the cla hash of signings which were saved before the cla
+// was versioned. There was only one cla info of each language at that time.
+func (this *client) addCLAHashToSignings() error {
+	collections := map[string]string{
+		this.corpSigningCollection:       this.corpSigningRecordCollection,
+		this.individualSigningCollection: this.individualSigningRecordCollection,
+	}
+
+	for linkCollection, recordCollection := range collections {
+		var docs []struct {
+			LinkID   string     `bson:"link_id"`
+			CLAInfos []DCLAInfo `bson:"cla_infos"`
+		}
+
+		f := func(ctx context.Context) error {
+			return this.getDocs(
+				ctx, linkCollection, bson.M{},
+				bson.M{fieldLinkID: 1, fieldCLAInfos: 1}, &docs,
+			)
+		}
+		if err := withContext(f); err != nil {
+			return err
+		}
+
+		for i := range docs {
+			for j := range docs[i].CLAInfos {
+				filter := bson.M{
+					fieldLinkID:  docs[i].LinkID,
+					fieldLang:    docs[i].CLAInfos[j].Language,
+					fieldCLAHash: bson.M{"$exists": false},
+				}
+				update := bson.M{fieldCLAHash: docs[i].CLAInfos[j].CLAHash}
+
+				f := func(ctx context.Context) error {
+					if err := this.updateDocs(ctx, recordCollection, filter, update); err != nil {
+						return err
+					}
+					return nil
+				}
+				if err := withContext(f); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+// The signings and corporation managers of a link are saved as separate docs in the
+// record collections, and each of them has the fields of link_id and link_status.
+// The doc of link in corpSigningCollection or individualSigningCollection only saves
+// the basic info of link and the cla infos.
+// The signing records are soft deleted by setting the field of deleted to true.
+
+func docFilterOfSigningRecords(linkID string, deleted bool) bson.M {
+	filter := docFilterOfSigning(linkID)
+	filter[fieldDeleted] = deleted
+	return filter
+}
+
+func toDocOfSigningRecord(linkID string, doc bson.M) bson.M {
+	doc[fieldLinkID] = linkID
+	doc[fieldLinkStatus] = linkStatusReady
+	return doc
+}
+
+// checkLinkOfRecords returns errNoDBRecord if the link which the records belong to does not exist.
+func (this *client) checkLinkOfRecords(ctx context.Context, linkCollection, linkID string) dbmodels.IDBError {
+	n, err := this.countDocs(ctx, linkCollection, docFilterOfSigning(linkID))
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return errNoDBRecord
+	}
+	return nil
+}
+
+// insertSigningRecord returns errNoDBRecord if the link does not exist or the record exists.
+func (this *client) insertSigningRecord(ctx context.Context, linkCollection, recordCollection, linkID string, doc bson.M) dbmodels.IDBError {
+	if err := this.checkLinkOfRecords(ctx, linkCollection, linkID); err != nil {
+		return err
+	}
+
+	if _, err := this.insertDoc(ctx, recordCollection, toDocOfSigningRecord(linkID, doc)); err != nil {
+		if isErrDuplicateKey(err) {
+			return errNoDBRecord
+		}
+		return newSystemError(err)
+	}
+	return nil
+}
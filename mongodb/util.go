@@ -25,6 +25,25 @@ func isErrNoDocuments(err error) bool {
 	return err.Error() == mongo.ErrNoDocuments.Error()
 }
 
+func isErrDuplicateKey(err error) bool {
+	// 11000 is the code of duplicate key error.
+	switch v := err.(type) {
+	case mongo.WriteException:
+		for _, item := range v.WriteErrors {
+			if item.Code == 11000 {
+				return true
+			}
+		}
+	case mongo.BulkWriteException:
+		for _, item := range v.WriteErrors {
+			if item.Code == 11000 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (this *client) isArrayElemNotExists(ctx context.Context, collection, array string, filterOfDoc, filterOfArray bson.M) (bool, error) {
 	query := bson.M{array: bson.M{"$elemMatch": filterOfArray}}
 	for k, v := range filterOfDoc {
@@ -129,6 +148,12 @@ func (this *client) getDocs(ctx context.Context, collection string, filterOfDoc,
 	return cursor.All(ctx, result)
 }
 
+func (this *client) insertDocs(ctx context.Context, collection string, docs bson.A) error {
+	col := this.collection(collection)
+	_, err := col.InsertMany(ctx, docs)
+	return err
+}
+
 func (this *client) insertDoc(ctx context.Context, collection string, docInfo bson.M) (string, error) {
 	col := this.collection(collection)
 	r, err := col.InsertOne(ctx, docInfo)
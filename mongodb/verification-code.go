@@ -2,12 +2,12 @@ package mongodb
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/opensourceways/app-cla-server/dbmodels"
-	"github.com/opensourceways/app-cla-server/util"
 )
 
 func (this *client) CreateVerificationCode(opt dbmodels.VerificationCode) dbmodels.IDBError {
@@ -21,14 +21,10 @@ func (this *client) CreateVerificationCode(opt dbmodels.VerificationCode) dbmode
 	if err != nil {
 		return err
 	}
+	// The expiry is saved as date, so that the TTL index can delete the expired codes.
+	body[fieldExpiry] = time.Unix(opt.Expiry, 0)
 
 	f := func(ctx context.Context) dbmodels.IDBError {
-		col := this.collection(this.vcCollection)
-
-		// delete the expired codes.
-		filter := bson.M{fieldExpiry: bson.M{"$lt": util.Now()}}
-		col.DeleteMany(ctx, filter)
-
 		// email + purpose can't be the index, for example: a corp signs a community concurrently.
 		// so, it should use insertDoc to record each verification codes.
 		_, err := this.insertDoc(ctx, this.vcCollection, body)
@@ -43,7 +39,7 @@ func (this *client) CreateVerificationCode(opt dbmodels.VerificationCode) dbmode
 
 func (this *client) GetVerificationCode(opt *dbmodels.VerificationCode) dbmodels.IDBError {
 	var v struct {
-		Expiry int64 `bson:"expiry"`
+		Expiry time.Time `bson:"expiry"`
 	}
 
 	f := func(ctx context.Context) dbmodels.IDBError {
@@ -75,6 +71,6 @@ func (this *client) GetVerificationCode(opt *dbmodels.VerificationCode) dbmodels
 		return err
 	}
 
-	opt.Expiry = v.Expiry
+	opt.Expiry = v.Expiry.Unix()
 	return nil
 }
@@ -53,6 +53,11 @@ func (fs fileStorage) ListCorporationsWithPDFUploaded(linkID string) ([]string,
 	return result, nil
 }
 
+func (fs fileStorage) DeleteCorporationSigningPDF(linkID, email string) dbmodels.IDBError {
+	err := fs.c.DeleteObject(buildCorpSigningPDFPath(linkID, email))
+	return toDBError(err)
+}
+
 func buildCorpSigningPDFPath(linkID string, email string) string {
 	return fmt.Sprintf("%s/%s", linkID, util.EmailSuffix(email))
 }
@@ -87,6 +87,17 @@ func (cli *client) HasObject(path string) (bool, error) {
 	return false, err
 }
 
+func (cli *client) DeleteObject(path string) error {
+	_, err := cli.c.DeleteObject(&sdk.DeleteObjectInput{
+		Bucket: cli.bucket,
+		Key:    path,
+	})
+	if err == nil || (obsError{err: err}).IsObjectNotFound() {
+		return nil
+	}
+	return err
+}
+
 func (cli *client) ListObject(pathPrefix string) ([]string, error) {
 	input := sdk.ListObjectsInput{
 		Bucket: cli.bucket,
@@ -0,0 +1,192 @@
+package local
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opensourceways/app-cla-server/obs"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+const plugin = "local-directory"
+
+// client saves the objects in the local directory which is specified by bucket.
+type client struct {
+	dir     string
+	encrypt util.SymmetricEncryption
+}
+
+func init() {
+	obs.Register(plugin, &client{})
+}
+
+func (cli *client) Initialize(path, bucket string) error {
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	dir, err := filepath.Abs(bucket)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	if cfg.ObjectEncryptionKey != "" {
+		// the nonce is generated randomly for each object when it is empty.
+		e, err := util.NewSymmetricEncryption(cfg.ObjectEncryptionKey, "")
+		if err != nil {
+			return err
+		}
+		cli.encrypt = e
+	}
+
+	cli.dir = dir
+
+	return nil
+}
+
+func (cli *client) WriteObject(path string, data []byte) error {
+	file, err := cli.objectFile(path)
+	if err != nil {
+		return err
+	}
+
+	if cli.encrypt != nil {
+		if data, err = cli.encrypt.Encrypt(data); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so a reader never sees a partial object.
+	tmp, err := ioutil.TempFile(filepath.Dir(file), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
+
+func (cli *client) ReadObject(path, localPath string) obs.OBSError {
+	file, err := cli.objectFile(path)
+	if err != nil {
+		return obsError{err: err}
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return obsError{err: err}
+	}
+
+	if cli.encrypt != nil {
+		if data, err = cli.encrypt.Decrypt(data); err != nil {
+			return obsError{err: err}
+		}
+	}
+
+	if err := ioutil.WriteFile(localPath, data, 0644); err != nil {
+		return obsError{err: err}
+	}
+	return nil
+}
+
+func (cli *client) HasObject(path string) (bool, error) {
+	file, err := cli.objectFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	v, err := os.Stat(file)
+	if err == nil {
+		return !v.IsDir(), nil
+	}
+
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+func (cli *client) DeleteObject(path string) error {
+	file, err := cli.objectFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (cli *client) ListObject(pathPrefix string) ([]string, error) {
+	r := make([]string, 0, 100)
+
+	err := filepath.Walk(cli.dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".tmp-") {
+			return nil
+		}
+
+		key, err := filepath.Rel(cli.dir, file)
+		if err != nil {
+			return err
+		}
+
+		if key = filepath.ToSlash(key); strings.HasPrefix(key, pathPrefix) {
+			r = append(r, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (cli *client) objectFile(path string) (string, error) {
+	file := filepath.Join(cli.dir, filepath.FromSlash(path))
+	if !strings.HasPrefix(file, cli.dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid object path: %s", path)
+	}
+	return file, nil
+}
+
+type obsError struct {
+	err error
+}
+
+func (e obsError) IsObjectNotFound() bool {
+	return os.IsNotExist(e.err)
+}
+
+func (e obsError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	return ""
+}
@@ -0,0 +1,18 @@
+package local
+
+import (
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+type config struct {
+	ObjectEncryptionKey string `json:"object_encryption_key"`
+}
+
+func loadConfig(path string) (*config, error) {
+	v := &config{}
+	if err := util.LoadFromYaml(path, v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
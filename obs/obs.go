@@ -12,6 +12,8 @@ type OBS interface {
 	ReadObject(path, localPath string) OBSError
 	HasObject(string) (bool, error)
 	ListObject(pathPrefix string) ([]string, error)
+	// DeleteObject doesn't return error if the object doesn't exist.
+	DeleteObject(path string) error
 }
 
 var instances = map[string]OBS{}
@@ -0,0 +1,288 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+var (
+	oidData                 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData           = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidAttrContentType      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidAttrMessageDigest    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidAttrSigningCertV2    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 47}
+	oidDigestSHA256         = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSignatureRSA         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidSignatureSHA256ECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+)
+
+// The structures of CMS SignedData defined by RFC 5652, which only
+// cover what a detached signature of pdf needs.
+
+type cmsContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type cmsSignedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo cmsContentInfo
+	Certificates     asn1.RawValue   `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue   `asn1:"optional,tag:1"`
+	SignerInfos      []cmsSignerInfo `asn1:"set"`
+}
+
+type cmsIssuerAndSerial struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type cmsSignerInfo struct {
+	Version            int
+	SID                cmsIssuerAndSerial
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type cmsAttribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue
+}
+
+// signCMS generates the detached CMS signature of the content whose sha256 digest is digest.
+// The signed attributes include the signing certificate required by PAdES, so that
+// the signer certificate can't be replaced.
+func signCMS(digest []byte, key crypto.Signer, certs []*x509.Certificate) ([]byte, error) {
+	cert := certs[0]
+
+	sigAlg, err := signatureAlgorithm(key)
+	if err != nil {
+		return nil, err
+	}
+
+	certHash := sha256.Sum256(cert.Raw)
+	essCertID, err := asn1.Marshal(struct{ CertHash []byte }{certHash[:]})
+	if err != nil {
+		return nil, err
+	}
+	signingCert, err := asn1.Marshal(struct{ Certs asn1.RawValue }{
+		asn1.RawValue{Tag: asn1.TagSequence, IsCompound: true, Bytes: essCertID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	contentType, err := asn1.Marshal(oidData)
+	if err != nil {
+		return nil, err
+	}
+	messageDigest, err := asn1.Marshal(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, err := marshalSignedAttrs([]cmsAttribute{
+		{Type: oidAttrContentType, Values: setOf(contentType)},
+		{Type: oidAttrMessageDigest, Values: setOf(messageDigest)},
+		{Type: oidAttrSigningCertV2, Values: setOf(signingCert)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.Sum256(attrs)
+	signature, err := key.Sign(rand.Reader, h[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	// the signed attributes are signed as SET OF and saved as [0] IMPLICIT.
+	signedAttrs := append([]byte{0xa0}, attrs[1:]...)
+
+	sha256Alg := pkix.AlgorithmIdentifier{Algorithm: oidDigestSHA256}
+
+	var rawCerts []byte
+	for _, item := range certs {
+		rawCerts = append(rawCerts, item.Raw...)
+	}
+
+	sd := cmsSignedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{sha256Alg},
+		EncapContentInfo: cmsContentInfo{ContentType: oidData},
+		Certificates: asn1.RawValue{
+			Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: rawCerts,
+		},
+		SignerInfos: []cmsSignerInfo{{
+			Version: 1,
+			SID: cmsIssuerAndSerial{
+				Issuer:       asn1.RawValue{FullBytes: cert.RawIssuer},
+				SerialNumber: cert.SerialNumber,
+			},
+			DigestAlgorithm:    sha256Alg,
+			SignedAttrs:        asn1.RawValue{FullBytes: signedAttrs},
+			SignatureAlgorithm: sigAlg,
+			Signature:          signature,
+		}},
+	}
+
+	v, err := asn1.Marshal(sd)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(cmsContentInfo{
+		ContentType: oidSignedData,
+		Content: asn1.RawValue{
+			Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: v,
+		},
+	})
+}
+
+func signatureAlgorithm(key crypto.Signer) (pkix.AlgorithmIdentifier, error) {
+	switch key.Public().(type) {
+	case *rsa.PublicKey:
+		return pkix.AlgorithmIdentifier{
+			Algorithm:  oidSignatureRSA,
+			Parameters: asn1.NullRawValue,
+		}, nil
+
+	case *ecdsa.PublicKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSignatureSHA256ECDSA}, nil
+	}
+
+	return pkix.AlgorithmIdentifier{}, fmt.Errorf("unsupported key, only rsa and ecdsa are supported")
+}
+
+func setOf(v []byte) asn1.RawValue {
+	return asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: v}
+}
+
+// marshalSignedAttrs encodes the attributes as DER SET OF whose elements must be sorted.
+func marshalSignedAttrs(attrs []cmsAttribute) ([]byte, error) {
+	items := make([][]byte, 0, len(attrs))
+	for i := range attrs {
+		b, err := asn1.Marshal(attrs[i])
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, b)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return bytes.Compare(items[i], items[j]) < 0
+	})
+
+	return asn1.Marshal(setOf(bytes.Join(items, nil)))
+}
+
+// parseCMS parses the detached CMS signature without verifying it,
+// and returns the signer info and the certificate of signer.
+func parseCMS(data []byte) (*cmsSignerInfo, *x509.Certificate, error) {
+	var ci cmsContentInfo
+	if _, err := asn1.Unmarshal(data, &ci); err != nil {
+		return nil, nil, fmt.Errorf("invalid cms: %s", err.Error())
+	}
+	if !ci.ContentType.Equal(oidSignedData) {
+		return nil, nil, fmt.Errorf("the cms is not signed data")
+	}
+
+	var sd cmsSignedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return nil, nil, fmt.Errorf("invalid signed data: %s", err.Error())
+	}
+	if len(sd.SignerInfos) != 1 {
+		return nil, nil, fmt.Errorf("the signed data should have one signer")
+	}
+
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid certificates: %s", err.Error())
+	}
+
+	si := &sd.SignerInfos[0]
+	var cert *x509.Certificate
+	for _, item := range certs {
+		if bytes.Equal(item.RawIssuer, si.SID.Issuer.FullBytes) &&
+			item.SerialNumber.Cmp(si.SID.SerialNumber) == 0 {
+			cert = item
+			break
+		}
+	}
+	if cert == nil {
+		return nil, nil, fmt.Errorf("missing the certificate of signer")
+	}
+
+	return si, cert, nil
+}
+
+// verifyCMS checks the detached CMS signature against the sha256 digest of content,
+// and returns the certificate of signer.
+func verifyCMS(data, digest []byte) (*x509.Certificate, error) {
+	si, cert, err := parseCMS(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !si.DigestAlgorithm.Algorithm.Equal(oidDigestSHA256) {
+		return nil, fmt.Errorf("unsupported digest algorithm: %s", si.DigestAlgorithm.Algorithm)
+	}
+
+	if len(si.SignedAttrs.FullBytes) == 0 {
+		return nil, fmt.Errorf("missing signed attributes")
+	}
+	attrs := append([]byte{0x31}, si.SignedAttrs.FullBytes[1:]...)
+
+	v, err := signedAttrValue(attrs, oidAttrMessageDigest)
+	if err != nil {
+		return nil, err
+	}
+	var md []byte
+	if _, err := asn1.Unmarshal(v, &md); err != nil || !bytes.Equal(md, digest) {
+		return nil, fmt.Errorf("the digest of content is not matched")
+	}
+
+	var alg x509.SignatureAlgorithm
+	switch cert.PublicKeyAlgorithm {
+	case x509.RSA:
+		alg = x509.SHA256WithRSA
+	case x509.ECDSA:
+		alg = x509.ECDSAWithSHA256
+	default:
+		return nil, fmt.Errorf("unsupported public key algorithm of signer")
+	}
+
+	if err := cert.CheckSignature(alg, attrs, si.Signature); err != nil {
+		return nil, fmt.Errorf("invalid signature: %s", err.Error())
+	}
+
+	return cert, nil
+}
+
+func signedAttrValue(attrs []byte, oid asn1.ObjectIdentifier) ([]byte, error) {
+	var items []cmsAttribute
+	if _, err := asn1.UnmarshalWithParams(attrs, &items, "set"); err != nil {
+		return nil, fmt.Errorf("invalid signed attributes: %s", err.Error())
+	}
+
+	for i := range items {
+		if items[i].Type.Equal(oid) {
+			return items[i].Values.Bytes, nil
+		}
+	}
+	return nil, fmt.Errorf("missing the signed attribute: %s", oid)
+}
@@ -0,0 +1,241 @@
+package pdf
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/opensourceways/gofpdf"
+
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+// corpPDFConfig defines the pdf of corporation signing in a language.
+// Each language is defined by a yaml file, so that a new language can be supported
+// by adding the file, the templates and the fonts without changing the code.
+type corpPDFConfig struct {
+	Language            string `json:"language" required:"true"`
+	WelcomeTemplate     string `json:"welcome_template" required:"true"`
+	DeclarationTemplate string `json:"declaration_template" required:"true"`
+	Subtitle            string `json:"subtitle" required:"true"`
+
+	// FooterFormat is the format of page number, such as "Page %d".
+	FooterFormat string `json:"footer_format" required:"true"`
+
+	// SignatureItems are the rows of signature page. The first row is the titles
+	// of community and corporation, and each of the others is the label of an item.
+	SignatureItems [][]string `json:"signature_items" required:"true"`
+	SignatureDate  string     `json:"signature_date" required:"true"`
+
+	// ESignedNote is the optional note under the signature of corporation
+	// which signs electronically.
+	ESignedNote string `json:"esigned_note"`
+
+	// AgreementIDFormat and AgreementURLFormat are the formats of the agreement id and
+	// the url to verify it, which are printed beside the QR code on the signature page.
+	AgreementIDFormat  string `json:"agreement_id_format"`
+	AgreementURLFormat string `json:"agreement_url_format"`
+
+	Layout pageLayout `json:"layout"`
+
+	// FontDir is the directory of the font files of UTF8Fonts.
+	FontDir   string     `json:"font_dir"`
+	UTF8Fonts []utf8Font `json:"utf8_fonts"`
+	Fonts     textFonts  `json:"fonts"`
+}
+
+type pageLayout struct {
+	// Orientation is 'P' for portrait or 'L' for landscape.
+	Orientation string  `json:"orientation"`
+	PageSize    string  `json:"page_size"`
+	LineHeight  float64 `json:"line_height"`
+}
+
+type utf8Font struct {
+	Family string `json:"family" required:"true"`
+	Style  string `json:"style"`
+	File   string `json:"file" required:"true"`
+}
+
+type fontConfig struct {
+	Font string  `json:"font"`
+	Size float64 `json:"size"`
+}
+
+type textFonts struct {
+	Footer    fontConfig `json:"footer"`
+	Title     fontConfig `json:"title"`
+	Welcome   fontConfig `json:"welcome"`
+	Contact   fontConfig `json:"contact"`
+	Declare   fontConfig `json:"declaration"`
+	CLA       fontConfig `json:"cla"`
+	URL       fontConfig `json:"url"`
+	Signature fontConfig `json:"signature"`
+
+	// ESignature is the font of the name typed by the corporation which
+	// signs electronically. It is the font of contact if unset.
+	ESignature fontConfig `json:"esignature"`
+}
+
+func (cfg *corpPDFConfig) setDefault() {
+	cfg.Language = strings.ToLower(cfg.Language)
+
+	if cfg.Layout.Orientation == "" {
+		cfg.Layout.Orientation = "P"
+	}
+	if cfg.Layout.PageSize == "" {
+		cfg.Layout.PageSize = "A4"
+	}
+	if cfg.Layout.LineHeight <= 0 {
+		cfg.Layout.LineHeight = 5.0
+	}
+
+	if cfg.FontDir == "" {
+		cfg.FontDir = "./conf/pdf-font"
+	}
+
+	if cfg.AgreementIDFormat == "" {
+		cfg.AgreementIDFormat = "Agreement ID: %s"
+	}
+	if cfg.AgreementURLFormat == "" {
+		cfg.AgreementURLFormat = "Verify it at: %s"
+	}
+
+	if cfg.Fonts.ESignature.Font == "" {
+		cfg.Fonts.ESignature = cfg.Fonts.Contact
+	}
+}
+
+func (cfg *corpPDFConfig) validate() error {
+	if strings.Count(cfg.FooterFormat, "%d") != 1 {
+		return fmt.Errorf("footer_format must contain one %%d")
+	}
+
+	if strings.Count(cfg.AgreementIDFormat, "%s") != 1 {
+		return fmt.Errorf("agreement_id_format must contain one %%s")
+	}
+	if strings.Count(cfg.AgreementURLFormat, "%s") != 1 {
+		return fmt.Errorf("agreement_url_format must contain one %%s")
+	}
+
+	if len(cfg.SignatureItems) == 0 {
+		return fmt.Errorf("missing signature_items")
+	}
+	for _, item := range cfg.SignatureItems {
+		if len(item) != 2 {
+			return fmt.Errorf("each row of signature_items must have 2 items")
+		}
+	}
+
+	fonts := map[string]fontConfig{
+		"footer":      cfg.Fonts.Footer,
+		"title":       cfg.Fonts.Title,
+		"welcome":     cfg.Fonts.Welcome,
+		"contact":     cfg.Fonts.Contact,
+		"declaration": cfg.Fonts.Declare,
+		"cla":         cfg.Fonts.CLA,
+		"url":         cfg.Fonts.URL,
+		"signature":   cfg.Fonts.Signature,
+		"esignature":  cfg.Fonts.ESignature,
+	}
+	for k, v := range fonts {
+		if v.Font == "" || v.Size <= 0 {
+			return fmt.Errorf("invalid font of %s", k)
+		}
+	}
+
+	return nil
+}
+
+func (cfg *corpPDFConfig) newCorpSigningPDF() (*corpSigningPDF, error) {
+	welTemp, err := util.NewTemplate("wel", cfg.WelcomeTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	declTemp, err := util.NewTemplate("decl", cfg.DeclarationTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	toFont := func(v fontConfig) fontInfo {
+		return fontInfo{font: v.Font, size: v.Size}
+	}
+
+	layout := cfg.Layout
+	fontDir := cfg.FontDir
+	utf8Fonts := cfg.UTF8Fonts
+	footerFormat := cfg.FooterFormat
+	agreementIDFormat := cfg.AgreementIDFormat
+	agreementURLFormat := cfg.AgreementURLFormat
+
+	return &corpSigningPDF{
+		language:    cfg.Language,
+		welcomeTemp: welTemp,
+		declaration: declTemp,
+		gh:          layout.LineHeight,
+
+		footerFont:    toFont(cfg.Fonts.Footer),
+		titleFont:     toFont(cfg.Fonts.Title),
+		welcomeFont:   toFont(cfg.Fonts.Welcome),
+		contactFont:   toFont(cfg.Fonts.Contact),
+		declareFont:   toFont(cfg.Fonts.Declare),
+		claFont:       toFont(cfg.Fonts.CLA),
+		urlFont:       toFont(cfg.Fonts.URL),
+		signatureFont: toFont(cfg.Fonts.Signature),
+		esigFont:      toFont(cfg.Fonts.ESignature),
+
+		subtitle: cfg.Subtitle,
+
+		footerNumber: func(num int) string { return fmt.Sprintf(footerFormat, num) },
+
+		signatureItems: cfg.SignatureItems,
+		signatureDate:  cfg.SignatureDate,
+		esignedNote:    cfg.ESignedNote,
+
+		agreementText: func(id, url string) string {
+			return fmt.Sprintf(agreementIDFormat, id) + "\n" + fmt.Sprintf(agreementURLFormat, url)
+		},
+
+		newPDF: func() *gofpdf.Fpdf {
+			pdf := gofpdf.New(layout.Orientation, "mm", layout.PageSize, fontDir)
+			for _, item := range utf8Fonts {
+				pdf.AddUTF8Font(item.Family, item.Style, item.File)
+			}
+			return pdf
+		},
+	}, nil
+}
+
+// loadCorpPDFConfigs loads the config of each language from the yaml files in dir.
+func loadCorpPDFConfigs(dir string) ([]corpPDFConfig, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no config of corporation pdf in %s", dir)
+	}
+
+	r := make([]corpPDFConfig, 0, len(files))
+	langs := map[string]bool{}
+	for _, f := range files {
+		var cfg corpPDFConfig
+		if err := util.LoadFromYaml(f, &cfg); err != nil {
+			return nil, fmt.Errorf("load %s failed: %s", f, err.Error())
+		}
+
+		cfg.setDefault()
+		if err := cfg.validate(); err != nil {
+			return nil, fmt.Errorf("invalid config of %s: %s", f, err.Error())
+		}
+
+		if langs[cfg.Language] {
+			return nil, fmt.Errorf("duplicate language: %s", cfg.Language)
+		}
+		langs[cfg.Language] = true
+
+		r = append(r, cfg)
+	}
+	return r, nil
+}
@@ -3,9 +3,27 @@ package pdf
 import (
 	"bytes"
 	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
 	"text/template"
 
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
 	"github.com/opensourceways/gofpdf"
+	"github.com/opensourceways/gofpdf/contrib/gofpdi"
+
+	"github.com/opensourceways/app-cla-server/models"
+)
+
+// the height of logo in mm
+const logoHeight = 15.0
+
+const (
+	// the size of QR code of agreement in mm
+	qrCodeSize = 25.0
+	// the size of QR code image in pixels, which is clear enough to be printed and scanned.
+	qrCodePixels = 300
 )
 
 type fontInfo struct {
@@ -28,13 +46,19 @@ type corpSigningPDF struct {
 	claFont       fontInfo
 	urlFont       fontInfo
 	signatureFont fontInfo
+	esigFont      fontInfo
 
 	subtitle     string
 	footerNumber func(int) string
 
 	signatureItems [][]string
 	signatureDate  string
+	esignedNote    string
 	newPDF         func() *gofpdf.Fpdf
+
+	// agreementURL is the format of url to verify the agreement, such as "https://x/%s".
+	agreementURL  string
+	agreementText func(id, url string) string
 }
 
 func (this *corpSigningPDF) begin() *gofpdf.Fpdf {
@@ -65,9 +89,47 @@ func (this *corpSigningPDF) end(pdf *gofpdf.Fpdf, path string) error {
 	return pdf.OutputFileAndClose(path)
 }
 
-func (this *corpSigningPDF) firstPage(pdf *gofpdf.Fpdf, title string) {
+// corpTemplates are the templates to generate a pdf, which are
+// the default ones of language or the ones customized by community.
+type corpTemplates struct {
+	welcome     *template.Template
+	declaration *template.Template
+	logo        []byte
+	logoType    string
+}
+
+// templates parses the customized templates, and returns the default ones if t is nil.
+func (this *corpSigningPDF) templates(t *models.CorpPDFTemplate) (*corpTemplates, error) {
+	if t == nil {
+		return &corpTemplates{
+			welcome:     this.welcomeTemp,
+			declaration: this.declaration,
+		}, nil
+	}
+
+	wel, err := template.New("wel").Parse(t.Welcome)
+	if err != nil {
+		return nil, fmt.Errorf("invalid welcome template: %s", err.Error())
+	}
+
+	decl, err := template.New("decl").Parse(t.Declaration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid declaration template: %s", err.Error())
+	}
+
+	return &corpTemplates{
+		welcome:     wel,
+		declaration: decl,
+		logo:        t.Logo,
+		logoType:    t.LogoType,
+	}, nil
+}
+
+func (this *corpSigningPDF) firstPage(pdf *gofpdf.Fpdf, title string, t *corpTemplates) {
 	pdf.AddPage()
 
+	this.logo(pdf, t)
+
 	setFont(pdf, this.titleFont)
 
 	pdf.CellFormat(0, 10, title, "", 1, "C", false, 0, "")
@@ -77,17 +139,33 @@ func (this *corpSigningPDF) firstPage(pdf *gofpdf.Fpdf, title string) {
 	pdf.Ln(-1)
 }
 
-func (this *corpSigningPDF) welcome(pdf *gofpdf.Fpdf, org, email string) {
-	data := struct {
-		Org   string
-		Email string
-	}{
-		Org:   org,
-		Email: email,
+// logo draws the logo at the center of top of the page.
+func (this *corpSigningPDF) logo(pdf *gofpdf.Fpdf, t *corpTemplates) {
+	if len(t.logo) == 0 {
+		return
 	}
 
-	tmpl := this.welcomeTemp
+	opt := gofpdf.ImageOptions{ImageType: t.logoType}
+	info := pdf.RegisterImageOptionsReader("logo", opt, bytes.NewReader(t.logo))
+	if pdf.Err() || info == nil || info.Height() <= 0 {
+		pdf.SetErrorf("Failed to add logo: invalid image")
+		return
+	}
+
+	h := logoHeight
+	w := h * info.Width() / info.Height()
+	pw, _ := pdf.GetPageSize()
+
+	pdf.ImageOptions("logo", (pw-w)/2, pdf.GetY(), w, h, true, opt, 0, "")
+	pdf.Ln(-1)
+}
+
+type templateData struct {
+	Org   string
+	Email string
+}
 
+func (this *corpSigningPDF) welcome(pdf *gofpdf.Fpdf, tmpl *template.Template, data *templateData) {
 	buf := new(bytes.Buffer)
 	if err := tmpl.Execute(buf, data); err != nil {
 		pdf.SetErrorf("Failed to add welcome part: execute template failed: %s", err.Error())
@@ -101,12 +179,17 @@ func (this *corpSigningPDF) welcome(pdf *gofpdf.Fpdf, org, email string) {
 func (this *corpSigningPDF) contact(pdf *gofpdf.Fpdf, items map[string]string, orders []string, titles map[string]string) {
 	gh := this.gh
 
+	// the value is 130mm wide on A4 page.
+	w, _ := pdf.GetPageSize()
+	l, _, r, _ := pdf.GetMargins()
+	vw := w - l - r - 60
+
 	f := func(title, value string) {
 		pdf.CellFormat(50, gh, fmt.Sprintf("%s:", title), "", 0, "R", false, 0, "")
 
 		pdf.Cell(2, gh, " ")
 
-		pdf.MultiCell(130, gh, value, "B", "L", false)
+		pdf.MultiCell(vw, gh, value, "B", "L", false)
 
 		pdf.Ln(-1)
 	}
@@ -118,11 +201,9 @@ func (this *corpSigningPDF) contact(pdf *gofpdf.Fpdf, items map[string]string, o
 	}
 }
 
-func (this *corpSigningPDF) declare(pdf *gofpdf.Fpdf) {
-	tmpl := this.declaration
-
+func (this *corpSigningPDF) declare(pdf *gofpdf.Fpdf, tmpl *template.Template, data *templateData) {
 	buf := new(bytes.Buffer)
-	if err := tmpl.Execute(buf, nil); err != nil {
+	if err := tmpl.Execute(buf, data); err != nil {
 		pdf.SetErrorf("Failed to add declaration part: execute template failed: %s", err.Error())
 		return
 	}
@@ -141,30 +222,171 @@ func (this *corpSigningPDF) projectURL(pdf *gofpdf.Fpdf, url string) {
 	multlines(pdf, this.gh, url)
 }
 
-func (this *corpSigningPDF) secondPage(pdf *gofpdf.Fpdf, date string) {
+// orgSignaturePage is the page signed by the community, which is imported from the org signature pdf.
+type orgSignaturePage struct {
+	importer *gofpdi.Importer
+	tpl      int
+}
+
+// importOrgSignaturePage imports the first page of the org signature pdf.
+func importOrgSignaturePage(pdf *gofpdf.Fpdf, file string) (page *orgSignaturePage, err error) {
+	// gofpdi panics if it fails to parse the pdf.
+	defer func() {
+		if r := recover(); r != nil {
+			page = nil
+			err = fmt.Errorf("failed to import the org signature page of %s: %v", file, r)
+		}
+	}()
+
+	imp := gofpdi.NewImporter()
+	tpl := imp.ImportPage(pdf, file, 1, "/MediaBox")
+
+	return &orgSignaturePage{importer: imp, tpl: tpl}, nil
+}
+
+// secondPage adds the signature page. The org signature page is drawn
+// as the background of it, unless orgSig is nil which means the org
+// signature page will be merged into it afterward. The signature of
+// corporation is filled if it signs electronically.
+func (this *corpSigningPDF) secondPage(pdf *gofpdf.Fpdf, date string, orgSig *orgSignaturePage, esig *models.CorpESignature) {
 	items := make([][]string, len(this.signatureItems))
 	for i := range items {
 		items[i] = []string{"", ""}
 	}
 
-	this.genSignatureItems(pdf, items)
+	pdf.AddPage()
+	if orgSig != nil {
+		w, h := pdf.GetPageSize()
+		orgSig.importer.UseImportedTemplate(pdf, orgSig.tpl, 0, 0, w, h)
+	}
+
+	y := this.genSignatureItems(pdf, items)
+
+	if esig == nil {
+		addSignatureItem(pdf, this.gh, this.signatureDate, this.signatureDate, date, "")
+		return
+	}
+
+	addSignatureItem(pdf, this.gh, this.signatureDate, this.signatureDate, date, date)
+	this.corpESignature(pdf, esig, y)
+
+	if this.esignedNote != "" {
+		pdf.Ln(-1)
+		setFont(pdf, this.signatureFont)
+		multlines(pdf, this.gh, this.esignedNote)
+	}
+}
+
+// corpESignature draws the signature of corporation on the value line whose top is y
+// of the first signature item.
+func (this *corpSigningPDF) corpESignature(pdf *gofpdf.Fpdf, esig *models.CorpESignature, y float64) {
+	w := signatureCellWidth(pdf)
+	l, _, _, _ := pdf.GetMargins()
+	x := l + w + 5
+	gh := this.gh
+
+	if esig.Type != models.ESignatureDrawn {
+		x0, y0 := pdf.GetXY()
+		pdf.SetXY(x, y)
+		setFont(pdf, this.esigFont)
+		pdf.CellFormat(w, gh, esig.Text, "", 0, "L", false, 0, "")
+		pdf.SetXY(x0, y0)
+		return
+	}
+
+	opt := gofpdf.ImageOptions{ImageType: "png"}
+	info := pdf.RegisterImageOptionsReader("esignature", opt, bytes.NewReader(esig.ImageData()))
+	if pdf.Err() || info == nil || info.Height() <= 0 {
+		pdf.SetErrorf("Failed to add the signature of corporation: invalid image")
+		return
+	}
+
+	// the image is above the line and takes the space of two lines at most.
+	h := 2 * gh
+	iw := h * info.Width() / info.Height()
+	if iw > w {
+		iw = w
+		h = w * info.Height() / info.Width()
+	}
+
+	pdf.ImageOptions("esignature", x, y+gh-h, iw, h, false, opt, 0, "")
+}
+
+// agreement draws the QR code of the url to verify the agreement under the
+// signature items, and the agreement id and the url beside it.
+func (this *corpSigningPDF) agreement(pdf *gofpdf.Fpdf, agreementID string) {
+	url := fmt.Sprintf(this.agreementURL, agreementID)
+
+	data, err := genQRCode(url)
+	if err != nil {
+		pdf.SetErrorf("Failed to generate the QR code of agreement: %s", err.Error())
+		return
+	}
+
+	opt := gofpdf.ImageOptions{ImageType: "png"}
+	if pdf.RegisterImageOptionsReader("agreement", opt, bytes.NewReader(data)); pdf.Err() {
+		return
+	}
+
+	pdf.Ln(-1)
+
+	// keep the QR code and the text together on the signature page.
+	_, h := pdf.GetPageSize()
+	_, _, _, b := pdf.GetMargins()
+	if pdf.GetY()+qrCodeSize > h-b {
+		pdf.AddPage()
+	}
+
+	w, _ := pdf.GetPageSize()
+	l, _, r, _ := pdf.GetMargins()
+	x, y := l, pdf.GetY()
+
+	pdf.ImageOptions("agreement", x, y, qrCodeSize, qrCodeSize, false, opt, 0, "")
+
+	pdf.SetXY(x+qrCodeSize+5, y+(qrCodeSize-3*this.gh)/2)
+	setFont(pdf, this.signatureFont)
+	pdf.MultiCell(w-l-r-qrCodeSize-5, this.gh, this.agreementText(agreementID, url), "", "L", false)
+
+	pdf.SetXY(x, y+qrCodeSize)
+}
+
+// genQRCode returns the png image of QR code of content. The image is 8-bit
+// grayscale, because gofpdf doesn't support the png of 16-bit depth.
+func genQRCode(content string) ([]byte, error) {
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return nil, err
+	}
 
-	addSignatureItem(pdf, this.gh, this.signatureDate, this.signatureDate, date, "")
+	if code, err = barcode.Scale(code, qrCodePixels, qrCodePixels); err != nil {
+		return nil, err
+	}
+
+	img := image.NewGray(code.Bounds())
+	draw.Draw(img, img.Bounds(), code, code.Bounds().Min, draw.Src)
+
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 func (this *corpSigningPDF) genBlankSignaturePage(path string) error {
 	pdf := this.newPDF()
 
+	pdf.AddPage()
 	this.genSignatureItems(pdf, this.signatureItems)
 
 	return this.end(pdf, path)
 }
 
-func (this *corpSigningPDF) genSignatureItems(pdf *gofpdf.Fpdf, items [][]string) {
-	pdf.AddPage()
+// genSignatureItems adds the rows of signature items, and returns the top of
+// the value line of the first item.
+func (this *corpSigningPDF) genSignatureItems(pdf *gofpdf.Fpdf, items [][]string) float64 {
 	setFont(pdf, this.signatureFont)
 
-	w := 92.5
+	w := signatureCellWidth(pdf)
 	gh := this.gh
 
 	pdf.CellFormat(w, gh, items[0][0], "", 0, "C", false, 0, "")
@@ -173,13 +395,25 @@ func (this *corpSigningPDF) genSignatureItems(pdf *gofpdf.Fpdf, items [][]string
 	pdf.CellFormat(w, gh, items[0][1], "", 1, "C", false, 0, "")
 	pdf.Ln(10)
 
+	// the title of item and the empty line above the value line.
+	y := pdf.GetY() + 2*gh
+
 	for i := 1; i < len(items); i++ {
 		addSignatureItem(pdf, gh, items[i][0], items[i][1], "", "")
 	}
+
+	return y
+}
+
+// signatureCellWidth returns the width of each of the two columns on signature page.
+func signatureCellWidth(pdf *gofpdf.Fpdf) float64 {
+	w, _ := pdf.GetPageSize()
+	l, _, r, _ := pdf.GetMargins()
+	return (w - l - r - 5) / 2
 }
 
 func addSignatureItem(pdf *gofpdf.Fpdf, gh float64, ltitle, rtitle, lvalue, rvalue string) {
-	w := 92.5
+	w := signatureCellWidth(pdf)
 
 	b := ""
 	if ltitle != "" {
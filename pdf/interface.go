@@ -1,13 +1,9 @@
 package pdf
 
 import (
-	"fmt"
-	"text/template"
-
-	"github.com/opensourceways/gofpdf"
+	"sort"
 
 	"github.com/opensourceways/app-cla-server/models"
-	"github.com/opensourceways/app-cla-server/util"
 )
 
 type IPDFGenerator interface {
@@ -15,27 +11,77 @@ type IPDFGenerator interface {
 	GetBlankSignaturePath(string) string
 
 	GenPDFForCorporationSigning(linkID, orgSignatureFile, claFile string, orgInfo *models.OrgInfo, signing *models.CorporationSigning, claFields []models.CLAField) (string, error)
+	GenESignedPDFForCorporationSigning(linkID, orgSignatureFile, claFile string, orgInfo *models.OrgInfo, signing *models.CorporationSigning, claFields []models.CLAField, esig *models.CorpESignature) (string, error)
+	GenSamplePDFForCorporationSigning(linkID, orgSignatureFile, claFile string, orgInfo *models.OrgInfo, signing *models.CorporationSigning, claFields []models.CLAField, tmpl *models.CorpPDFTemplate) (string, error)
+
+	CheckCorpSigningPDF(data []byte, linkID, claFile string, orgInfo *models.OrgInfo, signing *models.CorporationSigning, claFields []models.CLAField) (map[string]string, error)
+
+	// VerifySignature checks the signature of the community if the pdf has it, and
+	// only the signatures and annotations can be added after the signed revision.
+	// It always succeeds if the signing is not enabled.
+	VerifySignature(data []byte) error
+}
+
+// The items of the uploaded pdf of corporation signing which are checked.
+const (
+	PDFItemMeta        = "meta"
+	PDFItemPageCount   = "page_count"
+	PDFItemCLAHash     = "cla_hash"
+	PDFItemCorpName    = "corporation_name"
+	PDFItemAdminEmail  = "admin_email"
+	PDFItemAgreementID = "agreement_id"
+
+	// PDFErrMissing means the item can't be checked, such as the meta of scanned pdf.
+	PDFErrMissing   = "missing"
+	PDFErrUnmatched = "unmatched"
+)
+
+// FindActiveContent returns the active content of pdf, such as JavaScript,
+// launch actions and embedded files. It returns error if the pdf can't be parsed.
+func FindActiveContent(data []byte) ([]string, error) {
+	f, err := parsePDF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	v := f.activeContent()
+	sort.Strings(v)
+	return v, nil
 }
 
 var generator *pdfGenerator
 
-func InitPDFGenerator(pythonBin, pdfOutDir, pdfOrgSigDir string) error {
+// InitPDFGenerator initializes the generator with the languages of corporation
+// pdf which are defined by the yaml files in corpLangDir. The generated pdf is
+// signed digitally if signCertFile and signKeyFile are set. agreementURL is the
+// format of url to verify the agreement, which contains one %s of agreement id.
+func InitPDFGenerator(pythonBin, pdfOutDir, pdfOrgSigDir, corpLangDir, signCertFile, signKeyFile, agreementURL string) error {
 	generator = &pdfGenerator{
 		pythonBin:    pythonBin,
 		pdfOutDir:    pdfOutDir,
 		pdfOrgSigDir: pdfOrgSigDir,
 	}
 
-	corp := []*corpSigningPDF{}
-	m := []func() (*corpSigningPDF, error){
-		newGeneratorForEnglish,
-		newGeneratorForChinese,
+	if signCertFile != "" {
+		signer, err := newPDFSigner(signCertFile, signKeyFile)
+		if err != nil {
+			return err
+		}
+		generator.signer = signer
+	}
+
+	cfgs, err := loadCorpPDFConfigs(corpLangDir)
+	if err != nil {
+		return err
 	}
-	for _, f := range m {
-		c, err := f()
+
+	corp := make([]*corpSigningPDF, 0, len(cfgs))
+	for i := range cfgs {
+		c, err := cfgs[i].newCorpSigningPDF()
 		if err != nil {
 			return err
 		}
+		c.agreementURL = agreementURL
 
 		blankPDF := generator.GetBlankSignaturePath(c.language)
 		if err = c.genBlankSignaturePage(blankPDF); err != nil {
@@ -51,112 +97,3 @@ func InitPDFGenerator(pythonBin, pdfOutDir, pdfOrgSigDir string) error {
 func GetPDFGenerator() IPDFGenerator {
 	return generator
 }
-
-func newGeneratorForEnglish() (*corpSigningPDF, error) {
-	lang := "english"
-
-	welTemp, err := newWelcomeTmpl(lang)
-	if err != nil {
-		return nil, err
-	}
-
-	declTemp, err := newDeclTmpl(lang)
-	if err != nil {
-		return nil, err
-	}
-
-	return &corpSigningPDF{
-		language:    lang,
-		welcomeTemp: welTemp,
-		declaration: declTemp,
-		gh:          5.0,
-
-		footerFont:    fontInfo{font: "Arial", size: 8},
-		titleFont:     fontInfo{font: "Arial", size: 12},
-		welcomeFont:   fontInfo{font: "Times", size: 12},
-		contactFont:   fontInfo{font: "NotoSansSC-Regular", size: 12},
-		declareFont:   fontInfo{font: "Times", size: 12},
-		claFont:       fontInfo{font: "Times", size: 12},
-		urlFont:       fontInfo{font: "Times", size: 12},
-		signatureFont: fontInfo{font: "Arial", size: 12},
-
-		subtitle: "Software Grant and Corporate Contributor License Agreement (\"Agreement\")",
-
-		footerNumber: func(num int) string { return fmt.Sprintf("Page %d", num) },
-
-		signatureItems: [][]string{
-			{"Community Sign", "Corporation Sign"},
-			{"Signature", "Signature and Seal"},
-			{"Title", "Title"},
-			{"Community", "Corporation"},
-		},
-		signatureDate: "Date",
-
-		newPDF: func() *gofpdf.Fpdf {
-			pdf := gofpdf.New("P", "mm", "A4", "./conf/pdf-font") // 210mm x 297mm
-			pdf.AddUTF8Font("NotoSansSC-Regular", "", "NotoSansSC-Regular.ttf")
-			return pdf
-		},
-	}, nil
-}
-
-func newGeneratorForChinese() (*corpSigningPDF, error) {
-	lang := "chinese"
-
-	welTemp, err := newWelcomeTmpl(lang)
-	if err != nil {
-		return nil, err
-	}
-
-	declTemp, err := newDeclTmpl(lang)
-	if err != nil {
-		return nil, err
-	}
-
-	return &corpSigningPDF{
-		language:    lang,
-		welcomeTemp: welTemp,
-		declaration: declTemp,
-		gh:          5.0,
-
-		footerFont:    fontInfo{font: "NotoSansSC-Regular", size: 8},
-		titleFont:     fontInfo{font: "NotoSansSC-Regular", size: 12},
-		welcomeFont:   fontInfo{font: "NotoSansSC-Regular", size: 12},
-		contactFont:   fontInfo{font: "NotoSansSC-Regular", size: 12},
-		declareFont:   fontInfo{font: "NotoSansSC-Regular", size: 12},
-		claFont:       fontInfo{font: "NotoSansSC-Regular", size: 12},
-		urlFont:       fontInfo{font: "Times", size: 12},
-		signatureFont: fontInfo{font: "NotoSansSC-Regular", size: 12},
-
-		subtitle: "软件授权和企业贡献者许可协议 (\"协议\")",
-
-		footerNumber: func(num int) string { return fmt.Sprintf("%d 页", num) },
-
-		signatureItems: [][]string{
-			{"社区签署", "企业签署"},
-			{"签名", "签名(加盖公章)"},
-			{"职位", "职位"},
-			{"社区名称", "企业名称"},
-		},
-		signatureDate: "日期",
-
-		newPDF: func() *gofpdf.Fpdf {
-			pdf := gofpdf.New("P", "mm", "A4", "./conf/pdf-font") // 210mm x 297mm
-			pdf.AddUTF8Font("NotoSansSC-Regular", "", "NotoSansSC-Regular.ttf")
-			pdf.AddUTF8Font("NotoSansSC-Regular", "I", "NotoSansSC-Regular.ttf")
-			return pdf
-		},
-	}, nil
-}
-
-func newWelcomeTmpl(lang string) (*template.Template, error) {
-	return util.NewTemplate("wel", pathOfTemplate("welcome", lang))
-}
-
-func newDeclTmpl(lang string) (*template.Template, error) {
-	return util.NewTemplate("decl", pathOfTemplate("declaration", lang))
-}
-
-func pathOfTemplate(part, lang string) string {
-	return fmt.Sprintf("./conf/pdf_template_corporation/%s_%s.tmpl", part, lang)
-}
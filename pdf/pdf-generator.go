@@ -1,6 +1,7 @@
 package pdf
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -9,6 +10,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/astaxie/beego"
+
 	"github.com/opensourceways/app-cla-server/models"
 	"github.com/opensourceways/app-cla-server/util"
 )
@@ -18,6 +21,7 @@ type pdfGenerator struct {
 	pdfOrgSigDir string
 	pythonBin    string
 	corp         []*corpSigningPDF
+	signer       *pdfSigner
 }
 
 func (this *pdfGenerator) LangSupported() map[string]bool {
@@ -40,20 +44,193 @@ func (this *pdfGenerator) generator(claLang string) *corpSigningPDF {
 	}
 	return nil
 }
+
+// GenPDFForCorporationSigning generates the pdf with the templates customized by
+// the community if there are, otherwise the default ones of the language.
+// The pdf is signed digitally at last if the signing is enabled.
 func (this *pdfGenerator) GenPDFForCorporationSigning(linkID, orgSignatureFile, claFile string, orgInfo *models.OrgInfo, signing *models.CorporationSigning, claFields []models.CLAField) (string, error) {
+	return this.genSignedPDF(linkID, orgSignatureFile, claFile, orgInfo, signing, claFields, nil)
+}
+
+// GenESignedPDFForCorporationSigning generates the pdf on which the signature of
+// corporation is filled, so that it needn't be printed, signed and scanned.
+func (this *pdfGenerator) GenESignedPDFForCorporationSigning(linkID, orgSignatureFile, claFile string, orgInfo *models.OrgInfo, signing *models.CorporationSigning, claFields []models.CLAField, esig *models.CorpESignature) (string, error) {
+	return this.genSignedPDF(linkID, orgSignatureFile, claFile, orgInfo, signing, claFields, esig)
+}
+
+func (this *pdfGenerator) genSignedPDF(linkID, orgSignatureFile, claFile string, orgInfo *models.OrgInfo, signing *models.CorporationSigning, claFields []models.CLAField, esig *models.CorpESignature) (string, error) {
+	tmpl, merr := models.GetCorpPDFTemplate(linkID, strings.ToLower(signing.CLALanguage))
+	if merr != nil {
+		return "", merr
+	}
+
+	outfile, err := this.genPDF(linkID, orgSignatureFile, claFile, orgInfo, signing, claFields, tmpl, esig)
+	if err != nil || this.signer == nil {
+		return outfile, err
+	}
+
+	if err := this.signer.sign(outfile); err != nil {
+		os.Remove(outfile)
+		return "", err
+	}
+	return outfile, nil
+}
+
+// GenSamplePDFForCorporationSigning generates the pdf with the templates which
+// have not been saved, so that they can be validated before being saved.
+func (this *pdfGenerator) GenSamplePDFForCorporationSigning(linkID, orgSignatureFile, claFile string, orgInfo *models.OrgInfo, signing *models.CorporationSigning, claFields []models.CLAField, tmpl *models.CorpPDFTemplate) (string, error) {
+	return this.genPDF(linkID, orgSignatureFile, claFile, orgInfo, signing, claFields, tmpl, nil)
+}
+
+// CheckCorpSigningPDF checks the uploaded pdf against the one regenerated for the signing,
+// and returns the error code of each item which is unmatched or missing. The pdf printed,
+// signed and scanned by the corporation has no meta, so only the unmatched items mean
+// it is not the pdf of signing. The page count is only checked when the local cla is
+// the one signed by the corporation.
+func (this *pdfGenerator) CheckCorpSigningPDF(data []byte, linkID, claFile string, orgInfo *models.OrgInfo, signing *models.CorporationSigning, claFields []models.CLAField) (map[string]string, error) {
+	rev, err := this.signedRevision(data)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := parsePDF(rev)
+	if err != nil {
+		return nil, err
+	}
+
+	r := map[string]string{}
+
+	if meta := f.corpPDFMeta(); meta == nil {
+		r[PDFItemMeta] = PDFErrMissing
+	} else {
+		if meta.CLAHash != signing.CLAHash {
+			r[PDFItemCLAHash] = PDFErrUnmatched
+		}
+		if meta.CorporationName != signing.CorporationName {
+			r[PDFItemCorpName] = PDFErrUnmatched
+		}
+		if !strings.EqualFold(meta.AdminEmail, signing.AdminEmail) {
+			r[PDFItemAdminEmail] = PDFErrUnmatched
+		}
+		if meta.AgreementID != signing.AgreementID {
+			r[PDFItemAgreementID] = PDFErrUnmatched
+		}
+	}
+
+	if md5, err := util.Md5sumOfFile(claFile); err != nil || md5 != signing.CLAHash {
+		return r, nil
+	}
+
+	n, err := this.pageCountOfCorpPDF(linkID, claFile, orgInfo, signing, claFields)
+	if err != nil {
+		return nil, err
+	}
+
+	if v, err := f.pageCount(); err != nil {
+		r[PDFItemPageCount] = PDFErrMissing
+	} else if v != n {
+		r[PDFItemPageCount] = PDFErrUnmatched
+	}
+
+	return r, nil
+}
+
+// pageCountOfCorpPDF regenerates the pdf without the org signature page, which
+// doesn't change the page count, and returns the page count of it.
+func (this *pdfGenerator) pageCountOfCorpPDF(linkID, claFile string, orgInfo *models.OrgInfo, signing *models.CorporationSigning, claFields []models.CLAField) (int, error) {
+	corp := this.generator(signing.CLALanguage)
+	if corp == nil {
+		return 0, fmt.Errorf("unknown cla language:%s", signing.CLALanguage)
+	}
+
+	tmpl, merr := models.GetCorpPDFTemplate(linkID, strings.ToLower(signing.CLALanguage))
+	if merr != nil {
+		return 0, merr
+	}
+
+	tmpls, err := corp.templates(tmpl)
+	if err != nil {
+		return 0, err
+	}
+
+	outfile := util.GenFilePath(this.pdfOutDir, genPDFFileName(linkID, signing.AdminEmail, "_check"))
+	if err := genCorporPDF(corp, tmpls, orgInfo, signing, claFields, nil, claFile, "", outfile); err != nil {
+		return 0, err
+	}
+	defer os.Remove(outfile)
+
+	data, err := ioutil.ReadFile(outfile)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := parsePDF(data)
+	if err != nil {
+		return 0, err
+	}
+	return f.pageCount()
+}
+
+// signedRevision returns the revision of pdf signed by the community, so that the meta
+// and pages can't be replaced by the incremental update appended after it. The whole pdf
+// is returned if it is not signed, such as the scanned one.
+func (this *pdfGenerator) signedRevision(data []byte) ([]byte, error) {
+	if this.signer == nil {
+		return data, nil
+	}
+
+	n, err := this.signer.signedLen(data)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return data, nil
+	}
+	return data[:n], nil
+}
+
+func (this *pdfGenerator) VerifySignature(data []byte) error {
+	if this.signer == nil {
+		return nil
+	}
+	return this.signer.verify(data)
+}
+
+// genPDF generates the pdf with the org signature page merged natively.
+// If it fails and python_bin is set, the pdf is merged by the python script as fallback.
+func (this *pdfGenerator) genPDF(linkID, orgSignatureFile, claFile string, orgInfo *models.OrgInfo, signing *models.CorporationSigning, claFields []models.CLAField, tmpl *models.CorpPDFTemplate, esig *models.CorpESignature) (string, error) {
 	corp := this.generator(signing.CLALanguage)
 	if corp == nil {
 		return "", fmt.Errorf("unknown cla language:%s", signing.CLALanguage)
 	}
 
-	tempPdf := util.GenFilePath(this.pdfOutDir, genPDFFileName(linkID, signing.AdminEmail, "_missing_sig"))
-	err := genCorporPDFMissingSig(corp, orgInfo, signing, claFields, claFile, tempPdf)
+	tmpls, err := corp.templates(tmpl)
 	if err != nil {
 		return "", err
 	}
-	defer os.Remove(tempPdf)
+
+	if util.IsFileNotExist(orgSignatureFile) {
+		return "", fmt.Errorf("org signature file(%s) is not exist", orgSignatureFile)
+	}
 
 	outfile := util.GenFilePath(this.pdfOutDir, genPDFFileName(linkID, signing.AdminEmail, ""))
+
+	err = genCorporPDF(corp, tmpls, orgInfo, signing, claFields, esig, claFile, orgSignatureFile, outfile)
+	if err == nil {
+		return outfile, nil
+	}
+	if this.pythonBin == "" {
+		return "", err
+	}
+
+	beego.Warning(fmt.Sprintf("Failed to merge the org signature page natively, try python. err: %s", err.Error()))
+
+	tempPdf := util.GenFilePath(this.pdfOutDir, genPDFFileName(linkID, signing.AdminEmail, "_missing_sig"))
+	if err := genCorporPDF(corp, tmpls, orgInfo, signing, claFields, esig, claFile, "", tempPdf); err != nil {
+		return "", err
+	}
+	defer os.Remove(tempPdf)
+
 	if err := mergeCorporPDFSignaturePage(this.pythonBin, tempPdf, orgSignatureFile, outfile); err != nil {
 		return "", err
 	}
@@ -61,7 +238,10 @@ func (this *pdfGenerator) GenPDFForCorporationSigning(linkID, orgSignatureFile,
 	return outfile, nil
 }
 
-func genCorporPDFMissingSig(c *corpSigningPDF, orgInfo *models.OrgInfo, signing *models.CorporationSigning, claFields []models.CLAField, claFile, outFile string) error {
+// genCorporPDF generates the pdf of corporation signing. The signature page
+// will miss the org signature if orgSignatureFile is empty, and the signature
+// of corporation is filled if esig is not nil.
+func genCorporPDF(c *corpSigningPDF, tmpls *corpTemplates, orgInfo *models.OrgInfo, signing *models.CorporationSigning, claFields []models.CLAField, esig *models.CorpESignature, claFile, orgSignatureFile, outFile string) error {
 	text, err := ioutil.ReadFile(claFile)
 	if err != nil {
 		return fmt.Errorf("failed to read cla file(%s): %s", claFile, err.Error())
@@ -69,19 +249,42 @@ func genCorporPDFMissingSig(c *corpSigningPDF, orgInfo *models.OrgInfo, signing
 
 	pdf := c.begin()
 
+	meta, err := json.Marshal(corpPDFMeta{
+		CLAHash:         signing.CLAHash,
+		CorporationName: signing.CorporationName,
+		AdminEmail:      signing.AdminEmail,
+		AgreementID:     signing.AgreementID,
+	})
+	if err != nil {
+		return err
+	}
+	pdf.SetKeywords(string(meta), true)
+
+	var orgSig *orgSignaturePage
+	if orgSignatureFile != "" {
+		if orgSig, err = importOrgSignaturePage(pdf, orgSignatureFile); err != nil {
+			return err
+		}
+	}
+
+	data := &templateData{Org: orgInfo.OrgAlias, Email: orgInfo.OrgEmail}
+
 	// first page
-	c.firstPage(pdf, orgInfo.OrgAlias)
-	c.welcome(pdf, orgInfo.OrgAlias, orgInfo.OrgEmail)
+	c.firstPage(pdf, orgInfo.OrgAlias, tmpls)
+	c.welcome(pdf, tmpls.welcome, data)
 
 	orders, titles := BuildCorpContact(claFields)
 	c.contact(pdf, signing.Info, orders, titles)
 
-	c.declare(pdf)
+	c.declare(pdf, tmpls.declaration, data)
 	c.cla(pdf, string(text))
 	c.projectURL(pdf, fmt.Sprintf("[1]. %s", orgInfo.ProjectURL()))
 
 	// second page
-	c.secondPage(pdf, signing.Date)
+	c.secondPage(pdf, signing.Date, orgSig, esig)
+	if signing.AgreementID != "" {
+		c.agreement(pdf, signing.AgreementID)
+	}
 
 	if !util.IsFileNotExist(outFile) {
 		os.Remove(outFile)
@@ -93,14 +296,10 @@ func genCorporPDFMissingSig(c *corpSigningPDF, orgInfo *models.OrgInfo, signing
 }
 
 func mergeCorporPDFSignaturePage(pythonBin, pdfFile, sigFile, outfile string) error {
-	if util.IsFileNotExist(sigFile) {
-		return fmt.Errorf("org signature file(%s) is not exist", sigFile)
-	}
-
 	// merge file
 	cmd := exec.Command(pythonBin, "./util/merge-signature.py", pdfFile, sigFile, outfile)
-	if _, err := cmd.Output(); err != nil {
-		return fmt.Errorf("merge signature page of pdf failed: %s", err.Error())
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("merge signature page of pdf failed: %s, output: %s", err.Error(), string(out))
 	}
 
 	return nil
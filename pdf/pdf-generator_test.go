@@ -0,0 +1,160 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/opensourceways/gofpdf"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/models"
+)
+
+func newTestCorpSigning(corp, email string) *models.CorporationSigning {
+	return &models.CorporationSigning{
+		CorporationSigningBasicInfo: dbmodels.CorporationSigningBasicInfo{
+			CLALanguage:     "english",
+			CLAHash:         "hash",
+			AdminEmail:      email,
+			CorporationName: corp,
+			AgreementID:     "agreement-" + corp,
+		},
+	}
+}
+
+func testCorpPDFMeta(t *testing.T, signing *models.CorporationSigning) string {
+	meta, err := json.Marshal(corpPDFMeta{
+		CLAHash:         signing.CLAHash,
+		CorporationName: signing.CorporationName,
+		AdminEmail:      signing.AdminEmail,
+		AgreementID:     signing.AgreementID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(meta)
+}
+
+func newTestCorpPDF(t *testing.T, signing *models.CorporationSigning) []byte {
+	f := gofpdf.New("P", "mm", "A4", "")
+	f.SetKeywords(testCorpPDFMeta(t, signing), true)
+	f.SetFont("Helvetica", "", 12)
+	f.AddPage()
+	f.Cell(40, 10, signing.CorporationName)
+
+	buf := new(bytes.Buffer)
+	if err := f.Output(buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// appendInfoRevision replaces the info of pdf by an incremental update.
+func appendInfoRevision(t *testing.T, data []byte, signing *models.CorporationSigning) []byte {
+	tr, err := parseTrailer(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keywords := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(testCorpPDFMeta(t, signing))
+	obj := fmt.Sprintf("%d 0 obj\n<< /Keywords (%s) >>\nendobj\n", tr.size, keywords)
+	update := fmt.Sprintf(
+		"%sxref\n%d 1\n%010d 00000 n \ntrailer\n<< /Size %d /Root %s R /Info %d 0 R /Prev %d >>\nstartxref\n%d\n%%%%EOF\n",
+		obj, tr.size, len(data), tr.size+1, tr.root, tr.size, tr.startXref, len(data)+len(obj),
+	)
+
+	return append(append([]byte{}, data...), update...)
+}
+
+func checkTestCorpPDF(t *testing.T, g *pdfGenerator, data []byte, signing *models.CorporationSigning) map[string]string {
+	// the page count is not checked, because the cla file doesn't exist.
+	r, err := g.CheckCorpSigningPDF(data, "link", "nonexistent-cla.pdf", nil, signing, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestCheckCorpSigningPDF(t *testing.T) {
+	a := newTestCorpSigning("corp-a", "admin@a.com")
+	b := newTestCorpSigning("corp-b", "admin@b.com")
+
+	s := newTestSigner(t)
+	signed, err := s.signPDF(newTestCorpPDF(t, a))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		g    *pdfGenerator
+		data []byte
+	}{
+		{"signed", &pdfGenerator{signer: s}, signed},
+		{"unsigned", &pdfGenerator{}, newTestCorpPDF(t, a)},
+	}
+
+	for _, c := range cases {
+		if r := checkTestCorpPDF(t, c.g, c.data, a); len(r) != 0 {
+			t.Fatalf("%s: the pdf of the signing should match, got %v", c.name, r)
+		}
+
+		r := checkTestCorpPDF(t, c.g, c.data, b)
+		if r[PDFItemCorpName] != PDFErrUnmatched || r[PDFItemAdminEmail] != PDFErrUnmatched {
+			t.Fatalf("%s: the pdf of the other signing should not match, got %v", c.name, r)
+		}
+	}
+}
+
+func TestCheckCorpSigningPDFReadsSignedRevision(t *testing.T) {
+	a := newTestCorpSigning("corp-a", "admin@a.com")
+	b := newTestCorpSigning("corp-b", "admin@b.com")
+
+	s := newTestSigner(t)
+	signed, err := s.signPDF(newTestCorpPDF(t, a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := appendInfoRevision(t, signed, b)
+
+	// the meta of the appended revision is the one of signing b.
+	f, err := parsePDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta := f.corpPDFMeta(); meta == nil || meta.CorporationName != b.CorporationName {
+		t.Fatal("the revision is not appended as expected")
+	}
+
+	g := &pdfGenerator{signer: s}
+
+	r := checkTestCorpPDF(t, g, data, b)
+	if r[PDFItemCorpName] != PDFErrUnmatched {
+		t.Fatalf("the meta should be read from the signed revision, got %v", r)
+	}
+
+	if err := g.VerifySignature(data); err == nil {
+		t.Fatal("the info replaced after signing should be rejected")
+	}
+}
+
+func TestCheckScannedCorpSigningPDF(t *testing.T) {
+	a := newTestCorpSigning("corp-a", "admin@a.com")
+
+	// the scanned pdf has no meta and signature.
+	data := newTestPDF(t, 2)
+
+	for _, g := range []*pdfGenerator{{signer: newTestSigner(t)}, {}} {
+		if err := g.VerifySignature(data); err != nil {
+			t.Fatalf("the scanned pdf should be left to the other checks, got %v", err)
+		}
+
+		r := checkTestCorpPDF(t, g, data, a)
+		if len(r) != 1 || r[PDFItemMeta] != PDFErrMissing {
+			t.Fatalf("expect only the missing meta, got %v", r)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+var (
+	reObjHead = regexp.MustCompile(`(?:^|[^0-9])(\d+)\s+\d+\s+obj\b`)
+	reName    = regexp.MustCompile(`/([^\s/<>\[\]()%{}]+)`)
+	reRootRef = regexp.MustCompile(`/Root\s+(\d+)\s+\d+\s+R`)
+	reInfoRef = regexp.MustCompile(`/Info\s+(\d+)\s+\d+\s+R`)
+	rePages   = regexp.MustCompile(`/Pages\s+(\d+)\s+\d+\s+R`)
+	reCount   = regexp.MustCompile(`/Count\s+(\d+)`)
+	reObjStm  = regexp.MustCompile(`/Type\s*/ObjStm\b`)
+	reFirst   = regexp.MustCompile(`/First\s+(\d+)`)
+	// reEmptyNameTree matches the empty name tree, such as the one of embedded files
+	// which is always written by gofpdf.
+	reEmptyNameTree = regexp.MustCompile(`^\s*<<\s*/Names\s*\[\s*\]\s*>>`)
+)
+
+// activeContentNames are the names of pdf which can run or carry something
+// when the pdf is opened, such as the scripts, the actions launching a
+// program and the embedded files.
+var activeContentNames = map[string]bool{
+	"JavaScript":    true,
+	"JS":            true,
+	"Launch":        true,
+	"EmbeddedFile":  true,
+	"EmbeddedFiles": true,
+	"RichMedia":     true,
+	"SubmitForm":    true,
+	"ImportData":    true,
+	"GoToE":         true,
+}
+
+// corpPDFMeta is saved as the keywords of the pdf of corporation signing,
+// so that the uploaded pdf can be checked against the signing.
+type corpPDFMeta struct {
+	CLAHash         string `json:"cla_hash"`
+	CorporationName string `json:"corporation_name"`
+	AdminEmail      string `json:"admin_email"`
+	AgreementID     string `json:"agreement_id,omitempty"`
+}
+
+// pdfFile is the pdf parsed loosely. It only parses what is needed to check the
+// uploaded pdf, and the objects in the object streams are included.
+type pdfFile struct {
+	data []byte
+	// objects are the dictionaries of objects keyed by the object number.
+	// The later one overrides the earlier one when the pdf is updated incrementally.
+	objects map[int]string
+}
+
+func parsePDF(data []byte) (*pdfFile, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return nil, fmt.Errorf("not a pdf")
+	}
+
+	f := &pdfFile{data: data, objects: map[int]string{}}
+	if err := f.parseObjects(data); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// parseObjects parses the objects in data, which is the whole pdf or an incremental update of it.
+func (this *pdfFile) parseObjects(data []byte) error {
+	heads := reObjHead.FindAllSubmatchIndex(data, -1)
+	if len(heads) == 0 {
+		return fmt.Errorf("no object in the pdf")
+	}
+
+	for i, h := range heads {
+		num, _ := strconv.Atoi(string(data[h[2]:h[3]]))
+
+		end := len(data)
+		if i+1 < len(heads) {
+			end = heads[i+1][0]
+		}
+		body := data[h[1]:end]
+		if j := bytes.LastIndex(body, []byte("endobj")); j >= 0 {
+			body = body[:j]
+		}
+
+		dict, stream := splitStream(body)
+		this.objects[num] = dict
+
+		if reObjStm.MatchString(dict) {
+			if err := this.parseObjStm(dict, stream); err != nil {
+				return fmt.Errorf("invalid object stream %d: %s", num, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitStream splits the object to the dictionary and the data of stream if there is.
+func splitStream(body []byte) (string, []byte) {
+	i := bytes.Index(body, []byte("stream"))
+	if i < 0 {
+		return string(body), nil
+	}
+
+	stream := body[i+len("stream"):]
+	if bytes.HasPrefix(stream, []byte("\r\n")) {
+		stream = stream[2:]
+	} else if bytes.HasPrefix(stream, []byte("\n")) {
+		stream = stream[1:]
+	}
+	if j := bytes.LastIndex(stream, []byte("endstream")); j >= 0 {
+		stream = stream[:j]
+	}
+
+	return string(body[:i]), stream
+}
+
+func (this *pdfFile) parseObjStm(dict string, stream []byte) error {
+	if strings.Contains(dict, "/FlateDecode") {
+		r, err := zlib.NewReader(bytes.NewReader(stream))
+		if err != nil {
+			return err
+		}
+		// the stream may have the trailing end of line, so the error of unexpected EOF is ignored.
+		v, err := ioutil.ReadAll(r)
+		if len(v) == 0 && err != nil {
+			return err
+		}
+		stream = v
+	} else if strings.Contains(dict, "/Filter") {
+		return fmt.Errorf("unsupported filter")
+	}
+
+	m := reFirst.FindStringSubmatch(dict)
+	if m == nil {
+		return fmt.Errorf("missing /First")
+	}
+	first, _ := strconv.Atoi(m[1])
+	if first > len(stream) {
+		return fmt.Errorf("invalid /First")
+	}
+
+	header := strings.Fields(string(stream[:first]))
+	if len(header)%2 != 0 {
+		return fmt.Errorf("invalid header")
+	}
+
+	n := len(header) / 2
+	for i := 0; i < n; i++ {
+		num, err1 := strconv.Atoi(header[2*i])
+		start, err2 := strconv.Atoi(header[2*i+1])
+		if err1 != nil || err2 != nil || first+start > len(stream) {
+			return fmt.Errorf("invalid header")
+		}
+
+		end := len(stream)
+		if i+1 < n {
+			if v, err := strconv.Atoi(header[2*i+3]); err == nil && first+v <= len(stream) && v >= start {
+				end = first + v
+			}
+		}
+
+		this.objects[num] = string(stream[first+start : end])
+	}
+
+	return nil
+}
+
+// activeContent returns the names of active content found in the dictionaries.
+func (this *pdfFile) activeContent() []string {
+	found := map[string]bool{}
+	for _, dict := range this.objects {
+		for _, m := range reName.FindAllStringSubmatchIndex(dict, -1) {
+			name := decodeName(dict[m[2]:m[3]])
+			if !activeContentNames[name] {
+				continue
+			}
+			if name == "EmbeddedFiles" && reEmptyNameTree.MatchString(dict[m[1]:]) {
+				continue
+			}
+			found[name] = true
+		}
+	}
+
+	r := make([]string, 0, len(found))
+	for k := range found {
+		r = append(r, k)
+	}
+	return r
+}
+
+// decodeName decodes the #xx in the name, such as /J#61vaScript.
+func decodeName(s string) string {
+	if !strings.Contains(s, "#") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '#' && i+2 < len(s) {
+			if v, err := hex.DecodeString(s[i+1 : i+3]); err == nil {
+				b.WriteByte(v[0])
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// lastRef returns the object number of the last reference matched by re, which
+// is the one of the latest trailer when the pdf is updated incrementally.
+func (this *pdfFile) lastRef(re *regexp.Regexp) (int, bool) {
+	v := re.FindAllSubmatch(this.data, -1)
+	if len(v) == 0 {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(string(v[len(v)-1][1]))
+	return n, err == nil
+}
+
+func (this *pdfFile) pageCount() (int, error) {
+	root, ok := this.lastRef(reRootRef)
+	if !ok {
+		return 0, fmt.Errorf("missing the catalog")
+	}
+
+	m := rePages.FindStringSubmatch(this.objects[root])
+	if m == nil {
+		return 0, fmt.Errorf("missing the pages")
+	}
+	pages, _ := strconv.Atoi(m[1])
+
+	m = reCount.FindStringSubmatch(this.objects[pages])
+	if m == nil {
+		return 0, fmt.Errorf("missing the count of pages")
+	}
+	return strconv.Atoi(m[1])
+}
+
+// corpPDFMeta returns nil if the pdf has no meta of corporation signing.
+func (this *pdfFile) corpPDFMeta() *corpPDFMeta {
+	info, ok := this.lastRef(reInfoRef)
+	if !ok {
+		return nil
+	}
+
+	s, ok := dictString(this.objects[info], "/Keywords")
+	if !ok {
+		return nil
+	}
+
+	var v corpPDFMeta
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil
+	}
+	return &v
+}
+
+// dictString returns the value of key which is a literal or hexadecimal string.
+// The string which begins with the byte order mark is decoded from UTF-16BE.
+func dictString(dict, key string) (string, bool) {
+	i := strings.Index(dict, key)
+	if i < 0 {
+		return "", false
+	}
+
+	s := strings.TrimLeft(dict[i+len(key):], " \t\r\n")
+	if s == "" {
+		return "", false
+	}
+
+	var v []byte
+	switch s[0] {
+	case '(':
+		b, ok := literalString(s)
+		if !ok {
+			return "", false
+		}
+		v = b
+
+	case '<':
+		j := strings.IndexByte(s, '>')
+		if j < 0 {
+			return "", false
+		}
+		h := strings.Join(strings.Fields(s[1:j]), "")
+		if len(h)%2 != 0 {
+			h += "0"
+		}
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			return "", false
+		}
+		v = b
+
+	default:
+		return "", false
+	}
+
+	if len(v) >= 2 && v[0] == 0xfe && v[1] == 0xff {
+		u := make([]uint16, 0, len(v)/2)
+		for k := 2; k+1 < len(v); k += 2 {
+			u = append(u, uint16(v[k])<<8|uint16(v[k+1]))
+		}
+		return string(utf16.Decode(u)), true
+	}
+	return string(v), true
+}
+
+// literalString parses the literal string which begins with '(' and ends with the balanced ')'.
+func literalString(s string) ([]byte, bool) {
+	var b []byte
+	depth := 0
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '\\':
+			i++
+			if i >= len(s) {
+				return nil, false
+			}
+			switch e := s[i]; e {
+			case 'n':
+				b = append(b, '\n')
+			case 'r':
+				b = append(b, '\r')
+			case 't':
+				b = append(b, '\t')
+			case 'b':
+				b = append(b, '\b')
+			case 'f':
+				b = append(b, '\f')
+			case '\r', '\n':
+				// line continuation
+				if e == '\r' && i+1 < len(s) && s[i+1] == '\n' {
+					i++
+				}
+			default:
+				if e >= '0' && e <= '7' {
+					n := 0
+					j := i
+					for ; j < len(s) && j < i+3 && s[j] >= '0' && s[j] <= '7'; j++ {
+						n = n*8 + int(s[j]-'0')
+					}
+					b = append(b, byte(n))
+					i = j - 1
+				} else {
+					b = append(b, e)
+				}
+			}
+
+		case '(':
+			if depth > 0 {
+				b = append(b, c)
+			}
+			depth++
+
+		case ')':
+			depth--
+			if depth == 0 {
+				return b, true
+			}
+			b = append(b, c)
+
+		default:
+			b = append(b, c)
+		}
+	}
+
+	return nil, false
+}
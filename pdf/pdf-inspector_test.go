@@ -0,0 +1,98 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeName(t *testing.T) {
+	cases := map[string]string{
+		"JavaScript":   "JavaScript",
+		"J#61vaScript": "JavaScript",
+		"#4A#53":       "JS",
+		"Name#2":       "Name#2",
+		"Name#zz":      "Name#zz",
+	}
+
+	for k, v := range cases {
+		if r := decodeName(k); r != v {
+			t.Errorf("decodeName(%s): expect %s, got %s", k, v, r)
+		}
+	}
+}
+
+// newObjStmPDF returns the pdf whose objects are in a compressed object stream.
+func newObjStmPDF(t *testing.T, objs map[int]string, order []int) []byte {
+	header := ""
+	body := ""
+	for _, num := range order {
+		header += fmt.Sprintf("%d %d ", num, len(body))
+		body += objs[num] + "\n"
+	}
+
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write([]byte(header + body))
+	w.Close()
+
+	v := fmt.Sprintf(
+		"%%PDF-1.5\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n"+
+			"5 0 obj\n<< /Type /ObjStm /N %d /First %d /Filter /FlateDecode /Length %d >>\nstream\n",
+		len(order), len(header), buf.Len(),
+	)
+
+	return append(append([]byte(v), buf.Bytes()...), "\nendstream\nendobj\ntrailer\n<< /Root 1 0 R >>\n%%EOF\n"...)
+}
+
+func TestParseObjectStream(t *testing.T) {
+	objs := map[int]string{
+		2: "<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		3: "<< /Type /Page /Parent 2 0 R >>",
+	}
+	data := newObjStmPDF(t, objs, []int{2, 3})
+
+	f, err := parsePDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for num, v := range objs {
+		if got := f.objects[num]; got != v+"\n" {
+			t.Errorf("object %d: expect %q, got %q", num, v+"\n", got)
+		}
+	}
+
+	if n, err := f.pageCount(); err != nil || n != 1 {
+		t.Fatalf("expect 1 page, got %d, %v", n, err)
+	}
+}
+
+func TestFindActiveContentInObjectStream(t *testing.T) {
+	objs := map[int]string{
+		2: "<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		3: "<< /Type /Page /Parent 2 0 R /AA << /O 4 0 R >> >>",
+		4: "<< /S /J#61vaScript /JS (app.alert(1)) >>",
+	}
+	data := newObjStmPDF(t, objs, []int{2, 3, 4})
+
+	v, err := FindActiveContent(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := []string{"JS", "JavaScript"}; !reflect.DeepEqual(v, expect) {
+		t.Fatalf("expect %v, got %v", expect, v)
+	}
+}
+
+func TestFindActiveContentOfPlainPDF(t *testing.T) {
+	v, err := FindActiveContent(newTestPDF(t, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v) != 0 {
+		t.Fatalf("expect no active content, got %v", v)
+	}
+}
@@ -0,0 +1,270 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	reTypeCatalog = regexp.MustCompile(`/Type\s*/Catalog\b`)
+	reTypePage    = regexp.MustCompile(`/Type\s*/Page\b`)
+	reAnnot       = regexp.MustCompile(`/Type\s*/Annot\b|/Subtype\s*/Widget\b`)
+	reAcroFormRef = regexp.MustCompile(`/AcroForm\s+(\d+)\s+\d+\s+R`)
+	reAnnotsRef   = regexp.MustCompile(`/Annots\s+(\d+)\s+\d+\s+R`)
+	reFieldsRef   = regexp.MustCompile(`/Fields\s+(\d+)\s+\d+\s+R`)
+	reRefTail     = regexp.MustCompile(`^\s+\d+\s+R\b`)
+)
+
+// The keys of catalog and page which are changed when a signature or an annotation is added.
+var (
+	catalogKeysOfSigning = map[string]bool{"AcroForm": true, "Perms": true, "DSS": true, "Extensions": true}
+	pageKeysOfSigning    = map[string]bool{"Annots": true}
+)
+
+// isEndOfRevision checks whether data ends with the end of a revision.
+func isEndOfRevision(data []byte) bool {
+	return bytes.HasSuffix(bytes.TrimRight(data, "\r\n"), []byte("%%EOF"))
+}
+
+// checkIncrementalUpdate checks the update appended to the signed revision, which
+// can only add signatures and annotations, such as the countersignature of corporation.
+// The new objects are allowed, because they take effect only when they are referred
+// to by the existing objects which are checked.
+func checkIncrementalUpdate(signed, update []byte) error {
+	if len(bytes.TrimSpace(update)) == 0 {
+		return nil
+	}
+
+	f, err := parsePDF(signed)
+	if err != nil {
+		return err
+	}
+
+	u := &pdfFile{data: update, objects: map[int]string{}}
+	if err := u.parseObjects(update); err != nil {
+		return err
+	}
+
+	for _, re := range []*regexp.Regexp{reRootRef, reInfoRef} {
+		old, _ := f.lastRef(re)
+		for _, m := range re.FindAllSubmatch(update, -1) {
+			if n, _ := strconv.Atoi(string(m[1])); n != old {
+				return fmt.Errorf("the catalog or the info is replaced after being signed")
+			}
+		}
+	}
+
+	mutable := f.mutableObjects()
+
+	for num, dict := range u.objects {
+		old, ok := f.objects[num]
+		if !ok || mutable[num] {
+			continue
+		}
+
+		switch {
+		case reTypeCatalog.MatchString(old):
+			ok = sameDictExcept(old, dict, catalogKeysOfSigning)
+		case reTypePage.MatchString(old):
+			ok = sameDictExcept(old, dict, pageKeysOfSigning)
+		default:
+			ok = false
+		}
+
+		if !ok {
+			return fmt.Errorf("the object %d is changed after being signed", num)
+		}
+	}
+
+	return nil
+}
+
+// mutableObjects returns the objects which can be replaced when a signature or an
+// annotation is added, which are the annotations, the form and the arrays of them.
+func (this *pdfFile) mutableObjects() map[int]bool {
+	r := map[int]bool{}
+
+	ref := func(re *regexp.Regexp, dict string) {
+		if m := re.FindStringSubmatch(dict); m != nil {
+			if n, err := strconv.Atoi(m[1]); err == nil {
+				r[n] = true
+			}
+		}
+	}
+
+	for num, dict := range this.objects {
+		switch {
+		case reAnnot.MatchString(dict):
+			r[num] = true
+		case reTypeCatalog.MatchString(dict):
+			ref(reAcroFormRef, dict)
+		case reTypePage.MatchString(dict):
+			ref(reAnnotsRef, dict)
+		}
+	}
+
+	if root, ok := this.lastRef(reRootRef); ok {
+		if m := reAcroFormRef.FindStringSubmatch(this.objects[root]); m != nil {
+			form, _ := strconv.Atoi(m[1])
+			ref(reFieldsRef, this.objects[form])
+		}
+	}
+
+	return r
+}
+
+// sameDictExcept checks whether the dictionaries are the same except the keys.
+func sameDictExcept(a, b string, keys map[string]bool) bool {
+	va, err := dictEntries(a)
+	if err != nil {
+		return false
+	}
+	vb, err := dictEntries(b)
+	if err != nil {
+		return false
+	}
+
+	for k := range keys {
+		delete(va, k)
+		delete(vb, k)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// dictEntries splits the dictionary into the values keyed by the names. The white
+// spaces of values are normalized, so that the dictionaries can be compared.
+func dictEntries(dict string) (map[string]string, error) {
+	s := strings.TrimSpace(dict)
+	if !strings.HasPrefix(s, "<<") || !strings.HasSuffix(s, ">>") {
+		return nil, fmt.Errorf("not a dictionary")
+	}
+	s = s[2 : len(s)-2]
+
+	r := map[string]string{}
+	for i := skipSpace(s, 0); i < len(s); i = skipSpace(s, i) {
+		if s[i] != '/' {
+			return nil, fmt.Errorf("invalid key of dictionary")
+		}
+
+		j := endOfToken(s, i+1)
+		k, err := endOfObject(s, j)
+		if err != nil {
+			return nil, err
+		}
+
+		r[decodeName(s[i+1:j])] = strings.Join(strings.Fields(s[j:k]), " ")
+		i = k
+	}
+
+	return r, nil
+}
+
+// endOfObject returns the end of the object which begins at i.
+func endOfObject(s string, i int) (int, error) {
+	i = skipSpace(s, i)
+	if i >= len(s) {
+		return 0, fmt.Errorf("missing object")
+	}
+
+	switch {
+	case strings.HasPrefix(s[i:], "<<"):
+		return endOfContainer(s, i+2, ">>")
+
+	case s[i] == '[':
+		return endOfContainer(s, i+1, "]")
+
+	case s[i] == '(':
+		return endOfLiteral(s, i)
+
+	case s[i] == '<':
+		if j := strings.IndexByte(s[i:], '>'); j > 0 {
+			return i + j + 1, nil
+		}
+		return 0, fmt.Errorf("invalid hexadecimal string")
+
+	case s[i] == '/':
+		return endOfToken(s, i+1), nil
+	}
+
+	j := endOfToken(s, i)
+	if j == i {
+		return 0, fmt.Errorf("unexpected delimiter: %c", s[i])
+	}
+
+	// the indirect reference, such as 1 0 R
+	if m := reRefTail.FindStringIndex(s[j:]); m != nil {
+		j += m[1]
+	}
+	return j, nil
+}
+
+func endOfContainer(s string, i int, end string) (int, error) {
+	for {
+		i = skipSpace(s, i)
+		if i >= len(s) {
+			return 0, fmt.Errorf("missing %s", end)
+		}
+		if strings.HasPrefix(s[i:], end) {
+			return i + len(end), nil
+		}
+
+		j, err := endOfObject(s, i)
+		if err != nil {
+			return 0, err
+		}
+		i = j
+	}
+}
+
+// endOfLiteral returns the end of the literal string which may have the escaped
+// and the balanced parentheses.
+func endOfLiteral(s string, i int) (int, error) {
+	depth := 0
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i + 1, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid literal string")
+}
+
+func endOfToken(s string, i int) int {
+	for ; i < len(s); i++ {
+		if isSpace(s[i]) || strings.IndexByte("()<>[]{}/%", s[i]) >= 0 {
+			break
+		}
+	}
+	return i
+}
+
+// skipSpace skips the white spaces and the comments.
+func skipSpace(s string, i int) int {
+	for i < len(s) {
+		if isSpace(s[i]) {
+			i++
+			continue
+		}
+		if s[i] != '%' {
+			break
+		}
+		for i < len(s) && s[i] != '\n' && s[i] != '\r' {
+			i++
+		}
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == 0
+}
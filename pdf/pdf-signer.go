@@ -0,0 +1,425 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	reStartXref = regexp.MustCompile(`startxref\s+(\d+)`)
+	reSize      = regexp.MustCompile(`/Size\s+(\d+)`)
+	reRoot      = regexp.MustCompile(`/Root\s+(\d+\s+\d+)\s+R`)
+	reInfo      = regexp.MustCompile(`/Info\s+(\d+\s+\d+)\s+R`)
+	reID        = regexp.MustCompile(`/ID\s*\[[^\]]*\]`)
+	reByteRange = regexp.MustCompile(`/ByteRange\s*\[\s*(\d+)\s+(\d+)\s+(\d+)\s+(\d+)\s*\]`)
+	rePagesRef  = regexp.MustCompile(`/Pages\s+(\d+\s+\d+)\s+R`)
+	reKidsRef   = regexp.MustCompile(`/Kids\s*\[\s*(\d+\s+\d+)\s+R`)
+	reAnnots    = regexp.MustCompile(`/Annots\s*\[`)
+)
+
+// the width of the value of /ByteRange which is filled after the pdf is written.
+const byteRangeWidth = 48
+
+// pdfSigner signs the pdf with a PAdES signature which is appended as an incremental
+// update, and verifies the signature signed by itself.
+type pdfSigner struct {
+	key crypto.Signer
+	// certs[0] is the certificate of signer, and the others are the chain of it.
+	certs []*x509.Certificate
+}
+
+func newPDFSigner(certFile, keyFile string) (*pdfSigner, error) {
+	certs, err := loadCertificates(certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := loadPrivateKey(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(pub, certs[0].RawSubjectPublicKeyInfo) {
+		return nil, fmt.Errorf("the private key does not match the certificate")
+	}
+
+	return &pdfSigner{key: key, certs: certs}, nil
+}
+
+func loadCertificates(file string) ([]*x509.Certificate, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		if block, data = pem.Decode(data); block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in %s: %s", file, err.Error())
+		}
+		certs = append(certs, c)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate in %s", file)
+	}
+	return certs, nil
+}
+
+func loadPrivateKey(file string) (crypto.Signer, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no private key in %s", file)
+	}
+
+	if k, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		switch v := k.(type) {
+		case *rsa.PrivateKey:
+			return v, nil
+		case *ecdsa.PrivateKey:
+			return v, nil
+		}
+		return nil, fmt.Errorf("unsupported private key in %s", file)
+	}
+
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+
+	if k, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+
+	return nil, fmt.Errorf("invalid private key in %s", file)
+}
+
+// sign signs the pdf file in place.
+func (this *pdfSigner) sign(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	v, err := this.signPDF(data)
+	if err != nil {
+		return fmt.Errorf("failed to sign pdf(%s): %s", file, err.Error())
+	}
+
+	return ioutil.WriteFile(file, v, 0644)
+}
+
+// signPDF appends the signature dictionary, the signature field, the first page and
+// the catalog which refer to the field to the pdf, then fills the byte range and the
+// CMS signature of it. It only supports the pdf whose cross-reference is a table,
+// such as the one by gofpdf.
+func (this *pdfSigner) signPDF(data []byte) ([]byte, error) {
+	t, err := parseTrailer(data)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := findObject(data, t.root)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(catalog, "/AcroForm") {
+		return nil, fmt.Errorf("the pdf which has form is not supported")
+	}
+	i := strings.LastIndex(catalog, ">>")
+	if !strings.HasPrefix(catalog, "<<") || i < 0 {
+		return nil, fmt.Errorf("invalid catalog")
+	}
+
+	sigNum := t.size
+	fieldNum := t.size + 1
+
+	pageRef, page, err := firstPage(data, catalog)
+	if err != nil {
+		return nil, err
+	}
+	if page, err = addAnnot(page, fieldNum); err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(data)+8192))
+	buf.Write(data)
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+
+	// signature dictionary
+	sigOffset := buf.Len()
+	fmt.Fprintf(buf, "%d 0 obj\n<<\n/Type /Sig\n/Filter /Adobe.PPKLite\n/SubFilter /ETSI.CAdES.detached\n", sigNum)
+	buf.WriteString("/ByteRange ")
+	byteRangePos := buf.Len()
+	buf.WriteString(strings.Repeat(" ", byteRangeWidth))
+	buf.WriteString("\n/Contents ")
+	contentsPos := buf.Len()
+	contentsLen := 2*this.maxSignatureSize() + 2
+	buf.WriteString("<" + strings.Repeat("0", contentsLen-2) + ">")
+	fmt.Fprintf(buf, "\n/M (%s)\n>>\nendobj\n", time.Now().UTC().Format("D:20060102150405Z"))
+
+	// signature field which is invisible and belongs to the first page
+	fieldOffset := buf.Len()
+	fmt.Fprintf(
+		buf,
+		"%d 0 obj\n<<\n/Type /Annot\n/Subtype /Widget\n/FT /Sig\n/T (Signature1)\n/V %d 0 R\n/P %s R\n/F 132\n/Rect [0 0 0 0]\n>>\nendobj\n",
+		fieldNum, sigNum, pageRef,
+	)
+
+	pageOffset := buf.Len()
+	fmt.Fprintf(buf, "%s obj\n%s\nendobj\n", pageRef, page)
+
+	catalogOffset := buf.Len()
+	fmt.Fprintf(
+		buf, "%s obj\n%s/AcroForm << /Fields [%d 0 R] /SigFlags 3 >>\n>>\nendobj\n",
+		t.root, catalog[:i], fieldNum,
+	)
+
+	xrefOffset := buf.Len()
+	buf.WriteString("xref\n")
+	writeXrefEntry(buf, t.root, catalogOffset)
+	writeXrefEntry(buf, pageRef, pageOffset)
+	fmt.Fprintf(buf, "%d 2\n%010d 00000 n \n%010d 00000 n \n", sigNum, sigOffset, fieldOffset)
+
+	fmt.Fprintf(buf, "trailer\n<<\n/Size %d\n/Root %s R\n", fieldNum+1, t.root)
+	if t.info != "" {
+		fmt.Fprintf(buf, "/Info %s R\n", t.info)
+	}
+	if t.id != "" {
+		fmt.Fprintf(buf, "%s\n", t.id)
+	}
+	fmt.Fprintf(buf, "/Prev %d\n>>\nstartxref\n%d\n%%%%EOF\n", t.startXref, xrefOffset)
+
+	out := buf.Bytes()
+
+	b := contentsPos
+	c := contentsPos + contentsLen
+	byteRange := fmt.Sprintf("[0 %d %d %d]", b, c, len(out)-c)
+	copy(out[byteRangePos:], byteRange)
+
+	h := sha256.New()
+	h.Write(out[:b])
+	h.Write(out[c:])
+
+	sig, err := signCMS(h.Sum(nil), this.key, this.certs)
+	if err != nil {
+		return nil, err
+	}
+
+	s := hex.EncodeToString(sig)
+	if len(s) > contentsLen-2 {
+		return nil, fmt.Errorf("the signature is too big")
+	}
+	copy(out[b+1:], s)
+
+	return out, nil
+}
+
+func (this *pdfSigner) maxSignatureSize() int {
+	n := 0
+	for _, item := range this.certs {
+		n += len(item.Raw)
+	}
+	// the signature, signed attributes and the structure of CMS
+	return n + 4096
+}
+
+// firstPage returns the reference and the content of the first page of the catalog.
+func firstPage(data []byte, catalog string) (string, string, error) {
+	m := rePagesRef.FindStringSubmatch(catalog)
+	if m == nil {
+		return "", "", fmt.Errorf("missing the pages")
+	}
+	ref := strings.Join(strings.Fields(m[1]), " ")
+
+	// the depth of page tree is limited in case of the loop of it.
+	for i := 0; i < 32; i++ {
+		node, err := findObject(data, ref)
+		if err != nil {
+			return "", "", err
+		}
+
+		m := reKidsRef.FindStringSubmatch(node)
+		if m == nil {
+			if !strings.HasPrefix(node, "<<") || !strings.HasSuffix(node, ">>") {
+				return "", "", fmt.Errorf("invalid page: %s", ref)
+			}
+			return ref, node, nil
+		}
+		ref = strings.Join(strings.Fields(m[1]), " ")
+	}
+
+	return "", "", fmt.Errorf("the page tree is too deep")
+}
+
+// addAnnot adds the annotation to the page whose /Annots is absent or an array.
+func addAnnot(page string, annot int) (string, error) {
+	if loc := reAnnots.FindStringIndex(page); loc != nil {
+		return fmt.Sprintf("%s%d 0 R %s", page[:loc[1]], annot, page[loc[1]:]), nil
+	}
+	if strings.Contains(page, "/Annots") {
+		return "", fmt.Errorf("the page whose annotations are an indirect object is not supported")
+	}
+
+	i := strings.LastIndex(page, ">>")
+	return fmt.Sprintf("%s/Annots [%d 0 R]\n>>", page[:i], annot), nil
+}
+
+func writeXrefEntry(buf *bytes.Buffer, ref string, offset int) {
+	v := strings.Fields(ref)
+	gen, _ := strconv.Atoi(v[1])
+	fmt.Fprintf(buf, "%s 1\n%010d %05d n \n", v[0], offset, gen)
+}
+
+// verify checks the signature of the community if the pdf has it, and the pdf which
+// has not, such as the scanned one, is left to the other checks. The signature must
+// cover its own revision, and the later revisions, such as the countersignature
+// of corporation, can only add signatures and annotations.
+func (this *pdfSigner) verify(data []byte) error {
+	n, err := this.signedLen(data)
+	if err != nil || n == 0 {
+		return err
+	}
+	return checkIncrementalUpdate(data[:n], data[n:])
+}
+
+// signedLen returns the length of the revision signed by this signer,
+// or 0 if the pdf has no signature of it.
+func (this *pdfSigner) signedLen(data []byte) (int, error) {
+	for _, m := range reByteRange.FindAllSubmatchIndex(data, -1) {
+		r := make([]int, 4)
+		for i := range r {
+			r[i], _ = strconv.Atoi(string(data[m[2*i+2]:m[2*i+3]]))
+		}
+
+		// the signatures which can't be parsed are not the one of this signer.
+		a, b, c, d := r[0], r[1], r[2], r[3]
+		if a != 0 || b <= a || c <= b+1 || c+d > len(data) || data[b] != '<' || data[c-1] != '>' {
+			continue
+		}
+
+		sig, err := hex.DecodeString(string(data[b+1 : c-1]))
+		if err != nil {
+			continue
+		}
+		if _, cert, err := parseCMS(sig); err != nil || !bytes.Equal(cert.Raw, this.certs[0].Raw) {
+			continue
+		}
+
+		if m[0] >= b {
+			return 0, fmt.Errorf("the byte range is not signed")
+		}
+		if !isEndOfRevision(data[:c+d]) {
+			return 0, fmt.Errorf("the signature doesn't cover its revision")
+		}
+
+		h := sha256.New()
+		h.Write(data[a:b])
+		h.Write(data[c : c+d])
+
+		if _, err := verifyCMS(sig, h.Sum(nil)); err != nil {
+			return 0, err
+		}
+		return c + d, nil
+	}
+
+	return 0, nil
+}
+
+type pdfTrailer struct {
+	size      int
+	root      string
+	info      string
+	id        string
+	startXref int
+}
+
+func parseTrailer(data []byte) (*pdfTrailer, error) {
+	i := bytes.LastIndex(data, []byte("startxref"))
+	if i < 0 {
+		return nil, fmt.Errorf("missing startxref")
+	}
+	m := reStartXref.FindSubmatch(data[i:])
+	if m == nil {
+		return nil, fmt.Errorf("invalid startxref")
+	}
+	startXref, _ := strconv.Atoi(string(m[1]))
+
+	j := bytes.LastIndex(data[:i], []byte("trailer"))
+	if j < 0 {
+		return nil, fmt.Errorf("the pdf whose cross-reference is a stream is not supported")
+	}
+	s := string(data[j:i])
+
+	r := &pdfTrailer{startXref: startXref}
+
+	if m := reSize.FindStringSubmatch(s); m != nil {
+		r.size, _ = strconv.Atoi(m[1])
+	}
+	if m := reRoot.FindStringSubmatch(s); m != nil {
+		r.root = strings.Join(strings.Fields(m[1]), " ")
+	}
+	if r.size <= 0 || r.root == "" {
+		return nil, fmt.Errorf("invalid trailer")
+	}
+
+	if m := reInfo.FindStringSubmatch(s); m != nil {
+		r.info = strings.Join(strings.Fields(m[1]), " ")
+	}
+	r.id = reID.FindString(s)
+
+	return r, nil
+}
+
+// findObject returns the content of the object whose reference is ref, such as "1 0".
+// The last one is returned if it is updated incrementally.
+func findObject(data []byte, ref string) (string, error) {
+	re, err := regexp.Compile(`(^|[^0-9])` + strings.Replace(ref, " ", `\s+`, 1) + `\s+obj`)
+	if err != nil {
+		return "", err
+	}
+
+	v := re.FindAllIndex(data, -1)
+	if len(v) == 0 {
+		return "", fmt.Errorf("missing object: %s", ref)
+	}
+	start := v[len(v)-1][1]
+
+	end := bytes.Index(data[start:], []byte("endobj"))
+	if end < 0 {
+		return "", fmt.Errorf("invalid object: %s", ref)
+	}
+
+	return strings.TrimSpace(string(data[start : start+end])), nil
+}
@@ -0,0 +1,342 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opensourceways/gofpdf"
+)
+
+// newTestSigner returns the signer with a self-signed certificate.
+func newTestSigner(t *testing.T) *pdfSigner {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "cla test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "pdf-signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := newPDFSigner(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func newTestPDF(t *testing.T, pages int) []byte {
+	f := gofpdf.New("P", "mm", "A4", "")
+	f.SetFont("Helvetica", "", 12)
+	for i := 0; i < pages; i++ {
+		f.AddPage()
+		f.Cell(40, 10, fmt.Sprintf("page %d", i+1))
+	}
+
+	buf := new(bytes.Buffer)
+	if err := f.Output(buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func signTestPDF(t *testing.T, s *pdfSigner) []byte {
+	v, err := s.signPDF(newTestPDF(t, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestSignAndVerify(t *testing.T) {
+	s := newTestSigner(t)
+	data := signTestPDF(t, s)
+
+	if err := s.verify(data); err != nil {
+		t.Fatalf("failed to verify the signed pdf: %v", err)
+	}
+
+	// the signed pdf can be parsed and keeps the pages.
+	f, err := parsePDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := f.pageCount(); err != nil || n != 2 {
+		t.Fatalf("expect 2 pages, got %d, %v", n, err)
+	}
+}
+
+func TestSignAttachesWidgetToFirstPage(t *testing.T) {
+	data := signTestPDF(t, newTestSigner(t))
+
+	tr, err := parseTrailer(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog, err := findObject(data, tr.root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pageRef, page, err := firstPage(data, catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fieldRef := fmt.Sprintf("%d 0", tr.size-1)
+	field, err := findObject(data, fieldRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(field, "/P "+pageRef+" R") {
+		t.Fatalf("the widget doesn't refer to the first page %s: %s", pageRef, field)
+	}
+	if !regexp.MustCompile(`/Annots\s*\[[^\]]*\b` + fieldRef + ` R`).MatchString(page) {
+		t.Fatalf("the first page doesn't have the widget: %s", page)
+	}
+	if !strings.Contains(catalog, "/Fields ["+fieldRef+" R]") {
+		t.Fatalf("the catalog doesn't have the field: %s", catalog)
+	}
+}
+
+func TestVerifySkipsPDFNotSignedByCommunity(t *testing.T) {
+	s := newTestSigner(t)
+
+	// the scanned pdf and the one signed by the others are left to the other checks.
+	for _, data := range [][]byte{newTestPDF(t, 1), signTestPDF(t, newTestSigner(t))} {
+		if n, err := s.signedLen(data); err != nil || n != 0 {
+			t.Fatalf("expect no signed revision, got %d, %v", n, err)
+		}
+		if err := s.verify(data); err != nil {
+			t.Fatalf("the pdf not signed by the community should be skipped, got %v", err)
+		}
+	}
+}
+
+func TestVerifyRejectsTamperedPDF(t *testing.T) {
+	s := newTestSigner(t)
+	data := signTestPDF(t, s)
+
+	i := bytes.Index(data, []byte("page 1"))
+	if i < 0 {
+		// the content is compressed, so the header is changed instead.
+		i = 1
+	}
+	data[i] ^= 0x01
+
+	if err := s.verify(data); err == nil {
+		t.Fatal("the tampered pdf should be rejected")
+	}
+}
+
+// appendTestUpdate appends the objects as an incremental update, whose trailer
+// refers to info if it is not empty.
+func appendTestUpdate(t *testing.T, data []byte, info string, objs map[int]string) []byte {
+	tr, err := parseTrailer(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info == "" {
+		info = tr.info
+	}
+
+	buf := bytes.NewBuffer(append([]byte{}, data...))
+	xref := "xref\n"
+	size := tr.size
+	for num := 1; num < tr.size+len(objs); num++ {
+		obj, ok := objs[num]
+		if !ok {
+			continue
+		}
+		xref += fmt.Sprintf("%d 1\n%010d 00000 n \n", num, buf.Len())
+		fmt.Fprintf(buf, "%d 0 obj\n%s\nendobj\n", num, obj)
+		if num >= size {
+			size = num + 1
+		}
+	}
+
+	offset := buf.Len()
+	fmt.Fprintf(
+		buf, "%strailer\n<< /Size %d /Root %s R /Info %s R /Prev %d >>\nstartxref\n%d\n%%%%EOF\n",
+		xref, size, tr.root, info, tr.startXref, offset,
+	)
+	return buf.Bytes()
+}
+
+// testSignedObjects returns the catalog, the first page and the signature field of the signed pdf.
+func testSignedObjects(t *testing.T, data []byte) (tr *pdfTrailer, catalog, pageRef, page string, field int) {
+	tr, err := parseTrailer(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if catalog, err = findObject(data, tr.root); err != nil {
+		t.Fatal(err)
+	}
+	if pageRef, page, err = firstPage(data, catalog); err != nil {
+		t.Fatal(err)
+	}
+	return tr, catalog, pageRef, page, tr.size - 1
+}
+
+func refNum(ref string) int {
+	n, _ := strconv.Atoi(strings.Fields(ref)[0])
+	return n
+}
+
+func TestVerifyAllowsCountersignature(t *testing.T) {
+	s := newTestSigner(t)
+	data := signTestPDF(t, s)
+
+	tr, catalog, pageRef, page, field := testSignedObjects(t, data)
+	sig, widget := tr.size, tr.size+1
+
+	fields := fmt.Sprintf("/Fields [%d 0 R]", field)
+	annots := fmt.Sprintf("%d 0 R", field)
+
+	data = appendTestUpdate(t, data, "", map[int]string{
+		sig: "<< /Type /Sig /Filter /Adobe.PPKLite /SubFilter /ETSI.CAdES.detached /Contents <00> >>",
+		widget: fmt.Sprintf(
+			"<< /Type /Annot /Subtype /Widget /FT /Sig /T (Signature2) /V %d 0 R /P %s R /Rect [0 0 0 0] >>",
+			sig, pageRef,
+		),
+		refNum(tr.root): strings.Replace(catalog, fields, fmt.Sprintf("/Fields [%d 0 R %d 0 R]", field, widget), 1),
+		refNum(pageRef): strings.Replace(page, annots, fmt.Sprintf("%s %d 0 R", annots, widget), 1),
+	})
+
+	if err := s.verify(data); err != nil {
+		t.Fatalf("the countersignature should be allowed, got %v", err)
+	}
+}
+
+func TestVerifyAllowsAnnotation(t *testing.T) {
+	s := newTestSigner(t)
+	data := signTestPDF(t, s)
+
+	tr, _, pageRef, page, field := testSignedObjects(t, data)
+	annots := fmt.Sprintf("%d 0 R", field)
+
+	data = appendTestUpdate(t, data, "", map[int]string{
+		tr.size:         "<< /Type /Annot /Subtype /Text /Rect [0 0 10 10] /Contents (signed) >>",
+		refNum(pageRef): strings.Replace(page, annots, fmt.Sprintf("%s %d 0 R", annots, tr.size), 1),
+	})
+
+	if err := s.verify(data); err != nil {
+		t.Fatalf("the annotation should be allowed, got %v", err)
+	}
+}
+
+func TestVerifyRejectsChangesAfterSigning(t *testing.T) {
+	s := newTestSigner(t)
+	signed := signTestPDF(t, s)
+
+	tr, catalog, pageRef, page, _ := testSignedObjects(t, signed)
+
+	contents := regexp.MustCompile(`/Contents\s+(\d+)\s+\d+\s+R`).FindStringSubmatch(page)
+	if contents == nil {
+		t.Fatalf("missing the contents of page: %s", page)
+	}
+	pages := rePagesRef.FindStringSubmatch(catalog)
+
+	cases := []struct {
+		name string
+		info string
+		objs map[int]string
+	}{
+		{
+			"replace the info",
+			fmt.Sprintf("%d 0", tr.size),
+			map[int]string{tr.size: "<< /Keywords (changed) >>"},
+		},
+		{
+			"change the info",
+			"",
+			map[int]string{refNum(tr.info): "<< /Keywords (changed) >>"},
+		},
+		{
+			"change the contents of page",
+			"",
+			map[int]string{refNum(contents[1]): "<< /Length 0 >>\nstream\n\nendstream"},
+		},
+		{
+			"change the page",
+			"",
+			map[int]string{refNum(pageRef): strings.Replace(page, "/Contents", "/Rotate 90 /Contents", 1)},
+		},
+		{
+			"change the pages of catalog",
+			"",
+			map[int]string{refNum(tr.root): strings.Replace(catalog, pages[0], fmt.Sprintf("/Pages %d 0 R", tr.size), 1)},
+		},
+	}
+
+	for _, c := range cases {
+		data := appendTestUpdate(t, signed, c.info, c.objs)
+		if err := s.verify(data); err == nil {
+			t.Errorf("%s: the pdf changed after being signed should be rejected", c.name)
+
+		}
+	}
+}
+
+func TestDictEntries(t *testing.T) {
+	v, err := dictEntries("<< /Type /Page /Kids [1 0 R\n2 0 R] /T (a \\) (b)) /D << /X <00> >> /N 1 0 R /M 2 >>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]string{
+		"Type": "/Page",
+		"Kids": "[1 0 R 2 0 R]",
+		"T":    `(a \) (b))`,
+		"D":    "<< /X <00> >>",
+		"N":    "1 0 R",
+		"M":    "2",
+	}
+	if !reflect.DeepEqual(v, expect) {
+		t.Fatalf("expect %v, got %v", expect, v)
+	}
+}
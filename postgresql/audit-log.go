@@ -0,0 +1,82 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) AddAuditLog(log *dbmodels.AuditLog) dbmodels.IDBError {
+	actor, err := this.encrypt.encryptStr(log.Actor)
+	if err != nil {
+		return err
+	}
+
+	target, err := this.encrypt.encryptStr(log.Target)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		_, err := this.exec(
+			ctx,
+			`INSERT INTO audit_logs (actor, remote_addr, action, link_id, corp_id, target, time)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			actor, log.RemoteAddr, log.Action, log.LinkID, log.CorpID, target, log.Time,
+		)
+		return err
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) ListAuditLogs(opt *dbmodels.AuditLogListOption) ([]dbmodels.AuditLog, dbmodels.IDBError) {
+	query := `SELECT actor, remote_addr, action, link_id, corp_id, target, time
+		FROM audit_logs WHERE link_id = $1`
+	args := []interface{}{opt.LinkID}
+
+	if opt.CorpID != "" {
+		args = append(args, opt.CorpID)
+		query += " AND corp_id = $2"
+	}
+	query += " ORDER BY time, id"
+
+	var r []dbmodels.AuditLog
+	f := func(ctx context.Context) dbmodels.IDBError {
+		rows, err := this.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return newSystemError(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item dbmodels.AuditLog
+
+			err := rows.Scan(
+				&item.Actor, &item.RemoteAddr, &item.Action,
+				&item.LinkID, &item.CorpID, &item.Target, &item.Time,
+			)
+			if err != nil {
+				return newSystemError(err)
+			}
+
+			var err1 dbmodels.IDBError
+			if item.Actor, err1 = this.encrypt.decryptStr(item.Actor); err1 != nil {
+				return err1
+			}
+
+			if item.Target, err1 = this.encrypt.decryptStr(item.Target); err1 != nil {
+				return err1
+			}
+
+			r = append(r, item)
+		}
+
+		return toDBError(rows.Err())
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,207 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func insertCLAInfo(ctx context.Context, e sqlExecutor, linkID, applyTo string, info *dbmodels.CLAInfo) dbmodels.IDBError {
+	fields, err := marshalFields(info.Fields)
+	if err != nil {
+		return err
+	}
+
+	r, err1 := e.ExecContext(
+		ctx,
+		`INSERT INTO cla_infos (link_id, apply_to, lang, cla_hash, signature_hash, fields)
+		SELECT $1::text, $2::text, $3::text, $4::text, $5::text, $6::text
+		WHERE EXISTS (SELECT 1 FROM links WHERE link_id = $1 AND link_status = $7)
+		ON CONFLICT DO NOTHING`,
+		linkID, applyTo, info.CLALang, info.CLAHash, info.OrgSignatureHash, fields,
+		linkStatusReady,
+	)
+	if err1 != nil {
+		return newSystemError(err1)
+	}
+
+	n, err1 := r.RowsAffected()
+	if err1 != nil {
+		return newSystemError(err1)
+	}
+	if n == 0 {
+		return errNoDBRecord
+	}
+	return nil
+}
+
+func (this *client) InitializeIndividualSigning(linkID string, claInfo *dbmodels.CLAInfo) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.doTransaction(ctx, func(tx *sql.Tx) dbmodels.IDBError {
+			return initializeSigning(
+				ctx, tx, linkID, dbmodels.ApplyToIndividual, claInfo,
+				"DELETE FROM individual_signings WHERE link_id = $1",
+			)
+		})
+	}
+
+	return withContext1(f)
+}
+
+// InitializeCorpSigning clears the signing data of the link. The org info
+// is not saved again, because it is the same as the one in the links table.
+func (this *client) InitializeCorpSigning(linkID string, info *dbmodels.OrgInfo, claInfo *dbmodels.CLAInfo) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.doTransaction(ctx, func(tx *sql.Tx) dbmodels.IDBError {
+			return initializeSigning(
+				ctx, tx, linkID, dbmodels.ApplyToCorporation, claInfo,
+				"DELETE FROM corp_signings WHERE link_id = $1",
+				"DELETE FROM corp_managers WHERE link_id = $1",
+			)
+		})
+	}
+
+	return withContext1(f)
+}
+
+func initializeSigning(ctx context.Context, tx *sql.Tx, linkID, applyTo string, claInfo *dbmodels.CLAInfo, clears ...string) dbmodels.IDBError {
+	for _, item := range clears {
+		if _, err := tx.ExecContext(ctx, item, linkID); err != nil {
+			return newSystemError(err)
+		}
+	}
+
+	_, err := tx.ExecContext(
+		ctx, "DELETE FROM cla_infos WHERE link_id = $1 AND apply_to = $2", linkID, applyTo,
+	)
+	if err != nil {
+		return newSystemError(err)
+	}
+
+	if claInfo == nil {
+		return nil
+	}
+	return insertCLAInfo(ctx, tx, linkID, applyTo, claInfo)
+}
+
+func (this *client) DeleteCLAInfo(linkID, applyTo, claLang string) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		_, err = this.exec(
+			ctx, "DELETE FROM cla_infos WHERE link_id = $1 AND apply_to = $2 AND lang = $3",
+			linkID, applyTo, claLang,
+		)
+		return err
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) AddCLAInfo(linkID, applyTo string, info *dbmodels.CLAInfo) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return insertCLAInfo(ctx, this.db, linkID, applyTo, info)
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) GetCLAInfoSigned(linkID, claLang, applyTo string) (*dbmodels.CLAInfo, dbmodels.IDBError) {
+	signings := "SELECT 1 FROM individual_signings WHERE link_id = $1 AND lang = $2"
+	if applyTo == dbmodels.ApplyToCorporation {
+		signings = "SELECT 1 FROM corp_signings WHERE link_id = $1 AND lang = $2 AND NOT deleted"
+	}
+
+	var info *dbmodels.CLAInfo
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		var n int
+		err1 := this.db.QueryRowContext(
+			ctx, "SELECT COUNT(1) FROM ("+signings+" LIMIT 1) s", linkID, claLang,
+		).Scan(&n)
+		if err1 != nil {
+			return newSystemError(err1)
+		}
+		if n == 0 {
+			return errNoDBRecord
+		}
+
+		// the cla infos are inserted in the order of versions
+		row := this.db.QueryRowContext(
+			ctx,
+			`SELECT cla_hash, signature_hash, fields FROM cla_infos
+			WHERE link_id = $1 AND apply_to = $2 AND lang = $3
+			ORDER BY id DESC LIMIT 1`,
+			linkID, applyTo, claLang,
+		)
+		info, err = getCLAInfo(row, claLang)
+		return err
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (this *client) GetCLAInfoOfVersion(linkID, claLang, claHash, applyTo string) (*dbmodels.CLAInfo, dbmodels.IDBError) {
+	var info *dbmodels.CLAInfo
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		row := this.db.QueryRowContext(
+			ctx,
+			`SELECT cla_hash, signature_hash, fields FROM cla_infos
+			WHERE link_id = $1 AND apply_to = $2 AND lang = $3 AND cla_hash = $4`,
+			linkID, applyTo, claLang, claHash,
+		)
+		info, err = getCLAInfo(row, claLang)
+		return err
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// getCLAInfo returns nil if there is no row.
+func getCLAInfo(row *sql.Row, claLang string) (*dbmodels.CLAInfo, dbmodels.IDBError) {
+	v := dbmodels.CLAInfo{CLALang: claLang}
+	fields := ""
+
+	if err := row.Scan(&v.CLAHash, &v.OrgSignatureHash, &fields); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, newSystemError(err)
+	}
+
+	fs, err := unmarshalFields(fields)
+	if err != nil {
+		return nil, err
+	}
+	v.Fields = fs
+
+	return &v, nil
+}
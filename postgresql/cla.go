@@ -0,0 +1,324 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertCLA(ctx context.Context, e sqlExecutor, linkID, applyTo string, cla *dbmodels.CLACreateOption) dbmodels.IDBError {
+	fields, err := marshalFields(cla.Fields)
+	if err != nil {
+		return err
+	}
+
+	var orgSignature []byte
+	if cla.OrgSignature != nil {
+		orgSignature = *cla.OrgSignature
+	}
+
+	r, err1 := e.ExecContext(
+		ctx,
+		`INSERT INTO clas (
+			link_id, apply_to, lang, url, text, fields, cla_hash, signature_hash, org_signature,
+			version, policy
+		)
+		SELECT $1::text, $2::text, $3::text, $4::text, $5::text, $6::text, $7::text, $8::text, $9::bytea,
+			$11::int, $12::text
+		WHERE EXISTS (SELECT 1 FROM links WHERE link_id = $1 AND link_status = $10)
+		ON CONFLICT DO NOTHING`,
+		linkID, applyTo, cla.Language, cla.URL, cla.Text, fields,
+		cla.CLAHash, cla.OrgSignatureHash, orgSignature, linkStatusReady,
+		cla.Version, cla.Policy,
+	)
+	if err1 != nil {
+		return newSystemError(err1)
+	}
+
+	n, err1 := r.RowsAffected()
+	if err1 != nil {
+		return newSystemError(err1)
+	}
+	if n == 0 {
+		return errNoDBRecord
+	}
+	return nil
+}
+
+func (this *client) HasCLA(linkID, applyTo, language string) (bool, dbmodels.IDBError) {
+	has := false
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		var n int
+		err1 := this.db.QueryRowContext(
+			ctx,
+			"SELECT COUNT(1) FROM clas WHERE link_id = $1 AND apply_to = $2 AND lang = $3",
+			linkID, applyTo, language,
+		).Scan(&n)
+		if err1 != nil {
+			return newSystemError(err1)
+		}
+
+		has = n > 0
+		return nil
+	}
+
+	err := withContext1(f)
+	return has, err
+}
+
+func (this *client) AddCLA(linkID, applyTo string, cla *dbmodels.CLACreateOption) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return insertCLA(ctx, this.db, linkID, applyTo, cla)
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) ListCLAVersions(linkID, applyTo, language string) ([]dbmodels.CLAVersion, dbmodels.IDBError) {
+	var r []dbmodels.CLAVersion
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		rows, err1 := this.db.QueryContext(
+			ctx,
+			`SELECT version, cla_hash, policy FROM clas
+			WHERE link_id = $1 AND apply_to = $2 AND lang = $3 ORDER BY version`,
+			linkID, applyTo, language,
+		)
+		if err1 != nil {
+			return newSystemError(err1)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item dbmodels.CLAVersion
+			if err := rows.Scan(&item.Version, &item.CLAHash, &item.Policy); err != nil {
+				return newSystemError(err)
+			}
+
+			r = append(r, item)
+		}
+
+		return toDBError(rows.Err())
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (this *client) DeleteCLA(linkID, applyTo, language string) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		_, err = this.exec(
+			ctx, "DELETE FROM clas WHERE link_id = $1 AND apply_to = $2 AND lang = $3",
+			linkID, applyTo, language,
+		)
+		return err
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) GetCLAByType(orgRepo *dbmodels.OrgRepo, applyTo string) (string, []dbmodels.CLADetail, dbmodels.IDBError) {
+	linkID, err := this.GetLinkID(orgRepo)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var clas []dbmodels.CLADetail
+	f := func(ctx context.Context) dbmodels.IDBError {
+		v, err := this.getCLAs(ctx, linkID, applyTo)
+		clas = v
+		return err
+	}
+
+	if err := withContext1(f); err != nil {
+		return "", nil, err
+	}
+	return linkID, clas, nil
+}
+
+func (this *client) GetAllCLA(linkID string) (*dbmodels.CLAOfLink, dbmodels.IDBError) {
+	r := dbmodels.CLAOfLink{}
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		if r.IndividualCLAs, err = this.getCLAs(ctx, linkID, dbmodels.ApplyToIndividual); err != nil {
+			return err
+		}
+
+		r.CorpCLAs, err = this.getCLAs(ctx, linkID, dbmodels.ApplyToCorporation)
+		return err
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (this *client) getCLAs(ctx context.Context, linkID, applyTo string) ([]dbmodels.CLADetail, dbmodels.IDBError) {
+	rows, err := this.db.QueryContext(
+		ctx,
+		`SELECT lang, url, text, fields, cla_hash, version, policy,
+			drift_status, drift_checked_at, drift_url_hash, drift_draft
+		FROM clas WHERE link_id = $1 AND apply_to = $2 ORDER BY lang, version`,
+		linkID, applyTo,
+	)
+	if err != nil {
+		return nil, newSystemError(err)
+	}
+	defer rows.Close()
+
+	var r []dbmodels.CLADetail
+	for rows.Next() {
+		var item dbmodels.CLADetail
+		var drift dbmodels.CLADrift
+		fields := ""
+
+		err1 := rows.Scan(
+			&item.Language, &item.URL, &item.Text, &fields,
+			&item.CLAHash, &item.Version, &item.Policy,
+			&drift.Status, &drift.CheckedAt, &drift.URLHash, &drift.Draft,
+		)
+		if err1 != nil {
+			return nil, newSystemError(err1)
+		}
+
+		if drift.Status != "" {
+			item.Drift = &drift
+		}
+
+		fs, err := unmarshalFields(fields)
+		if err != nil {
+			return nil, err
+		}
+		item.Fields = fs
+
+		r = append(r, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, newSystemError(err)
+	}
+	return r, nil
+}
+
+func (this *client) GetCLAInfoToSign(linkID, claLang, applyTo string) (*dbmodels.CLAInfo, dbmodels.IDBError) {
+	var info *dbmodels.CLAInfo
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		v := dbmodels.CLAInfo{CLALang: claLang}
+		fields := ""
+		err1 := this.db.QueryRowContext(
+			ctx,
+			`SELECT cla_hash, signature_hash, fields FROM clas
+			WHERE link_id = $1 AND apply_to = $2 AND lang = $3
+			ORDER BY version DESC LIMIT 1`,
+			linkID, applyTo, claLang,
+		).Scan(&v.CLAHash, &v.OrgSignatureHash, &fields)
+		if err1 != nil {
+			if err1 == sql.ErrNoRows {
+				return nil
+			}
+			return newSystemError(err1)
+		}
+
+		if v.Fields, err = unmarshalFields(fields); err != nil {
+			return err
+		}
+
+		info = &v
+		return nil
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (this *client) DownloadCorpCLAPDF(linkID, lang string, version int) ([]byte, dbmodels.IDBError) {
+	var pdf []byte
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		err1 := this.db.QueryRowContext(
+			ctx,
+			`SELECT org_signature FROM clas
+			WHERE link_id = $1 AND apply_to = $2 AND lang = $3 AND version = $4`,
+			linkID, dbmodels.ApplyToCorporation, lang, version,
+		).Scan(&pdf)
+		if err1 != nil && err1 != sql.ErrNoRows {
+			return newSystemError(err1)
+		}
+		return nil
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return pdf, nil
+}
+
+func (this *client) UpdateCLADrift(linkID, applyTo, language string, version int, drift *dbmodels.CLADrift) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.execOnRecord(
+			ctx,
+			`UPDATE clas SET drift_status = $1, drift_checked_at = $2,
+				drift_url_hash = $3, drift_draft = $4
+			WHERE link_id = $5 AND apply_to = $6 AND lang = $7 AND version = $8`,
+			drift.Status, drift.CheckedAt, drift.URLHash, drift.Draft,
+			linkID, applyTo, language, version,
+		)
+	}
+
+	return withContext1(f)
+}
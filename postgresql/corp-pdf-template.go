@@ -0,0 +1,87 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) SaveCorpPDFTemplate(linkID string, t *dbmodels.CorpPDFTemplate) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		_, err = this.exec(
+			ctx,
+			`INSERT INTO corp_pdf_templates (link_id, lang, welcome, declaration, logo, logo_type)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (link_id, lang) DO UPDATE SET welcome = EXCLUDED.welcome,
+				declaration = EXCLUDED.declaration, logo = EXCLUDED.logo, logo_type = EXCLUDED.logo_type`,
+			linkID, t.Language, t.Welcome, t.Declaration, t.Logo, t.LogoType,
+		)
+		return err
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) GetCorpPDFTemplate(linkID, language string) (*dbmodels.CorpPDFTemplate, dbmodels.IDBError) {
+	var r *dbmodels.CorpPDFTemplate
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		v := dbmodels.CorpPDFTemplate{Language: language}
+		err1 := this.db.QueryRowContext(
+			ctx,
+			`SELECT welcome, declaration, logo, logo_type FROM corp_pdf_templates
+			WHERE link_id = $1 AND lang = $2`,
+			linkID, language,
+		).Scan(&v.Welcome, &v.Declaration, &v.Logo, &v.LogoType)
+		if err1 != nil {
+			if err1 == sql.ErrNoRows {
+				return nil
+			}
+			return newSystemError(err1)
+		}
+
+		r = &v
+		return nil
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (this *client) DeleteCorpPDFTemplate(linkID, language string) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		_, err = this.exec(
+			ctx, "DELETE FROM corp_pdf_templates WHERE link_id = $1 AND lang = $2",
+			linkID, language,
+		)
+		return err
+	}
+
+	return withContext1(f)
+}
@@ -0,0 +1,51 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) GetCorpSigningPolicy(linkID string) (*dbmodels.CorpSigningPolicy, dbmodels.IDBError) {
+	s := ""
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.db.QueryRowContext(
+			ctx,
+			"SELECT corp_signing_policy FROM links WHERE link_id = $1 AND link_status = $2",
+			linkID, linkStatusReady,
+		).Scan(&s)
+		return toDBError(err)
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+
+	if s == "" {
+		return nil, nil
+	}
+
+	var p dbmodels.CorpSigningPolicy
+	if err := json.Unmarshal([]byte(s), &p); err != nil {
+		return nil, newSystemError(err)
+	}
+	return &p, nil
+}
+
+func (this *client) UpdateCorpSigningPolicy(linkID string, p *dbmodels.CorpSigningPolicy) dbmodels.IDBError {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return newDBError(dbmodels.ErrMarshalDataFaield, err)
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.execOnRecord(
+			ctx,
+			"UPDATE links SET corp_signing_policy = $1 WHERE link_id = $2 AND link_status = $3",
+			string(b), linkID, linkStatusReady,
+		)
+	}
+
+	return withContext1(f)
+}
@@ -0,0 +1,201 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) AddCorpAdministrator(linkID string, opt *dbmodels.CorporationManagerCreateOption) dbmodels.IDBError {
+	email, err := this.encrypt.encryptStr(opt.Email)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.execOnRecord(
+			ctx,
+			`INSERT INTO corp_managers (link_id, corp_id, id, name, role, email, password)
+			SELECT $1::text, $2::text, $3::text, $4::text, $5::text, $6::text, $7::text
+			WHERE EXISTS (SELECT 1 FROM links WHERE link_id = $1 AND link_status = $8)
+			ON CONFLICT DO NOTHING`,
+			linkID, genCorpID(opt.Email), opt.ID, opt.Name, dbmodels.RoleAdmin,
+			email, opt.Password, linkStatusReady,
+		)
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) CheckCorporationManagerExist(opt dbmodels.CorporationManagerCheckInfo) (map[string]dbmodels.CorporationManagerCheckResult, dbmodels.IDBError) {
+	query := `SELECT DISTINCT ON (m.link_id)
+		m.link_id, m.role, m.name, m.email, m.password, m.changed,
+		l.platform, l.org, l.repo, l.org_alias, l.org_email
+		FROM corp_managers m JOIN links l ON m.link_id = l.link_id
+		WHERE l.link_status = $1 AND m.corp_id = $2 AND `
+
+	var args []interface{}
+	if opt.Email != "" {
+		email, err := this.encrypt.encryptStr(opt.Email)
+		if err != nil {
+			return nil, err
+		}
+
+		query += "m.email = $3"
+		args = []interface{}{linkStatusReady, genCorpID(opt.Email), email}
+	} else {
+		query += "m.id = $3"
+		args = []interface{}{linkStatusReady, opt.EmailSuffix, opt.ID}
+	}
+
+	result := map[string]dbmodels.CorporationManagerCheckResult{}
+	f := func(ctx context.Context) dbmodels.IDBError {
+		rows, err := this.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return newSystemError(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item dbmodels.CorporationManagerCheckResult
+			linkID := ""
+			email := ""
+
+			err := rows.Scan(
+				&linkID, &item.Role, &item.Name, &email, &item.Password,
+				&item.InitialPWChanged, &item.Platform, &item.OrgID, &item.RepoID,
+				&item.OrgAlias, &item.OrgEmail,
+			)
+			if err != nil {
+				return newSystemError(err)
+			}
+
+			v, err1 := this.encrypt.decryptStr(email)
+			if err1 != nil {
+				return err1
+			}
+			item.Email = v
+
+			result[linkID] = item
+		}
+
+		return toDBError(rows.Err())
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func (this *client) ResetCorporationManagerPassword(linkID, email string, opt dbmodels.CorporationManagerResetPassword) dbmodels.IDBError {
+	encryptedEmail, err := this.encrypt.encryptStr(email)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.execOnRecord(
+			ctx,
+			`UPDATE corp_managers SET password = $1, changed = TRUE
+			WHERE link_id = $2 AND corp_id = $3 AND email = $4 AND password = $5
+			AND EXISTS (SELECT 1 FROM links WHERE link_id = $2 AND link_status = $6)`,
+			opt.NewPassword, linkID, genCorpID(email), encryptedEmail,
+			opt.OldPassword, linkStatusReady,
+		)
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) ListCorporationManager(linkID, email, role string) ([]dbmodels.CorporationManagerListResult, dbmodels.IDBError) {
+	query := "SELECT id, name, email, role FROM corp_managers WHERE link_id = $1 AND corp_id = $2"
+	args := []interface{}{linkID, genCorpID(email)}
+	if role != "" {
+		query += " AND role = $3"
+		args = append(args, role)
+	}
+
+	var r []dbmodels.CorporationManagerListResult
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		rows, err1 := this.db.QueryContext(ctx, query, args...)
+		if err1 != nil {
+			return newSystemError(err1)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item dbmodels.CorporationManagerListResult
+			email := ""
+
+			if err := rows.Scan(&item.ID, &item.Name, &email, &item.Role); err != nil {
+				return newSystemError(err)
+			}
+
+			if item.Email, err = this.encrypt.decryptStr(email); err != nil {
+				return err
+			}
+
+			r = append(r, item)
+		}
+
+		return toDBError(rows.Err())
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (this *client) GetCorporationManager(linkID, email string) (*dbmodels.CorporationManagerCheckResult, dbmodels.IDBError) {
+	encryptedEmail, err := this.encrypt.encryptStr(email)
+	if err != nil {
+		return nil, err
+	}
+
+	var r *dbmodels.CorporationManagerCheckResult
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		pw := ""
+		err1 := this.db.QueryRowContext(
+			ctx,
+			"SELECT password FROM corp_managers WHERE link_id = $1 AND corp_id = $2 AND email = $3",
+			linkID, genCorpID(email), encryptedEmail,
+		).Scan(&pw)
+		if err1 != nil {
+			if err1 == sql.ErrNoRows {
+				return nil
+			}
+			return newSystemError(err1)
+		}
+
+		r = &dbmodels.CorporationManagerCheckResult{Password: pw}
+		return nil
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
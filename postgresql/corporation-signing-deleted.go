@@ -0,0 +1,192 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func addCorpSigningEvent(ctx context.Context, tx *sql.Tx, linkID, corpID string, event *dbmodels.CorpSigningEvent) dbmodels.IDBError {
+	if event == nil {
+		return nil
+	}
+
+	_, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO corp_signing_history (link_id, corp_id, action, operator, time)
+		VALUES ($1, $2, $3, $4, $5)`,
+		linkID, corpID, event.Action, event.Operator, event.Time,
+	)
+	return toDBError(err)
+}
+
+func (this *client) DeleteCorpSigning(linkID, email string, event *dbmodels.CorpSigningEvent) dbmodels.IDBError {
+	corpID := genCorpID(email)
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		return this.doTransaction(ctx, func(tx *sql.Tx) dbmodels.IDBError {
+			r, err := tx.ExecContext(
+				ctx,
+				`UPDATE corp_signings SET deleted = TRUE
+				WHERE link_id = $1 AND corp_id = $2 AND NOT deleted`,
+				linkID, corpID,
+			)
+			if err != nil {
+				return newSystemError(err)
+			}
+
+			// It is ok if the corporation has not signed.
+			if n, err := r.RowsAffected(); err != nil || n == 0 {
+				return toDBError(err)
+			}
+
+			return addCorpSigningEvent(ctx, tx, linkID, corpID, event)
+		})
+	}
+
+	return withContext1(f)
+}
+
+// RestoreCorpSigning restores the latest deleted signing of the corporation.
+// It returns ErrRecordExists if the corporation has signed again.
+func (this *client) RestoreCorpSigning(linkID, email string, event *dbmodels.CorpSigningEvent) dbmodels.IDBError {
+	corpID := genCorpID(email)
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		return this.doTransaction(ctx, func(tx *sql.Tx) dbmodels.IDBError {
+			var n int
+			err := tx.QueryRowContext(
+				ctx,
+				"SELECT COUNT(1) FROM corp_signings WHERE link_id = $1 AND corp_id = $2 AND NOT deleted",
+				linkID, corpID,
+			).Scan(&n)
+			if err != nil {
+				return newSystemError(err)
+			}
+			if n > 0 {
+				return newDBError(dbmodels.ErrRecordExists, fmt.Errorf("the corporation has signed again"))
+			}
+
+			// The signings of a corporation are deleted in the order of being signed,
+			// so the latest deleted one is the one with the biggest id.
+			r, err := tx.ExecContext(
+				ctx,
+				`UPDATE corp_signings SET deleted = FALSE WHERE id = (
+					SELECT id FROM corp_signings WHERE link_id = $1 AND corp_id = $2 AND deleted
+					ORDER BY id DESC LIMIT 1
+				)`,
+				linkID, corpID,
+			)
+			if err != nil {
+				return newSystemError(err)
+			}
+
+			if n, err := r.RowsAffected(); err != nil {
+				return newSystemError(err)
+			} else if n == 0 {
+				return errNoDBRecord
+			}
+
+			return addCorpSigningEvent(ctx, tx, linkID, corpID, event)
+		})
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) ListCorpSigningHistory(linkID, email string) ([]dbmodels.CorpSigningEvent, dbmodels.IDBError) {
+	var r []dbmodels.CorpSigningEvent
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		rows, err1 := this.db.QueryContext(
+			ctx,
+			`SELECT action, operator, time FROM corp_signing_history
+			WHERE link_id = $1 AND corp_id = $2 ORDER BY time, id`,
+			linkID, genCorpID(email),
+		)
+		if err1 != nil {
+			return newSystemError(err1)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item dbmodels.CorpSigningEvent
+			if err := rows.Scan(&item.Action, &item.Operator, &item.Time); err != nil {
+				return newSystemError(err)
+			}
+
+			r = append(r, item)
+		}
+
+		return toDBError(rows.Err())
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (this *client) ListDeletedCorpSignings(linkID string) ([]dbmodels.CorporationSigningBasicInfo, dbmodels.IDBError) {
+	var r []dbmodels.CorporationSigningBasicInfo
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		rows, err1 := this.db.QueryContext(
+			ctx,
+			"SELECT "+columnsOfCorpSigning+" FROM corp_signings WHERE link_id = $1 AND deleted ORDER BY id",
+			linkID,
+		)
+		if err1 != nil {
+			return newSystemError(err1)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			bi, err := this.scanCorpSigningBasicInfo(rows)
+			if err != nil {
+				return err
+			}
+
+			r = append(r, *bi)
+		}
+
+		return toDBError(rows.Err())
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
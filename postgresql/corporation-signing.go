@@ -0,0 +1,293 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+const columnsOfCorpSigning = "lang, cla_hash, email, name, corp, date, esigned, agreement_id"
+
+func (this *client) SignCorpCLA(linkID string, info *dbmodels.CorpSigningCreateOpt) dbmodels.IDBError {
+	email, err := this.encrypt.encryptStr(info.AdminEmail)
+	if err != nil {
+		return err
+	}
+
+	si, err := this.encrypt.encryptSigningInfo(&info.Info)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.execOnRecord(
+			ctx,
+			`INSERT INTO corp_signings (link_id, corp_id, lang, cla_hash, corp, email, name, date, info, esigned, agreement_id)
+			SELECT $1::text, $2::text, $3::text, $4::text, $5::text, $6::text, $7::text, $8::text, $9::bytea, $11::boolean, $12::text
+			WHERE EXISTS (SELECT 1 FROM links WHERE link_id = $1 AND link_status = $10)
+			ON CONFLICT DO NOTHING`,
+			linkID, genCorpID(info.AdminEmail), info.CLALanguage, info.CLAHash,
+			info.CorporationName, email, info.AdminName, info.Date, si, linkStatusReady, info.ESigned,
+			info.AgreementID,
+		)
+	}
+
+	return withContext1(f)
+}
+
+// ResignCorpCLA updates the signing in place, so that its history is kept.
+// Only the admin who signed the older version can re-sign.
+func (this *client) ResignCorpCLA(linkID string, info *dbmodels.CorpSigningCreateOpt) dbmodels.IDBError {
+	email, err := this.encrypt.encryptStr(info.AdminEmail)
+	if err != nil {
+		return err
+	}
+
+	si, err := this.encrypt.encryptSigningInfo(&info.Info)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.execOnRecord(
+			ctx,
+			`UPDATE corp_signings SET
+				lang = $3, cla_hash = $4, corp = $5, email = $6, name = $7, date = $8, info = $9,
+				esigned = $11, agreement_id = $12
+			WHERE link_id = $1 AND corp_id = $2 AND NOT deleted AND cla_hash <> $4 AND email = $6
+			AND EXISTS (SELECT 1 FROM links WHERE link_id = $1 AND link_status = $10)`,
+			linkID, genCorpID(info.AdminEmail), info.CLALanguage, info.CLAHash,
+			info.CorporationName, email, info.AdminName, info.Date, si, linkStatusReady, info.ESigned,
+			info.AgreementID,
+		)
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) ListCorpSignings(linkID, language string) ([]dbmodels.CorporationSigningSummary, dbmodels.IDBError) {
+	query := `SELECT s.lang, s.cla_hash, s.email, s.name, s.corp, s.date, s.esigned, s.agreement_id, EXISTS (
+			SELECT 1 FROM corp_managers m
+			WHERE m.link_id = s.link_id AND m.corp_id = s.corp_id
+			AND m.email = s.email AND m.role = $2
+		)
+		FROM corp_signings s WHERE s.link_id = $1 AND NOT s.deleted`
+	args := []interface{}{linkID, dbmodels.RoleAdmin}
+	if language != "" {
+		query += " AND s.lang = $3"
+		args = append(args, language)
+	}
+
+	var r []dbmodels.CorporationSigningSummary
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		rows, err1 := this.db.QueryContext(ctx, query, args...)
+		if err1 != nil {
+			return newSystemError(err1)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item dbmodels.CorporationSigningSummary
+			bi, err := this.scanCorpSigningBasicInfo(rows, &item.AdminAdded)
+			if err != nil {
+				return err
+			}
+			item.CorporationSigningBasicInfo = *bi
+
+			r = append(r, item)
+		}
+
+		return toDBError(rows.Err())
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (this *client) IsCorpSigned(linkID, email string) (bool, dbmodels.IDBError) {
+	signed := false
+	f := func(ctx context.Context) dbmodels.IDBError {
+		var n int
+		err := this.db.QueryRowContext(
+			ctx,
+			`SELECT COUNT(1) FROM corp_signings s JOIN links l ON s.link_id = l.link_id
+			WHERE s.link_id = $1 AND s.corp_id = $2 AND NOT s.deleted AND l.link_status = $3`,
+			linkID, genCorpID(email), linkStatusReady,
+		).Scan(&n)
+		if err != nil {
+			return newSystemError(err)
+		}
+
+		signed = n > 0
+		return nil
+	}
+
+	err := withContext1(f)
+	return signed, err
+}
+
+func (this *client) GetCorpSigningBasicInfo(linkID, email string) (*dbmodels.CorporationSigningBasicInfo, dbmodels.IDBError) {
+	var r *dbmodels.CorporationSigningBasicInfo
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		row := this.db.QueryRowContext(
+			ctx,
+			"SELECT "+columnsOfCorpSigning+` FROM corp_signings
+			WHERE link_id = $1 AND corp_id = $2 AND NOT deleted`,
+			linkID, genCorpID(email),
+		)
+
+		r, err = this.scanCorpSigningBasicInfo(row)
+		if err != nil && err.IsErrorOf(dbmodels.ErrNoDBRecord) {
+			return nil
+		}
+		return err
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (this *client) GetCorpSigningDetail(linkID, email string) ([]dbmodels.Field, *dbmodels.CorpSigningCreateOpt, dbmodels.IDBError) {
+	var fields []dbmodels.Field
+	var detail *dbmodels.CorpSigningCreateOpt
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		var info []byte
+		var fs sql.NullString
+		row := this.db.QueryRowContext(
+			ctx,
+			`SELECT s.lang, s.cla_hash, s.email, s.name, s.corp, s.date, s.esigned, s.agreement_id, s.info, c.fields
+			FROM corp_signings s LEFT JOIN cla_infos c
+			ON c.link_id = s.link_id AND c.apply_to = $3 AND c.lang = s.lang
+			AND c.cla_hash = s.cla_hash
+			WHERE s.link_id = $1 AND s.corp_id = $2 AND NOT s.deleted`,
+			linkID, genCorpID(email), dbmodels.ApplyToCorporation,
+		)
+
+		bi, err := this.scanCorpSigningBasicInfo(row, &info, &fs)
+		if err != nil {
+			return err
+		}
+
+		if !fs.Valid {
+			return nil
+		}
+
+		if fields, err = unmarshalFields(fs.String); err != nil {
+			return err
+		}
+
+		si, err := this.encrypt.decryptSigningInfo(info)
+		if err != nil {
+			return err
+		}
+
+		detail = &dbmodels.CorpSigningCreateOpt{
+			CorporationSigningBasicInfo: *bi,
+			Info:                        *si,
+		}
+		return nil
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, nil, err
+	}
+	return fields, detail, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanCorpSigningBasicInfo scans the columns of columnsOfCorpSigning and then the extra ones.
+func (this *client) scanCorpSigningBasicInfo(s scanner, extra ...interface{}) (*dbmodels.CorporationSigningBasicInfo, dbmodels.IDBError) {
+	var r dbmodels.CorporationSigningBasicInfo
+	email := ""
+
+	dest := []interface{}{
+		&r.CLALanguage, &r.CLAHash, &email, &r.AdminName, &r.CorporationName, &r.Date, &r.ESigned,
+		&r.AgreementID,
+	}
+	if err := s.Scan(append(dest, extra...)...); err != nil {
+		return nil, toDBError(err)
+	}
+
+	v, err := this.encrypt.decryptStr(email)
+	if err != nil {
+		return nil, err
+	}
+	r.AdminEmail = v
+
+	return &r, nil
+}
+
+func (this *client) UpdateCorpSigningESigned(linkID, email string, esigned bool) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.execOnRecord(
+			ctx,
+			`UPDATE corp_signings SET esigned = $3
+			WHERE link_id = $1 AND corp_id = $2 AND NOT deleted
+			AND EXISTS (SELECT 1 FROM links WHERE link_id = $1 AND link_status = $4)`,
+			linkID, genCorpID(email), esigned, linkStatusReady,
+		)
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) GetCorpAgreement(agreementID string) (*dbmodels.CorpAgreement, dbmodels.IDBError) {
+	r := dbmodels.CorpAgreement{AgreementID: agreementID}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		var deleted bool
+		var linkStatus sql.NullString
+
+		err := this.db.QueryRowContext(
+			ctx,
+			`SELECT s.link_id, s.lang, s.cla_hash, s.corp, s.date, s.deleted, l.link_status
+			FROM corp_signings s LEFT JOIN links l ON s.link_id = l.link_id
+			WHERE s.agreement_id = $1`,
+			agreementID,
+		).Scan(&r.LinkID, &r.CLALanguage, &r.CLAHash, &r.CorporationName, &r.Date, &deleted, &linkStatus)
+		if err != nil {
+			return toDBError(err)
+		}
+
+		r.Deleted = deleted || linkStatus.String != linkStatusReady
+		return nil
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,107 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) AddEmployeeManager(linkID string, opt []dbmodels.CorporationManagerCreateOption) dbmodels.IDBError {
+	emails := make([]string, 0, len(opt))
+	for i := range opt {
+		email, err := this.encrypt.encryptStr(opt[i].Email)
+		if err != nil {
+			return err
+		}
+		emails = append(emails, email)
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		return this.doTransaction(ctx, func(tx *sql.Tx) dbmodels.IDBError {
+			for i := range opt {
+				item := &opt[i]
+
+				_, err := tx.ExecContext(
+					ctx,
+					`INSERT INTO corp_managers (link_id, corp_id, id, name, role, email, password)
+					VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+					linkID, genCorpID(item.Email), item.ID, item.Name, item.Role,
+					emails[i], item.Password,
+				)
+				if err != nil {
+					return newSystemError(err)
+				}
+			}
+			return nil
+		})
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) DeleteEmployeeManager(linkID string, emails []string) ([]dbmodels.CorporationManagerCreateOption, dbmodels.IDBError) {
+	encryptedEmails := make([]string, 0, len(emails))
+	m := map[string]string{}
+	for _, item := range emails {
+		email, err := this.encrypt.encryptStr(item)
+		if err != nil {
+			return nil, err
+		}
+		encryptedEmails = append(encryptedEmails, email)
+		m[email] = item
+	}
+
+	var deleted []dbmodels.CorporationManagerCreateOption
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		rows, err1 := this.db.QueryContext(
+			ctx,
+			`DELETE FROM corp_managers
+			WHERE link_id = $1 AND corp_id = $2 AND email = ANY($3)
+			RETURNING email, name`,
+			linkID, genCorpID(emails[0]), pq.Array(encryptedEmails),
+		)
+		if err1 != nil {
+			return newSystemError(err1)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			email := ""
+			name := ""
+			if err := rows.Scan(&email, &name); err != nil {
+				return newSystemError(err)
+			}
+
+			deleted = append(deleted, dbmodels.CorporationManagerCreateOption{
+				Email: m[email],
+				Name:  name,
+			})
+		}
+
+		return toDBError(rows.Err())
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
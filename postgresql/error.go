@@ -0,0 +1,51 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+var (
+	errNoDBRecord = dbError{code: dbmodels.ErrNoDBRecord, err: fmt.Errorf("no record")}
+)
+
+type dbError struct {
+	code dbmodels.DBErrCode
+	err  error
+}
+
+func (this dbError) Error() string {
+	if this.err == nil {
+		return ""
+	}
+	return this.err.Error()
+}
+
+func (this dbError) IsErrorOf(code dbmodels.DBErrCode) bool {
+	return this.code == code
+}
+
+func (this dbError) ErrCode() dbmodels.DBErrCode {
+	return this.code
+}
+
+func newDBError(code dbmodels.DBErrCode, err error) dbmodels.IDBError {
+	return dbError{code: code, err: err}
+}
+
+func newSystemError(err error) dbmodels.IDBError {
+	return dbError{code: dbmodels.ErrSystemError, err: err}
+}
+
+func toDBError(err error) dbmodels.IDBError {
+	if err == nil {
+		return nil
+	}
+
+	if err == sql.ErrNoRows {
+		return errNoDBRecord
+	}
+	return newSystemError(err)
+}
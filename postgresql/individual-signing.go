@@ -0,0 +1,390 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) SignIndividualCLA(linkID string, info *dbmodels.IndividualSigningInfo) dbmodels.IDBError {
+	email, err := this.encrypt.encryptStr(info.Email)
+	if err != nil {
+		return err
+	}
+
+	si, err := this.encrypt.encryptSigningInfo(&info.Info)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.execOnRecord(
+			ctx,
+			`INSERT INTO individual_signings (`+columnsOfIndividualSigning+`)
+			SELECT $1::text, $2::text, $3::text, $4::text, $5::text, $6::text, $7::text, $8::text, $9::boolean, $10::bytea
+			WHERE EXISTS (SELECT 1 FROM links WHERE link_id = $1 AND link_status = $11)
+			ON CONFLICT DO NOTHING`,
+			linkID, genCorpID(info.Email), email, info.ID, info.Name, info.Date,
+			info.CLALanguage, info.CLAHash, info.Enabled, si, linkStatusReady,
+		)
+	}
+
+	return withContext1(f)
+}
+
+// ResignIndividualCLA keeps the enabled status of signing,
+// because it is managed by the corporation for the employee.
+func (this *client) ResignIndividualCLA(linkID string, info *dbmodels.IndividualSigningInfo) dbmodels.IDBError {
+	email, err := this.encrypt.encryptStr(info.Email)
+	if err != nil {
+		return err
+	}
+
+	si, err := this.encrypt.encryptSigningInfo(&info.Info)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.execOnRecord(
+			ctx,
+			`UPDATE individual_signings SET
+				id = $3, name = $4, date = $5, lang = $6, cla_hash = $7, info = $8
+			WHERE link_id = $1 AND email = $2 AND cla_hash <> $7
+			AND EXISTS (SELECT 1 FROM links WHERE link_id = $1 AND link_status = $9)`,
+			linkID, email, info.ID, info.Name, info.Date,
+			info.CLALanguage, info.CLAHash, si, linkStatusReady,
+		)
+	}
+
+	return withContext1(f)
+}
+
+const columnsOfIndividualSigning = "link_id, corp_id, email, id, name, date, lang, cla_hash, enabled, info"
+
+func (this *client) DeleteIndividualSigning(linkID, email string, tombstone *dbmodels.SigningTombstone) dbmodels.IDBError {
+	encryptedEmail, err := this.encrypt.encryptStr(email)
+	if err != nil {
+		return err
+	}
+
+	deletedBy, err := this.encrypt.encryptStr(tombstone.DeletedBy)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		// It is ok if the individual has not signed.
+		_, err = this.exec(
+			ctx,
+			`WITH d AS (
+				DELETE FROM individual_signings WHERE link_id = $1 AND email = $2
+				RETURNING `+columnsOfIndividualSigning+`
+			)
+			INSERT INTO deleted_individual_signings (`+columnsOfIndividualSigning+`, deleted_by, reason, deleted_at)
+			SELECT `+columnsOfIndividualSigning+`, $3::text, $4::text, $5::bigint FROM d
+			ON CONFLICT (link_id, email) DO UPDATE SET
+				corp_id = EXCLUDED.corp_id, id = EXCLUDED.id, name = EXCLUDED.name,
+				date = EXCLUDED.date, lang = EXCLUDED.lang, cla_hash = EXCLUDED.cla_hash,
+				enabled = EXCLUDED.enabled,
+				info = EXCLUDED.info, deleted_by = EXCLUDED.deleted_by,
+				reason = EXCLUDED.reason, deleted_at = EXCLUDED.deleted_at`,
+			linkID, encryptedEmail, deletedBy, tombstone.Reason, tombstone.DeletedAt,
+		)
+		return err
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) ListDeletedIndividualSignings(linkID, corpEmail string) ([]dbmodels.DeletedIndividualSigning, dbmodels.IDBError) {
+	query := `SELECT id, email, name, enabled, date, deleted_by, reason, deleted_at
+		FROM deleted_individual_signings WHERE link_id = $1`
+	args := []interface{}{linkID}
+
+	if corpEmail != "" {
+		args = append(args, genCorpID(corpEmail))
+		query += fmt.Sprintf(" AND corp_id = $%d", len(args))
+	}
+	query += " ORDER BY deleted_at"
+
+	var r []dbmodels.DeletedIndividualSigning
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		rows, err1 := this.db.QueryContext(ctx, query, args...)
+		if err1 != nil {
+			return newSystemError(err1)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item dbmodels.DeletedIndividualSigning
+			email, deletedBy := "", ""
+
+			err := rows.Scan(
+				&item.ID, &email, &item.Name, &item.Enabled, &item.Date,
+				&deletedBy, &item.Reason, &item.DeletedAt,
+			)
+			if err != nil {
+				return newSystemError(err)
+			}
+
+			var err2 dbmodels.IDBError
+			if item.Email, err2 = this.encrypt.decryptStr(email); err2 != nil {
+				return err2
+			}
+
+			if item.DeletedBy, err2 = this.encrypt.decryptStr(deletedBy); err2 != nil {
+				return err2
+			}
+
+			r = append(r, item)
+		}
+
+		return toDBError(rows.Err())
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (this *client) RestoreIndividualSigning(linkID, email string) dbmodels.IDBError {
+	encryptedEmail, err := this.encrypt.encryptStr(email)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.doTransaction(ctx, func(tx *sql.Tx) dbmodels.IDBError {
+			r, err := tx.ExecContext(
+				ctx,
+				`INSERT INTO individual_signings (`+columnsOfIndividualSigning+`)
+				SELECT `+columnsOfIndividualSigning+` FROM deleted_individual_signings
+				WHERE link_id = $1 AND email = $2
+				AND EXISTS (SELECT 1 FROM links WHERE link_id = $1 AND link_status = $3)
+				ON CONFLICT DO NOTHING`,
+				linkID, encryptedEmail, linkStatusReady,
+			)
+			if err != nil {
+				return newSystemError(err)
+			}
+
+			if n, err := r.RowsAffected(); err != nil {
+				return newSystemError(err)
+			} else if n == 0 {
+				return this.whyNotRestored(ctx, tx, linkID, encryptedEmail)
+			}
+
+			_, err = tx.ExecContext(
+				ctx, "DELETE FROM deleted_individual_signings WHERE link_id = $1 AND email = $2",
+				linkID, encryptedEmail,
+			)
+			return toDBError(err)
+		})
+	}
+
+	return withContext1(f)
+}
+
+// whyNotRestored returns ErrRecordExists if the individual has signed again
+// after the signing was deleted, otherwise errNoDBRecord.
+func (this *client) whyNotRestored(ctx context.Context, tx *sql.Tx, linkID, encryptedEmail string) dbmodels.IDBError {
+	var n int
+	err := tx.QueryRowContext(
+		ctx,
+		`SELECT COUNT(1) FROM individual_signings s JOIN deleted_individual_signings d
+		ON s.link_id = d.link_id AND s.email = d.email
+		WHERE s.link_id = $1 AND s.email = $2`,
+		linkID, encryptedEmail,
+	).Scan(&n)
+	if err != nil {
+		return newSystemError(err)
+	}
+
+	if n > 0 {
+		return newDBError(dbmodels.ErrRecordExists, fmt.Errorf("the individual has signed again"))
+	}
+	return errNoDBRecord
+}
+
+func (this *client) PurgeDeletedIndividualSignings(deletedBefore int64) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		_, err := this.exec(
+			ctx, "DELETE FROM deleted_individual_signings WHERE deleted_at < $1",
+			deletedBefore,
+		)
+		return err
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) UpdateIndividualSigning(linkID, email string, enabled bool) dbmodels.IDBError {
+	encryptedEmail, err := this.encrypt.encryptStr(email)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.execOnRecord(
+			ctx,
+			`UPDATE individual_signings SET enabled = $1
+			WHERE link_id = $2 AND email = $3
+			AND EXISTS (SELECT 1 FROM links WHERE link_id = $2 AND link_status = $4)`,
+			enabled, linkID, encryptedEmail, linkStatusReady,
+		)
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) IsIndividualSigned(linkID, email string) (bool, dbmodels.IDBError) {
+	encryptedEmail, err := this.encrypt.encryptStr(email)
+	if err != nil {
+		return false, err
+	}
+
+	signed := false
+	f := func(ctx context.Context) dbmodels.IDBError {
+		var n int
+		err := this.db.QueryRowContext(
+			ctx,
+			`SELECT COUNT(1) FROM individual_signings s JOIN links l ON s.link_id = l.link_id
+			WHERE s.link_id = $1 AND s.email = $2 AND s.enabled AND l.link_status = $3`,
+			linkID, encryptedEmail, linkStatusReady,
+		).Scan(&n)
+		if err != nil {
+			return newSystemError(err)
+		}
+
+		signed = n > 0
+		return nil
+	}
+
+	err = withContext1(f)
+	return signed, err
+}
+
+func (this *client) ListIndividualSigning(linkID, corpEmail, claLang string) ([]dbmodels.IndividualSigningBasicInfo, dbmodels.IDBError) {
+	query := `SELECT s.id, s.email, s.name, s.enabled, s.date, s.lang, s.cla_hash
+		FROM individual_signings s JOIN links l ON s.link_id = l.link_id
+		WHERE s.link_id = $1 AND l.link_status = $2`
+	args := []interface{}{linkID, linkStatusReady}
+
+	if corpEmail != "" {
+		args = append(args, genCorpID(corpEmail))
+		query += fmt.Sprintf(" AND s.corp_id = $%d", len(args))
+	}
+	if claLang != "" {
+		args = append(args, claLang)
+		query += fmt.Sprintf(" AND s.lang = $%d", len(args))
+	}
+
+	var r []dbmodels.IndividualSigningBasicInfo
+	f := func(ctx context.Context) dbmodels.IDBError {
+		rows, err := this.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return newSystemError(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item dbmodels.IndividualSigningBasicInfo
+			email := ""
+
+			err := rows.Scan(
+				&item.ID, &email, &item.Name, &item.Enabled,
+				&item.Date, &item.CLALanguage, &item.CLAHash,
+			)
+			if err != nil {
+				return newSystemError(err)
+			}
+
+			v, err1 := this.encrypt.decryptStr(email)
+			if err1 != nil {
+				return err1
+			}
+			item.Email = v
+
+			r = append(r, item)
+		}
+
+		return toDBError(rows.Err())
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (this *client) GetIndividualSigningDetail(linkID, email string) (*dbmodels.IndividualSigningInfo, dbmodels.IDBError) {
+	encryptedEmail, err := this.encrypt.encryptStr(email)
+	if err != nil {
+		return nil, err
+	}
+
+	var r *dbmodels.IndividualSigningInfo
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		item := dbmodels.IndividualSigningInfo{}
+		var info []byte
+
+		err1 := this.db.QueryRowContext(
+			ctx,
+			`SELECT id, name, enabled, date, lang, cla_hash, info FROM individual_signings
+			WHERE link_id = $1 AND corp_id = $2 AND email = $3`,
+			linkID, genCorpID(email), encryptedEmail,
+		).Scan(
+			&item.ID, &item.Name, &item.Enabled, &item.Date,
+			&item.CLALanguage, &item.CLAHash, &info,
+		)
+		if err1 != nil {
+			if err1 == sql.ErrNoRows {
+				return nil
+			}
+			return newSystemError(err1)
+		}
+
+		si, err := this.encrypt.decryptSigningInfo(info)
+		if err != nil {
+			return err
+		}
+
+		item.Email = email
+		item.Info = *si
+		r = &item
+		return nil
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
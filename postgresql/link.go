@@ -0,0 +1,182 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) GetLinkID(orgRepo *dbmodels.OrgRepo) (string, dbmodels.IDBError) {
+	linkID := ""
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.db.QueryRowContext(
+			ctx,
+			`SELECT link_id FROM links
+			WHERE platform = $1 AND org = $2 AND repo = $3 AND link_status = $4`,
+			orgRepo.Platform, orgRepo.OrgID, orgRepo.RepoID, linkStatusReady,
+		).Scan(&linkID)
+
+		return toDBError(err)
+	}
+
+	if err := withContext1(f); err != nil {
+		return "", err
+	}
+	return linkID, nil
+}
+
+func (this *client) HasLink(linkID string) (bool, dbmodels.IDBError) {
+	var n int
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.db.QueryRowContext(
+			ctx, "SELECT COUNT(1) FROM links WHERE link_id = $1", linkID,
+		).Scan(&n)
+		return toDBError(err)
+	}
+
+	if err := withContext1(f); err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (this *client) CreateLink(info *dbmodels.LinkCreateOption) (string, dbmodels.IDBError) {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.doTransaction(ctx, func(tx *sql.Tx) dbmodels.IDBError {
+			r, err := tx.ExecContext(
+				ctx,
+				`INSERT INTO links (
+					link_id, link_status, platform, org, repo, org_alias, submitter,
+					org_email, org_email_platform, org_email_token
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+				ON CONFLICT DO NOTHING`,
+				info.LinkID, linkStatusReady, info.Platform, info.OrgID, info.RepoID,
+				info.OrgAlias, info.Submitter,
+				info.OrgEmail.Email, info.OrgEmail.Platform, info.OrgEmail.Token,
+			)
+			if err != nil {
+				return newSystemError(err)
+			}
+
+			if n, err := r.RowsAffected(); err != nil {
+				return newSystemError(err)
+			} else if n == 0 {
+				return newDBError(dbmodels.ErrRecordExists, fmt.Errorf("the link exists"))
+			}
+
+			for i := range info.IndividualCLAs {
+				err := insertCLA(ctx, tx, info.LinkID, dbmodels.ApplyToIndividual, &info.IndividualCLAs[i])
+				if err != nil {
+					return err
+				}
+			}
+
+			for i := range info.CorpCLAs {
+				err := insertCLA(ctx, tx, info.LinkID, dbmodels.ApplyToCorporation, &info.CorpCLAs[i])
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	if err := withContext1(f); err != nil {
+		return "", err
+	}
+	return info.LinkID, nil
+}
+
+func (this *client) Unlink(linkID string) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		return this.execOnRecord(
+			ctx, "UPDATE links SET link_status = $1 WHERE link_id = $2",
+			linkStatusDeleted, linkID,
+		)
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) GetOrgOfLink(linkID string) (*dbmodels.OrgInfo, dbmodels.IDBError) {
+	var v []dbmodels.LinkInfo
+	f := func(ctx context.Context) dbmodels.IDBError {
+		r, err := this.getLinks(
+			ctx, "link_id = $1 AND link_status = $2", linkID, linkStatusReady,
+		)
+		v = r
+		return err
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+
+	if len(v) == 0 {
+		return nil, errNoDBRecord
+	}
+	return &v[0].OrgInfo, nil
+}
+
+func (this *client) ListLinks(opt *dbmodels.LinkListOption) ([]dbmodels.LinkInfo, dbmodels.IDBError) {
+	var v []dbmodels.LinkInfo
+	f := func(ctx context.Context) dbmodels.IDBError {
+		r, err := this.getLinks(
+			ctx, "platform = $1 AND org = ANY($2) AND link_status = $3",
+			opt.Platform, pq.Array(opt.Orgs), linkStatusReady,
+		)
+		v = r
+		return err
+	}
+
+	err := withContext1(f)
+	return v, err
+}
+
+func (this *client) GetAllLinks() ([]dbmodels.LinkInfo, dbmodels.IDBError) {
+	var v []dbmodels.LinkInfo
+	f := func(ctx context.Context) dbmodels.IDBError {
+		r, err := this.getLinks(ctx, "link_status = $1", linkStatusReady)
+		v = r
+		return err
+	}
+
+	err := withContext1(f)
+	return v, err
+}
+
+func (this *client) getLinks(ctx context.Context, cond string, args ...interface{}) ([]dbmodels.LinkInfo, dbmodels.IDBError) {
+	rows, err := this.db.QueryContext(
+		ctx,
+		"SELECT link_id, platform, org, repo, org_alias, org_email, submitter FROM links WHERE "+cond,
+		args...,
+	)
+	if err != nil {
+		return nil, newSystemError(err)
+	}
+	defer rows.Close()
+
+	var r []dbmodels.LinkInfo
+	for rows.Next() {
+		var item dbmodels.LinkInfo
+		err := rows.Scan(
+			&item.LinkID, &item.Platform, &item.OrgID, &item.RepoID,
+			&item.OrgAlias, &item.OrgEmail, &item.Submitter,
+		)
+		if err != nil {
+			return nil, newSystemError(err)
+		}
+
+		r = append(r, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, newSystemError(err)
+	}
+	return r, nil
+}
@@ -0,0 +1,301 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+// Each applied migration adds a row to schema_version, and the db
+// which has no row is on the version of 0 which is created by tables.
+const createSchemaVersionTable = `CREATE TABLE IF NOT EXISTS schema_version (
+	version    INT PRIMARY KEY,
+	updated_at BIGINT NOT NULL
+)`
+
+// migration upgrades the schema from version-1 to version.
+type migration struct {
+	version    int
+	desc       string
+	statements []string
+}
+
+// migrations must be sorted by version which increases by 1 each time.
+// Append a new one when the schema is changed, and never change the applied ones.
+var migrations = []migration{
+	{
+		version: 1,
+		desc:    "add the audit logs",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS audit_logs (
+				id          BIGSERIAL PRIMARY KEY,
+				actor       TEXT NOT NULL,
+				remote_addr TEXT NOT NULL DEFAULT '',
+				action      TEXT NOT NULL,
+				link_id     TEXT NOT NULL,
+				corp_id     TEXT NOT NULL DEFAULT '',
+				target      TEXT NOT NULL DEFAULT '',
+				time        BIGINT NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS audit_logs_link
+				ON audit_logs (link_id, corp_id, time)`,
+		},
+	},
+	{
+		version: 2,
+		desc:    "soft delete the individual signings",
+		statements: []string{
+			// The deleted individual signings are moved here, and only the latest one
+			// of each individual is kept until it is restored or purged.
+			`CREATE TABLE IF NOT EXISTS deleted_individual_signings (
+				link_id    TEXT NOT NULL,
+				corp_id    TEXT NOT NULL,
+				email      TEXT NOT NULL,
+				id         TEXT NOT NULL,
+				name       TEXT NOT NULL,
+				date       TEXT NOT NULL,
+				lang       TEXT NOT NULL,
+				enabled    BOOLEAN NOT NULL DEFAULT FALSE,
+				info       BYTEA,
+				deleted_by TEXT NOT NULL,
+				reason     TEXT NOT NULL DEFAULT '',
+				deleted_at BIGINT NOT NULL,
+				PRIMARY KEY (link_id, email)
+			)`,
+			`CREATE INDEX IF NOT EXISTS deleted_individual_signings_deleted_at
+				ON deleted_individual_signings (deleted_at)`,
+		},
+	},
+	{
+		version: 3,
+		desc:    "keep the history of deleting and restoring corporation signings",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS corp_signing_history (
+				id       BIGSERIAL PRIMARY KEY,
+				link_id  TEXT NOT NULL,
+				corp_id  TEXT NOT NULL,
+				action   TEXT NOT NULL,
+				operator TEXT NOT NULL DEFAULT '',
+				time     BIGINT NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS corp_signing_history_corp
+				ON corp_signing_history (link_id, corp_id)`,
+		},
+	},
+	{
+		version: 4,
+		desc:    "version the clas and record the cla hash in the signings",
+		statements: []string{
+			// each row is a version of cla, and the one with the biggest version
+			// is the current one of the language.
+			`ALTER TABLE clas
+				ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 0,
+				ADD COLUMN IF NOT EXISTS policy TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE clas
+				DROP CONSTRAINT IF EXISTS clas_pkey,
+				ADD PRIMARY KEY (link_id, apply_to, lang, version)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS clas_cla_hash
+				ON clas (link_id, apply_to, lang, cla_hash)`,
+
+			`ALTER TABLE cla_infos
+				ADD COLUMN IF NOT EXISTS id BIGSERIAL,
+				DROP CONSTRAINT IF EXISTS cla_infos_pkey`,
+			`ALTER TABLE cla_infos ADD PRIMARY KEY (id)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS cla_infos_cla_hash
+				ON cla_infos (link_id, apply_to, lang, cla_hash)`,
+
+			`ALTER TABLE corp_signings
+				ADD COLUMN IF NOT EXISTS cla_hash TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE individual_signings
+				ADD COLUMN IF NOT EXISTS cla_hash TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE deleted_individual_signings
+				ADD COLUMN IF NOT EXISTS cla_hash TEXT NOT NULL DEFAULT ''`,
+
+			// There was only one cla info of each language before the cla was versioned.
+			`UPDATE corp_signings s SET cla_hash = c.cla_hash FROM cla_infos c
+				WHERE s.cla_hash = '' AND c.link_id = s.link_id
+				AND c.apply_to = 'corporation' AND c.lang = s.lang`,
+			`UPDATE individual_signings s SET cla_hash = c.cla_hash FROM cla_infos c
+				WHERE s.cla_hash = '' AND c.link_id = s.link_id
+				AND c.apply_to = 'individual' AND c.lang = s.lang`,
+			`UPDATE deleted_individual_signings s SET cla_hash = c.cla_hash FROM cla_infos c
+				WHERE s.cla_hash = '' AND c.link_id = s.link_id
+				AND c.apply_to = 'individual' AND c.lang = s.lang`,
+		},
+	},
+	{
+		version: 5,
+		desc:    "add the re-signing campaigns",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS resign_campaigns (
+				id         BIGSERIAL PRIMARY KEY,
+				link_id    TEXT NOT NULL,
+				created_by TEXT NOT NULL DEFAULT '',
+				created_at BIGINT NOT NULL,
+				deadline   BIGINT NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS resign_campaigns_link
+				ON resign_campaigns (link_id)`,
+
+			// the signers asked to sign the new version of cla by the campaigns
+			`CREATE TABLE IF NOT EXISTS resign_campaign_signers (
+				campaign_id BIGINT NOT NULL REFERENCES resign_campaigns (id),
+				link_id     TEXT NOT NULL,
+				email       TEXT NOT NULL,
+				name        TEXT NOT NULL DEFAULT '',
+				type        TEXT NOT NULL,
+				lang        TEXT NOT NULL DEFAULT '',
+				status      TEXT NOT NULL,
+				resigned_at BIGINT NOT NULL DEFAULT 0,
+				PRIMARY KEY (campaign_id, email, type)
+			)`,
+			`CREATE INDEX IF NOT EXISTS resign_campaign_signers_email
+				ON resign_campaign_signers (link_id, email, status)`,
+		},
+	},
+	{
+		version: 6,
+		desc:    "record the drift between cla url and the cla text",
+		statements: []string{
+			`ALTER TABLE clas
+				ADD COLUMN IF NOT EXISTS drift_status TEXT NOT NULL DEFAULT '',
+				ADD COLUMN IF NOT EXISTS drift_checked_at BIGINT NOT NULL DEFAULT 0,
+				ADD COLUMN IF NOT EXISTS drift_url_hash TEXT NOT NULL DEFAULT '',
+				ADD COLUMN IF NOT EXISTS drift_draft TEXT NOT NULL DEFAULT ''`,
+		},
+	},
+	{
+		version: 7,
+		desc:    "add the templates of corporation pdf",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS corp_pdf_templates (
+				link_id     TEXT NOT NULL,
+				lang        TEXT NOT NULL,
+				welcome     TEXT NOT NULL,
+				declaration TEXT NOT NULL,
+				logo        BYTEA,
+				logo_type   TEXT NOT NULL DEFAULT '',
+				PRIMARY KEY (link_id, lang)
+			)`,
+		},
+	},
+	{
+		version: 8,
+		desc:    "let the corporation sign electronically",
+		statements: []string{
+			`ALTER TABLE links
+				ADD COLUMN IF NOT EXISTS corp_signing_policy TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE corp_signings
+				ADD COLUMN IF NOT EXISTS esigned BOOLEAN NOT NULL DEFAULT FALSE`,
+		},
+	},
+	{
+		version: 9,
+		desc:    "add the agreement id of corporation signings",
+		statements: []string{
+			`ALTER TABLE corp_signings
+				ADD COLUMN IF NOT EXISTS agreement_id TEXT NOT NULL DEFAULT ''`,
+			`CREATE INDEX IF NOT EXISTS corp_signings_agreement
+				ON corp_signings (agreement_id)`,
+		},
+	},
+}
+
+func latestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+func (m *migration) String() string {
+	return fmt.Sprintf("%d: %s", m.version, m.desc)
+}
+
+func getSchemaVersion(ctx context.Context, tx *sql.Tx) (int, error) {
+	var v int
+	err := tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&v)
+	return v, err
+}
+
+// Migrate applies the pending migrations in order and returns them. Each migration
+// runs in a transaction which locks schema_version, so the servers starting at
+// the same time will not apply the same migration twice.
+func (this *client) Migrate() ([]string, error) {
+	db := this.db
+
+	if err := withContext(func(ctx context.Context) error {
+		_, err := db.ExecContext(ctx, createSchemaVersionTable)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	var r []string
+	for {
+		var applied *migration
+
+		err := withLongContext(func(ctx context.Context) error {
+			tx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback()
+
+			if _, err := tx.ExecContext(ctx, "LOCK TABLE schema_version IN EXCLUSIVE MODE"); err != nil {
+				return err
+			}
+
+			v, err := getSchemaVersion(ctx, tx)
+			if err != nil {
+				return err
+			}
+
+			if latest := latestSchemaVersion(); v > latest {
+				return fmt.Errorf(
+					"the version of db:%d is newer than the one:%d this server supports", v, latest,
+				)
+			}
+			if v == len(migrations) {
+				return nil
+			}
+
+			item := &migrations[v]
+			for _, s := range item.statements {
+				if _, err := tx.ExecContext(ctx, s); err != nil {
+					return fmt.Errorf("migration %s failed, err:%s", item.String(), err.Error())
+				}
+			}
+
+			_, err = tx.ExecContext(
+				ctx, "INSERT INTO schema_version (version, updated_at) VALUES ($1, $2)",
+				item.version, util.Now(),
+			)
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Commit(); err != nil {
+				return err
+			}
+
+			applied = item
+			return nil
+		})
+		if err != nil {
+			return r, err
+		}
+
+		if applied == nil {
+			return r, nil
+		}
+		r = append(r, applied.String())
+	}
+}
+
+// withLongContext is used by the migrations which may update a lot of rows.
+func withLongContext(f func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	return f(ctx)
+}
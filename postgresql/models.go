@@ -0,0 +1,128 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+)
+
+const (
+	// 'ready' means the link is available currently.
+	// 'deleted' means the link and its signing data are invalid.
+	linkStatusReady   = "ready"
+	linkStatusDeleted = "deleted"
+)
+
+// The emails of signings and managers are encrypted with the fixed nonce,
+// so they can be compared directly in sql.
+// tables is the schema of version 0, and the later changes are the migrations,
+// so never change it.
+var tables = []string{
+	`CREATE TABLE IF NOT EXISTS links (
+		link_id            TEXT PRIMARY KEY,
+		link_status        TEXT NOT NULL,
+		platform           TEXT NOT NULL,
+		org                TEXT NOT NULL,
+		repo               TEXT NOT NULL DEFAULT '',
+		org_alias          TEXT NOT NULL DEFAULT '',
+		submitter          TEXT NOT NULL,
+		org_email          TEXT NOT NULL DEFAULT '',
+		org_email_platform TEXT NOT NULL DEFAULT '',
+		org_email_token    BYTEA
+	)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS links_org_repo
+		ON links (platform, org, repo) WHERE link_status = 'ready'`,
+
+	`CREATE TABLE IF NOT EXISTS clas (
+		link_id        TEXT NOT NULL,
+		apply_to       TEXT NOT NULL,
+		lang           TEXT NOT NULL,
+		url            TEXT NOT NULL,
+		text           TEXT NOT NULL,
+		fields         TEXT NOT NULL DEFAULT '',
+		cla_hash       TEXT NOT NULL,
+		signature_hash TEXT NOT NULL DEFAULT '',
+		org_signature  BYTEA,
+		PRIMARY KEY (link_id, apply_to, lang)
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS cla_infos (
+		link_id        TEXT NOT NULL,
+		apply_to       TEXT NOT NULL,
+		lang           TEXT NOT NULL,
+		cla_hash       TEXT NOT NULL,
+		signature_hash TEXT NOT NULL DEFAULT '',
+		fields         TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (link_id, apply_to, lang)
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS corp_signings (
+		id      BIGSERIAL PRIMARY KEY,
+		link_id TEXT NOT NULL,
+		corp_id TEXT NOT NULL,
+		deleted BOOLEAN NOT NULL DEFAULT FALSE,
+		lang    TEXT NOT NULL,
+		corp    TEXT NOT NULL,
+		email   TEXT NOT NULL,
+		name    TEXT NOT NULL,
+		date    TEXT NOT NULL,
+		info    BYTEA
+	)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS corp_signings_corp
+		ON corp_signings (link_id, corp_id) WHERE NOT deleted`,
+
+	`CREATE TABLE IF NOT EXISTS corp_managers (
+		link_id  TEXT NOT NULL,
+		corp_id  TEXT NOT NULL,
+		id       TEXT NOT NULL,
+		name     TEXT NOT NULL,
+		role     TEXT NOT NULL,
+		email    TEXT NOT NULL,
+		password TEXT NOT NULL,
+		changed  BOOLEAN NOT NULL DEFAULT FALSE
+	)`,
+	`CREATE INDEX IF NOT EXISTS corp_managers_corp
+		ON corp_managers (link_id, corp_id)`,
+	`CREATE INDEX IF NOT EXISTS corp_managers_email
+		ON corp_managers (corp_id, email)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS corp_managers_admin
+		ON corp_managers (link_id, corp_id) WHERE role = 'admin'`,
+
+	`CREATE TABLE IF NOT EXISTS individual_signings (
+		link_id TEXT NOT NULL,
+		corp_id TEXT NOT NULL,
+		email   TEXT NOT NULL,
+		id      TEXT NOT NULL,
+		name    TEXT NOT NULL,
+		date    TEXT NOT NULL,
+		lang    TEXT NOT NULL,
+		enabled BOOLEAN NOT NULL DEFAULT FALSE,
+		info    BYTEA,
+		PRIMARY KEY (link_id, email)
+	)`,
+	`CREATE INDEX IF NOT EXISTS individual_signings_corp
+		ON individual_signings (link_id, corp_id)`,
+
+	`CREATE TABLE IF NOT EXISTS org_emails (
+		email    TEXT PRIMARY KEY,
+		platform TEXT NOT NULL,
+		token    BYTEA
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS verification_codes (
+		email   TEXT NOT NULL,
+		code    TEXT NOT NULL,
+		purpose TEXT NOT NULL,
+		expiry  BIGINT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS verification_codes_email
+		ON verification_codes (email, purpose, code)`,
+}
+
+func createTables(ctx context.Context, db *sql.DB) error {
+	for _, item := range tables {
+		if _, err := db.ExecContext(ctx, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) CreateOrgEmail(opt dbmodels.OrgEmailCreateInfo) dbmodels.IDBError {
+	t, err := this.encrypt.encryptBytes(opt.Token)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		_, err := this.exec(
+			ctx,
+			`INSERT INTO org_emails (email, platform, token) VALUES ($1, $2, $3)
+			ON CONFLICT (email) DO UPDATE SET platform = EXCLUDED.platform, token = EXCLUDED.token`,
+			opt.Email, opt.Platform, t,
+		)
+		return err
+	}
+
+	return withContext1(f)
+}
+
+// GetOrgEmailInfo returns the encrypted token which will be saved in the link
+// directly and decrypted by GetOrgEmailOfLink.
+func (this *client) GetOrgEmailInfo(email string) (*dbmodels.OrgEmailCreateInfo, dbmodels.IDBError) {
+	r := dbmodels.OrgEmailCreateInfo{Email: email}
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.db.QueryRowContext(
+			ctx, "SELECT platform, token FROM org_emails WHERE email = $1", email,
+		).Scan(&r.Platform, &r.Token)
+
+		return toDBError(err)
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (this *client) GetOrgEmailOfLink(linkID string) (*dbmodels.OrgEmailCreateInfo, dbmodels.IDBError) {
+	var r dbmodels.OrgEmailCreateInfo
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.db.QueryRowContext(
+			ctx,
+			`SELECT org_email, org_email_platform, org_email_token FROM links
+			WHERE link_id = $1 AND link_status = $2`,
+			linkID, linkStatusReady,
+		).Scan(&r.Email, &r.Platform, &r.Token)
+
+		return toDBError(err)
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+
+	t, err := this.encrypt.decryptBytes(r.Token)
+	if err != nil {
+		return nil, err
+	}
+	r.Token = t
+
+	return &r, nil
+}
@@ -0,0 +1,149 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/opensourceways/app-cla-server/config"
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+var _ dbmodels.IModel = (*client)(nil)
+
+type client struct {
+	db      *sql.DB
+	encrypt encryption
+}
+
+func Initialize(cfg *config.PostgresqlConfig, encryptionKey, nonce string) (*client, error) {
+	db, err := sql.Open("postgres", cfg.Conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+
+	// verify if database connection is created successfully
+	if err = withContext(db.PingContext); err != nil {
+		return nil, err
+	}
+
+	if err = withContext(func(ctx context.Context) error {
+		return createTables(ctx, db)
+	}); err != nil {
+		return nil, err
+	}
+
+	e, err := newEncryption(encryptionKey, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client{db: db, encrypt: e}, nil
+}
+
+func (this *client) Close() error {
+	return this.db.Close()
+}
+
+func (this *client) doTransaction(ctx context.Context, f func(*sql.Tx) dbmodels.IDBError) dbmodels.IDBError {
+	tx, err := this.db.BeginTx(ctx, nil)
+	if err != nil {
+		return newSystemError(err)
+	}
+
+	if err := f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newSystemError(err)
+	}
+	return nil
+}
+
+func (this *client) exec(ctx context.Context, query string, args ...interface{}) (int64, dbmodels.IDBError) {
+	r, err := this.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, newSystemError(err)
+	}
+
+	n, err := r.RowsAffected()
+	if err != nil {
+		return 0, newSystemError(err)
+	}
+	return n, nil
+}
+
+// execOnRecord executes the query and returns errNoDBRecord if no row is affected.
+func (this *client) execOnRecord(ctx context.Context, query string, args ...interface{}) dbmodels.IDBError {
+	n, err := this.exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return errNoDBRecord
+	}
+	return nil
+}
+
+func (this *client) isLinkReady(ctx context.Context, linkID string) (bool, dbmodels.IDBError) {
+	var n int
+	err := this.db.QueryRowContext(
+		ctx, "SELECT COUNT(1) FROM links WHERE link_id = $1 AND link_status = $2",
+		linkID, linkStatusReady,
+	).Scan(&n)
+	if err != nil {
+		return false, newSystemError(err)
+	}
+	return n > 0, nil
+}
+
+func withContext(f func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return f(ctx)
+}
+
+func withContext1(f func(context.Context) dbmodels.IDBError) dbmodels.IDBError {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return f(ctx)
+}
+
+func genCorpID(email string) string {
+	return util.EmailSuffix(email)
+}
+
+func marshalFields(fields []dbmodels.Field) (string, dbmodels.IDBError) {
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return "", newDBError(dbmodels.ErrMarshalDataFaield, err)
+	}
+	return string(b), nil
+}
+
+func unmarshalFields(s string) ([]dbmodels.Field, dbmodels.IDBError) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var fields []dbmodels.Field
+	if err := json.Unmarshal([]byte(s), &fields); err != nil {
+		return nil, newSystemError(err)
+	}
+	return fields, nil
+}
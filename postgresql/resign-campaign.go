@@ -0,0 +1,162 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/lib/pq"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+)
+
+func (this *client) AddResignCampaign(linkID string, campaign *dbmodels.ResignCampaign) (string, dbmodels.IDBError) {
+	emails := make([]string, 0, len(campaign.Signers))
+	for i := range campaign.Signers {
+		v, err := this.encrypt.encryptStr(campaign.Signers[i].Email)
+		if err != nil {
+			return "", err
+		}
+		emails = append(emails, v)
+	}
+
+	var campaignID int64
+	f := func(ctx context.Context) dbmodels.IDBError {
+		ready, err := this.isLinkReady(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return errNoDBRecord
+		}
+
+		return this.doTransaction(ctx, func(tx *sql.Tx) dbmodels.IDBError {
+			err := tx.QueryRowContext(
+				ctx,
+				`INSERT INTO resign_campaigns (link_id, created_by, created_at, deadline)
+				VALUES ($1, $2, $3, $4) RETURNING id`,
+				linkID, campaign.CreatedBy, campaign.CreatedAt, campaign.Deadline,
+			).Scan(&campaignID)
+			if err != nil {
+				return newSystemError(err)
+			}
+
+			for i := range campaign.Signers {
+				item := &campaign.Signers[i]
+
+				_, err := tx.ExecContext(
+					ctx,
+					`INSERT INTO resign_campaign_signers
+					(campaign_id, link_id, email, name, type, lang, status)
+					VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+					campaignID, linkID, emails[i], item.Name, item.Type,
+					item.CLALanguage, dbmodels.ResignStatusPending,
+				)
+				if err != nil {
+					return newSystemError(err)
+				}
+			}
+			return nil
+		})
+	}
+
+	if err := withContext1(f); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(campaignID, 10), nil
+}
+
+func (this *client) ListResignCampaigns(linkID string) ([]dbmodels.ResignCampaign, dbmodels.IDBError) {
+	var r []dbmodels.ResignCampaign
+	f := func(ctx context.Context) dbmodels.IDBError {
+		rows, err := this.db.QueryContext(
+			ctx,
+			`SELECT c.id, c.created_by, c.created_at, c.deadline,
+				s.email, s.name, s.type, s.lang, s.status, s.resigned_at
+			FROM resign_campaigns c JOIN resign_campaign_signers s ON c.id = s.campaign_id
+			WHERE c.link_id = $1 ORDER BY c.created_at, c.id`,
+			linkID,
+		)
+		if err != nil {
+			return newSystemError(err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var c dbmodels.ResignCampaign
+			var s dbmodels.ResignCampaignSigner
+			var id int64
+
+			err := rows.Scan(
+				&id, &c.CreatedBy, &c.CreatedAt, &c.Deadline,
+				&s.Email, &s.Name, &s.Type, &s.CLALanguage, &s.Status, &s.ResignedAt,
+			)
+			if err != nil {
+				return newSystemError(err)
+			}
+
+			v, err1 := this.encrypt.decryptStr(s.Email)
+			if err1 != nil {
+				return err1
+			}
+			s.Email = v
+
+			c.ID = strconv.FormatInt(id, 10)
+			if n := len(r); n == 0 || r[n-1].ID != c.ID {
+				r = append(r, c)
+			}
+			r[len(r)-1].Signers = append(r[len(r)-1].Signers, s)
+		}
+
+		return toDBError(rows.Err())
+	}
+
+	if err := withContext1(f); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (this *client) ResignInCampaigns(linkID, email string, signerTypes []string, resignedAt int64) dbmodels.IDBError {
+	encryptedEmail, err := this.encrypt.encryptStr(email)
+	if err != nil {
+		return err
+	}
+
+	f := func(ctx context.Context) dbmodels.IDBError {
+		_, err := this.exec(
+			ctx,
+			`UPDATE resign_campaign_signers SET status = $1, resigned_at = $2
+			WHERE link_id = $3 AND email = $4 AND status = $5 AND type = ANY($6)`,
+			dbmodels.ResignStatusResigned, resignedAt, linkID, encryptedEmail,
+			dbmodels.ResignStatusPending, pq.Array(signerTypes),
+		)
+		return err
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) HasOverdueResign(linkID, email string, signerTypes []string, now int64) (bool, dbmodels.IDBError) {
+	encryptedEmail, err := this.encrypt.encryptStr(email)
+	if err != nil {
+		return false, err
+	}
+
+	overdue := false
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.db.QueryRowContext(
+			ctx,
+			`SELECT EXISTS (
+				SELECT 1 FROM resign_campaign_signers s JOIN resign_campaigns c ON c.id = s.campaign_id
+				WHERE s.link_id = $1 AND s.email = $2 AND s.status = $3
+				AND s.type = ANY($4) AND c.deadline < $5
+			)`,
+			linkID, encryptedEmail, dbmodels.ResignStatusPending, pq.Array(signerTypes), now,
+		).Scan(&overdue)
+		return toDBError(err)
+	}
+
+	err = withContext1(f)
+	return overdue, err
+}
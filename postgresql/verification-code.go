@@ -0,0 +1,48 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/util"
+)
+
+func (this *client) CreateVerificationCode(opt dbmodels.VerificationCode) dbmodels.IDBError {
+	f := func(ctx context.Context) dbmodels.IDBError {
+		// delete the expired codes.
+		this.exec(ctx, "DELETE FROM verification_codes WHERE expiry < $1", util.Now())
+
+		// email + purpose can't be the primary key, for example: a corp signs a community concurrently.
+		_, err := this.exec(
+			ctx,
+			"INSERT INTO verification_codes (email, code, purpose, expiry) VALUES ($1, $2, $3, $4)",
+			opt.Email, opt.Code, opt.Purpose, opt.Expiry,
+		)
+		return err
+	}
+
+	return withContext1(f)
+}
+
+func (this *client) GetVerificationCode(opt *dbmodels.VerificationCode) dbmodels.IDBError {
+	var expiry int64
+	f := func(ctx context.Context) dbmodels.IDBError {
+		err := this.db.QueryRowContext(
+			ctx,
+			`DELETE FROM verification_codes WHERE ctid IN (
+				SELECT ctid FROM verification_codes
+				WHERE email = $1 AND purpose = $2 AND code = $3 LIMIT 1
+			) RETURNING expiry`,
+			opt.Email, opt.Purpose, opt.Code,
+		).Scan(&expiry)
+
+		return toDBError(err)
+	}
+
+	if err := withContext1(f); err != nil {
+		return err
+	}
+
+	opt.Expiry = expiry
+	return nil
+}
@@ -80,6 +80,21 @@ func init() {
 				&controllers.VerificationCodeController{},
 			),
 		),
+		beego.NSNamespace("/audit-log",
+			beego.NSInclude(
+				&controllers.AuditLogController{},
+			),
+		),
+		beego.NSNamespace("/resign-campaign",
+			beego.NSInclude(
+				&controllers.ResignCampaignController{},
+			),
+		),
+		beego.NSNamespace("/agreement",
+			beego.NSInclude(
+				&controllers.AgreementController{},
+			),
+		),
 	)
 	beego.AddNamespace(ns)
 }
@@ -0,0 +1,131 @@
+// link-archive exports all the data of a link to an archive file, or imports
+// the link from the archive file. It is used to move a link between environments.
+// The servers should be restarted after importing, so that they can load the clas
+// of the new link.
+//
+// Usage:
+//
+//	link-archive -config ./conf/app.conf.yaml -export <link id> -file ./link.json.gz
+//	link-archive -config ./conf/app.conf.yaml -import -file ./link.json.gz
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/opensourceways/app-cla-server/config"
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/models"
+	"github.com/opensourceways/app-cla-server/mongodb"
+	"github.com/opensourceways/app-cla-server/obs"
+	_ "github.com/opensourceways/app-cla-server/obs/huaweicloud"
+	_ "github.com/opensourceways/app-cla-server/obs/local"
+	_ "github.com/opensourceways/app-cla-server/obs/s3"
+)
+
+func main() {
+	cfgFile := flag.String("config", "./conf/app.conf.yaml", "the config file of cla server")
+	linkID := flag.String("export", "", "the id of link to export")
+	toImport := flag.Bool("import", false, "import the link from the archive file")
+	file := flag.String("file", "", "the archive file")
+	flag.Parse()
+
+	if err := run(*cfgFile, *linkID, *toImport, *file); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func run(cfgFile, linkID string, toImport bool, file string) error {
+	if (linkID == "") == !toImport {
+		return fmt.Errorf("specify one of -export and -import")
+	}
+	if file == "" {
+		return fmt.Errorf("missing the archive file")
+	}
+
+	if err := config.InitAppConfig(cfgFile); err != nil {
+		return err
+	}
+	cfg := config.AppConfig
+
+	keys, keyID := cfg.DBEncryptionKeys()
+	mongoClient, err := mongodb.Initialize(&cfg.Mongodb, keys, keyID)
+	if err != nil {
+		return err
+	}
+	defer mongoClient.Close()
+
+	if err := mongoClient.CheckSchemaVersion(); err != nil {
+		return err
+	}
+
+	var fileStorage dbmodels.IFile = mongoClient
+	if cfg.FileStorage != config.FileStorageGridFS {
+		obsClient, err := obs.Initialize(cfg.OBS)
+		if err != nil {
+			return err
+		}
+		fileStorage = obs.NewFileStorage(obsClient)
+	}
+
+	dbmodels.RegisterDB(struct {
+		dbmodels.IModel
+		dbmodels.IFile
+	}{
+		IModel: mongoClient,
+		IFile:  fileStorage,
+	})
+
+	if toImport {
+		return importLink(file)
+	}
+	return exportLink(linkID, file)
+}
+
+func exportLink(linkID, file string) error {
+	archive, merr := models.ExportLink(linkID)
+	if merr != nil {
+		return merr
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	err = archive.Write(f)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(
+		"link:%s is exported with %d corp signings, %d individual signings and %d pdfs\n",
+		linkID, len(archive.CorpSignings), len(archive.IndividualSignings), len(archive.CorpPDFs),
+	)
+	return nil
+}
+
+func importLink(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	archive, merr := models.ReadLinkArchive(f)
+	if merr != nil {
+		return merr
+	}
+
+	if merr := archive.Import(); merr != nil {
+		return merr
+	}
+
+	fmt.Printf("link:%s of %s is imported\n", archive.LinkID, archive.OrgRepoID())
+	return nil
+}
@@ -0,0 +1,123 @@
+// migrate-corp-pdf moves the corporation signing pdfs between obs and the GridFS of mongodb.
+// The pdfs of the links which are unlinked will not be moved, because they are invalid.
+//
+// Usage:
+//
+//	migrate-corp-pdf -config ./conf/app.conf.yaml -from obs -to gridfs
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/opensourceways/app-cla-server/config"
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/mongodb"
+	"github.com/opensourceways/app-cla-server/obs"
+	_ "github.com/opensourceways/app-cla-server/obs/huaweicloud"
+	_ "github.com/opensourceways/app-cla-server/obs/local"
+	_ "github.com/opensourceways/app-cla-server/obs/s3"
+)
+
+func main() {
+	cfgFile := flag.String("config", "./conf/app.conf.yaml", "the config file of cla server")
+	from := flag.String("from", config.FileStorageOBS, "where to read the pdfs, obs or gridfs")
+	to := flag.String("to", config.FileStorageGridFS, "where to write the pdfs, obs or gridfs")
+	flag.Parse()
+
+	if err := run(*cfgFile, *from, *to); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func run(cfgFile, from, to string) error {
+	if from == to {
+		return fmt.Errorf("the source and the destination are the same")
+	}
+
+	if err := config.InitAppConfig(cfgFile); err != nil {
+		return err
+	}
+	cfg := config.AppConfig
+
+	keys, keyID := cfg.DBEncryptionKeys()
+	mongoClient, err := mongodb.Initialize(&cfg.Mongodb, keys, keyID)
+	if err != nil {
+		return err
+	}
+	defer mongoClient.Close()
+
+	obsClient, err := obs.Initialize(cfg.OBS)
+	if err != nil {
+		return err
+	}
+
+	storages := map[string]dbmodels.IFile{
+		config.FileStorageOBS:    obs.NewFileStorage(obsClient),
+		config.FileStorageGridFS: mongoClient,
+	}
+
+	src, ok := storages[from]
+	if !ok {
+		return fmt.Errorf("unknown source: %s", from)
+	}
+
+	dst, ok := storages[to]
+	if !ok {
+		return fmt.Errorf("unknown destination: %s", to)
+	}
+
+	links, err := mongoClient.GetAllLinks()
+	if err != nil {
+		return err
+	}
+
+	dir, err := ioutil.TempDir("", "corp-pdf")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	total := 0
+	for i := range links {
+		n, err := migrate(src, dst, links[i].LinkID, dir)
+		if err != nil {
+			return fmt.Errorf("migrate pdfs of link:%s failed, err:%s", links[i].LinkID, err.Error())
+		}
+
+		total += n
+	}
+
+	fmt.Printf("%d pdfs of %d links are moved from %s to %s\n", total, len(links), from, to)
+	return nil
+}
+
+func migrate(src, dst dbmodels.IFile, linkID, dir string) (int, error) {
+	corps, err := src.ListCorporationsWithPDFUploaded(linkID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, corpID := range corps {
+		// the corp id is the suffix of email, so it can be used as the email directly.
+		path := filepath.Join(dir, "pdf")
+		if err := src.DownloadCorporationSigningPDF(linkID, corpID, path); err != nil {
+			return 0, err
+		}
+
+		pdf, err := ioutil.ReadFile(path)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := dst.UploadCorporationSigningPDF(linkID, corpID, pdf); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(corps), nil
+}
@@ -0,0 +1,57 @@
+// migrate-db applies the pending migrations of data layout to the mongodb.
+// It is used when skip_migration is true for the server.
+//
+// Usage:
+//
+//	migrate-db -config ./conf/app.conf.yaml -dry-run
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/opensourceways/app-cla-server/config"
+	"github.com/opensourceways/app-cla-server/mongodb"
+)
+
+func main() {
+	cfgFile := flag.String("config", "./conf/app.conf.yaml", "the config file of cla server")
+	dryRun := flag.Bool("dry-run", false, "only list the pending migrations without applying them")
+	flag.Parse()
+
+	if err := run(*cfgFile, *dryRun); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func run(cfgFile string, dryRun bool) error {
+	if err := config.InitAppConfig(cfgFile); err != nil {
+		return err
+	}
+	cfg := config.AppConfig
+
+	keys, keyID := cfg.DBEncryptionKeys()
+	mongoClient, err := mongodb.Initialize(&cfg.Mongodb, keys, keyID)
+	if err != nil {
+		return err
+	}
+	defer mongoClient.Close()
+
+	ms, err := mongoClient.Migrate(dryRun)
+
+	action := "applied"
+	if dryRun {
+		action = "pending"
+	}
+	for _, item := range ms {
+		fmt.Printf("%s %s\n", action, item)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d migrations are %s\n", len(ms), action)
+	return nil
+}
@@ -0,0 +1,56 @@
+// migrate-signings moves the signings and corporation managers which are saved in
+// the arrays of the link docs to the collections which save each of them as a single doc.
+// It is the migration of version 1 which runs at startup or by tools/migrate-db.
+// Rerun it after all the servers of old version are stopped to move the data
+// they wrote during the migration. Then the emails of the data moved are encrypted
+// with random nonces and indexed as the migration of version 3 does.
+//
+// Usage:
+//
+//	migrate-signings -config ./conf/app.conf.yaml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/opensourceways/app-cla-server/config"
+	"github.com/opensourceways/app-cla-server/mongodb"
+)
+
+func main() {
+	cfgFile := flag.String("config", "./conf/app.conf.yaml", "the config file of cla server")
+	flag.Parse()
+
+	if err := run(*cfgFile); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func run(cfgFile string) error {
+	if err := config.InitAppConfig(cfgFile); err != nil {
+		return err
+	}
+	cfg := config.AppConfig
+
+	keys, keyID := cfg.DBEncryptionKeys()
+	mongoClient, err := mongodb.Initialize(&cfg.Mongodb, keys, keyID)
+	if err != nil {
+		return err
+	}
+	defer mongoClient.Close()
+
+	n, err := mongoClient.MigrateSignings()
+	if err != nil {
+		return fmt.Errorf("%d links are migrated before failing, err:%s", n, err.Error())
+	}
+
+	if err := mongoClient.AddEmailIndexes(); err != nil {
+		return fmt.Errorf("failed to add the email indexes, err:%s", err.Error())
+	}
+
+	fmt.Printf("the signings of %d links are migrated\n", n)
+	return nil
+}
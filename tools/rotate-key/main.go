@@ -0,0 +1,57 @@
+// rotate-key re-encrypts all the data saved in mongodb by the key of encryption_key_id.
+// It can run while the servers are working, as long as all of them have the keys
+// of the config. The old key can be removed from the config after it finishes.
+//
+// Usage:
+//
+//	rotate-key -config ./conf/app.conf.yaml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/opensourceways/app-cla-server/config"
+	"github.com/opensourceways/app-cla-server/mongodb"
+)
+
+func main() {
+	cfgFile := flag.String("config", "./conf/app.conf.yaml", "the config file of cla server")
+	flag.Parse()
+
+	if err := run(*cfgFile); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func run(cfgFile string) error {
+	if err := config.InitAppConfig(cfgFile); err != nil {
+		return err
+	}
+	cfg := config.AppConfig
+
+	keys, keyID := cfg.DBEncryptionKeys()
+	mongoClient, err := mongodb.Initialize(&cfg.Mongodb, keys, keyID)
+	if err != nil {
+		return err
+	}
+	defer mongoClient.Close()
+
+	n, skipped, err := mongoClient.RotateKey()
+	if err != nil {
+		return fmt.Errorf("%d docs are re-encrypted before failing, err:%s", n, err.Error())
+	}
+
+	fmt.Printf("%d docs are re-encrypted by the key:%s\n", n, keyID)
+
+	if len(skipped) > 0 {
+		fmt.Printf(
+			"%d pdfs are skipped because they were uploaded during the rotation, run it again to re-encrypt them: %s\n",
+			len(skipped), strings.Join(skipped, ", "),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// the networks which the server must not be made to access by the url of user.
+var privateNetworks = func() []*net.IPNet {
+	cidrs := []string{
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"127.0.0.0/8",
+		"169.254.0.0/16",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"::1/128",
+		"fc00::/7",
+		"fe80::/10",
+	}
+
+	r := make([]*net.IPNet, 0, len(cidrs))
+	for _, item := range cidrs {
+		_, n, err := net.ParseCIDR(item)
+		if err != nil {
+			panic(err)
+		}
+		r = append(r, n)
+	}
+	return r
+}()
+
+// isDeniedIP is replaced in the tests whose servers listen on the loopback.
+var isDeniedIP = isPrivateIP
+
+func isPrivateIP(ip net.IP) bool {
+	if ip.IsUnspecified() || ip.IsLoopback() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+		return true
+	}
+
+	for _, n := range privateNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// permanentError is the error which is the same however many times it is retried,
+// such as the url rejected by DownloadOption.
+type permanentError struct {
+	error
+}
+
+func newPermanentError(format string, a ...interface{}) error {
+	return permanentError{fmt.Errorf(format, a...)}
+}
+
+// IsPermanentDownloadError checks whether the error of Download is useless to retry.
+func IsPermanentDownloadError(err error) bool {
+	var v permanentError
+	return errors.As(err, &v)
+}
+
+// DownloadOption restricts what the url can be downloaded.
+type DownloadOption struct {
+	// AllowedHosts are the hosts which can be accessed, including the ones redirected to.
+	// Any public host can be accessed if it is empty.
+	AllowedHosts []string
+	MaxSize      int
+	MaxRedirects int
+	Timeout      time.Duration
+}
+
+func (opt *DownloadOption) checkURL(u *url.URL) error {
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return newPermanentError("unsupported scheme of url: %s", u.Scheme)
+	}
+
+	if len(opt.AllowedHosts) == 0 {
+		return nil
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, item := range opt.AllowedHosts {
+		if host == strings.ToLower(item) {
+			return nil
+		}
+	}
+	return newPermanentError("the host: %s is not allowed", host)
+}
+
+func (opt *DownloadOption) newClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: opt.Timeout,
+		// the address has been resolved when it is checked, so that
+		// the host can't be resolved to a private ip after the check.
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || isDeniedIP(ip) {
+				return newPermanentError("the address: %s is not allowed", host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: opt.Timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+			TLSHandshakeTimeout: opt.Timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > opt.MaxRedirects {
+				return newPermanentError("too many redirects")
+			}
+			return opt.checkURL(req.URL)
+		},
+	}
+}
+
+// Download gets the content of url which is restricted by opt.
+func Download(rawURL string, opt *DownloadOption) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, permanentError{err}
+	}
+	if err := opt.checkURL(u); err != nil {
+		return nil, err
+	}
+
+	resp, err := opt.newClient().Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("failed to download %s, status code: %d", rawURL, resp.StatusCode)
+		if resp.StatusCode < http.StatusInternalServerError {
+			return nil, permanentError{err}
+		}
+		return nil, err
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, int64(opt.MaxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > opt.MaxSize {
+		return nil, newPermanentError("the content of %s exceeds %d bytes", rawURL, opt.MaxSize)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,174 @@
+package util
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestDownloadOption() *DownloadOption {
+	return &DownloadOption{
+		MaxSize:      64,
+		MaxRedirects: 3,
+		Timeout:      5 * time.Second,
+	}
+}
+
+// denyIPs makes the downloader deny only the ips, so that the servers of tests
+// which listen on the loopback can be accessed. The returned func restores it.
+func denyIPs(ips ...string) func() {
+	old := isDeniedIP
+	isDeniedIP = func(ip net.IP) bool {
+		for _, item := range ips {
+			if ip.Equal(net.ParseIP(item)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func() { isDeniedIP = old }
+}
+
+// newTestServer starts a server listening on the ip.
+func newTestServer(t *testing.T, ip string, h http.HandlerFunc) *httptest.Server {
+	l, err := net.Listen("tcp", ip+":0")
+	if err != nil {
+		t.Skipf("can't listen on %s: %v", ip, err)
+	}
+
+	s := httptest.NewUnstartedServer(h)
+	s.Listener.Close()
+	s.Listener = l
+	s.Start()
+	return s
+}
+
+func expectPermanentError(t *testing.T, err error) {
+	if err == nil {
+		t.Fatal("expect an error")
+	}
+	if !IsPermanentDownloadError(err) {
+		t.Fatalf("expect a permanent error, got %v", err)
+	}
+}
+
+func TestIsPrivateIP(t *testing.T) {
+	cases := map[string]bool{
+		"127.0.0.1":       true,
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.1.1":     true,
+		"169.254.169.254": true,
+		"100.64.0.1":      true,
+		"0.0.0.0":         true,
+		"::1":             true,
+		"fd00::1":         true,
+		"fe80::1":         true,
+		"8.8.8.8":         false,
+		"2001:4860::8888": false,
+	}
+
+	for k, v := range cases {
+		if r := isPrivateIP(net.ParseIP(k)); r != v {
+			t.Errorf("isPrivateIP(%s): expect %v, got %v", k, v, r)
+		}
+	}
+}
+
+func TestDownloadRejectsPrivateIP(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the private server should not be accessed")
+	}))
+	defer s.Close()
+
+	_, err := Download(s.URL, newTestDownloadOption())
+	expectPermanentError(t, err)
+}
+
+func TestDownloadRejectsRedirectToPrivateHost(t *testing.T) {
+	private := newTestServer(t, "127.0.0.2", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the private server should not be accessed")
+	})
+	defer private.Close()
+
+	public := newTestServer(t, "127.0.0.1", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, private.URL, http.StatusFound)
+	})
+	defer public.Close()
+
+	defer denyIPs("127.0.0.2")()
+
+	_, err := Download(public.URL, newTestDownloadOption())
+	expectPermanentError(t, err)
+}
+
+func TestDownloadRejectsRedirectToHostNotAllowed(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the host not allowed should not be accessed")
+	}))
+	defer other.Close()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, strings.Replace(other.URL, "127.0.0.1", "localhost", 1), http.StatusFound)
+	}))
+	defer s.Close()
+
+	defer denyIPs()()
+
+	opt := newTestDownloadOption()
+	opt.AllowedHosts = []string{"127.0.0.1"}
+
+	_, err := Download(s.URL, opt)
+	expectPermanentError(t, err)
+}
+
+func TestDownloadRejectsTooManyRedirects(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.String(), http.StatusFound)
+	}))
+	defer s.Close()
+
+	defer denyIPs()()
+
+	_, err := Download(s.URL, newTestDownloadOption())
+	expectPermanentError(t, err)
+}
+
+func TestDownload(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cla":
+			w.Write([]byte("cla"))
+		case "/big":
+			w.Write([]byte(strings.Repeat("a", 65)))
+		case "/missing":
+			http.NotFound(w, r)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer s.Close()
+
+	defer denyIPs()()
+
+	opt := newTestDownloadOption()
+
+	if v, err := Download(s.URL+"/cla", opt); err != nil || string(v) != "cla" {
+		t.Fatalf("expect cla, got %s, %v", v, err)
+	}
+
+	_, err := Download(s.URL+"/big", opt)
+	expectPermanentError(t, err)
+
+	_, err = Download(s.URL+"/missing", opt)
+	expectPermanentError(t, err)
+
+	// the error of server is transient.
+	if _, err := Download(s.URL+"/unavailable", opt); err == nil || IsPermanentDownloadError(err) {
+		t.Fatalf("expect a transient error, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/astaxie/beego"
+
+	"github.com/opensourceways/app-cla-server/config"
+	"github.com/opensourceways/app-cla-server/dbmodels"
+	"github.com/opensourceways/app-cla-server/email"
+	"github.com/opensourceways/app-cla-server/models"
+)
+
+// StartCheckingCLADrift checks the url of clas of all the links every interval seconds,
+// and notifies the org email of link when the content is found changed. It should run
+// on only one server, otherwise the org email will be notified repeatedly.
+func StartCheckingCLADrift(interval int64, saveDraft bool) {
+	go func() {
+		for {
+			checkCLADrift(saveDraft)
+
+			time.Sleep(time.Second * time.Duration(interval))
+		}
+	}()
+}
+
+func checkCLADrift(saveDraft bool) {
+	links, merr := models.GetAllLinks()
+	if merr != nil {
+		beego.Error(fmt.Sprintf("Failed to list links to check cla drift, err: %s", merr.Error()))
+		return
+	}
+
+	for i := range links {
+		item := &links[i]
+
+		v, merr := models.CheckCLADrift(item.LinkID, saveDraft)
+		if merr != nil {
+			beego.Error(fmt.Sprintf(
+				"Failed to check cla drift of link: %s, err: %s", item.LinkID, merr.Error(),
+			))
+		}
+
+		if len(v) > 0 {
+			notifyCLADrift(item, v)
+		}
+	}
+}
+
+func notifyCLADrift(link *dbmodels.LinkInfo, clas []models.DriftedCLA) {
+	d := email.CLADrift{
+		Org:              link.OrgAlias,
+		ProjectURL:       link.ProjectURL(),
+		URLOfCLAPlatform: config.AppConfig.CLAPlatformURL,
+	}
+	for i := range clas {
+		item := &clas[i]
+
+		d.CLAs = append(d.CLAs, email.CLADriftItem{
+			ApplyTo:  item.ApplyTo,
+			Language: item.Language,
+			URL:      item.URL,
+		})
+		if item.DraftSaved {
+			d.DraftSaved = true
+		}
+	}
+
+	msg, err := d.GenEmailMsg()
+	if err != nil {
+		beego.Error(err)
+		return
+	}
+
+	msg.To = []string{link.OrgEmail}
+	msg.Subject = fmt.Sprintf("The CLA on project of \"%s\" has changed", link.OrgAlias)
+
+	GetEmailWorker().SendSimpleMessage(link.LinkID, msg)
+}
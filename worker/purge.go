@@ -0,0 +1,24 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/astaxie/beego"
+
+	"github.com/opensourceways/app-cla-server/models"
+)
+
+// StartPurgingDeletedSignings purges the deleted signings which are older than
+// retention seconds every hour. It is ok that all the servers do it.
+func StartPurgingDeletedSignings(retention int64) {
+	go func() {
+		for {
+			if err := models.PurgeDeletedIndividualSignings(retention); err != nil {
+				beego.Error(fmt.Sprintf("Failed to purge the deleted signings, err: %s", err.Error()))
+			}
+
+			time.Sleep(time.Hour)
+		}
+	}()
+}
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 	"sync"
@@ -19,6 +20,7 @@ var worker IEmailWorker
 
 type IEmailWorker interface {
 	GenCLAPDFForCorporationAndSendIt(string, string, string, models.OrgInfo, models.CorporationSigning, []models.CLAField)
+	GenESignedCLAPDFForCorporationAndSendIt(string, string, string, models.OrgInfo, models.CorporationSigning, []models.CLAField, models.CorpESignature, bool)
 	SendSimpleMessage(string, *email.EmailMessage)
 }
 
@@ -44,32 +46,68 @@ func (this *emailWorker) Shutdown() {
 }
 
 func (this *emailWorker) GenCLAPDFForCorporationAndSendIt(linkID, orgSignatureFile, claFile string, orgInfo models.OrgInfo, signing models.CorporationSigning, claFields []models.CLAField) {
+	this.genCorpPDFAndSendIt(linkID, orgSignatureFile, claFile, orgInfo, signing, claFields, nil, true)
+}
+
+// GenESignedCLAPDFForCorporationAndSendIt saves the pdf signed electronically by
+// the corporation before sending it, so that it is saved even if the email fails.
+func (this *emailWorker) GenESignedCLAPDFForCorporationAndSendIt(linkID, orgSignatureFile, claFile string, orgInfo models.OrgInfo, signing models.CorporationSigning, claFields []models.CLAField, esig models.CorpESignature, scannedPDFRequired bool) {
+	this.genCorpPDFAndSendIt(linkID, orgSignatureFile, claFile, orgInfo, signing, claFields, &esig, scannedPDFRequired)
+}
+
+func (this *emailWorker) genCorpPDFAndSendIt(linkID, orgSignatureFile, claFile string, orgInfo models.OrgInfo, signing models.CorporationSigning, claFields []models.CLAField, esig *models.CorpESignature, scannedPDFRequired bool) {
 	f := func() {
 		defer func() {
 			this.wg.Done()
 		}()
 
+		file := ""
+
+		defer func() {
+			if !util.IsFileNotExist(file) {
+				os.Remove(file)
+			}
+		}()
+
+		genPDF := func() (err error) {
+			if file != "" && !util.IsFileNotExist(file) {
+				return nil
+			}
+
+			if esig == nil {
+				file, err = this.pdfGenerator.GenPDFForCorporationSigning(linkID, orgSignatureFile, claFile, &orgInfo, &signing, claFields)
+			} else {
+				file, err = this.pdfGenerator.GenESignedPDFForCorporationSigning(linkID, orgSignatureFile, claFile, &orgInfo, &signing, claFields, esig)
+			}
+			if err != nil {
+				return fmt.Errorf(
+					"Failed to generate pdf for corp signing(%s:%s:%s/%s): %s",
+					orgInfo.Platform, orgInfo.OrgID, orgInfo.RepoID, util.EmailSuffix(signing.AdminEmail),
+					err.Error())
+			}
+			return nil
+		}
+
+		if esig != nil && !this.saveESignedPDF(linkID, signing.AdminEmail, genPDF, &file) {
+			return
+		}
+
 		emailCfg, ec, err := getEmailClient(linkID)
 		if err != nil {
 			return
 		}
 
 		data := email.CorporationSigning{
-			Org:         orgInfo.OrgAlias,
-			Date:        signing.Date,
-			AdminName:   signing.AdminName,
-			ProjectURL:  orgInfo.ProjectURL(),
-			SigningInfo: buildCorpSigningInfo(&signing, claFields),
+			Org:                orgInfo.OrgAlias,
+			Date:               signing.Date,
+			AdminName:          signing.AdminName,
+			ProjectURL:         orgInfo.ProjectURL(),
+			SigningInfo:        buildCorpSigningInfo(&signing, claFields),
+			ESigned:            esig != nil,
+			ScannedPDFRequired: scannedPDFRequired,
 		}
 
 		var msg *email.EmailMessage
-		file := ""
-
-		defer func() {
-			if !util.IsFileNotExist(file) {
-				os.Remove(file)
-			}
-		}()
 
 		for i := 0; i < 10; i++ {
 			if this.shutdown {
@@ -88,15 +126,9 @@ func (this *emailWorker) GenCLAPDFForCorporationAndSendIt(linkID, orgSignatureFi
 				msg.To = []string{signing.AdminEmail}
 			}
 
-			if file == "" || util.IsFileNotExist(file) {
-				file, err = this.pdfGenerator.GenPDFForCorporationSigning(linkID, orgSignatureFile, claFile, &orgInfo, &signing, claFields)
-				if err != nil {
-					next(fmt.Errorf(
-						"Failed to generate pdf for corp signing(%s:%s:%s/%s): %s",
-						orgInfo.Platform, orgInfo.OrgID, orgInfo.RepoID, util.EmailSuffix(signing.AdminEmail),
-						err.Error()))
-					continue
-				}
+			if err := genPDF(); err != nil {
+				next(err)
+				continue
 			}
 			msg.Attachment = file
 
@@ -112,6 +144,38 @@ func (this *emailWorker) GenCLAPDFForCorporationAndSendIt(linkID, orgSignatureFi
 	go f()
 }
 
+func (this *emailWorker) saveESignedPDF(linkID, adminEmail string, genPDF func() error, file *string) bool {
+	for i := 0; i < 10; i++ {
+		if this.shutdown {
+			beego.Info("email worker exits forcedly")
+			return false
+		}
+
+		if err := genPDF(); err != nil {
+			next(err)
+			continue
+		}
+
+		data, err := ioutil.ReadFile(*file)
+		if err != nil {
+			next(err)
+			continue
+		}
+
+		if merr := models.SaveESignedCorpSigningPDF(linkID, adminEmail, data); merr != nil {
+			next(fmt.Errorf(
+				"Failed to save the esigned pdf of corp signing(%s/%s): %s",
+				linkID, util.EmailSuffix(adminEmail), merr.Error(),
+			))
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
 func (this *emailWorker) SendSimpleMessage(linkID string, msg *email.EmailMessage) {
 	f := func() {
 		defer func() {